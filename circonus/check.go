@@ -1,20 +1,24 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"net/http"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // The circonusCheck type is the backing store of the `circonus_check` resource.
 
 type circonusCheck struct {
 	api.CheckBundle
-}
 
-type circonusCheckType string
+	// certExpiry is the cert_expiry block of an http or tcp check.  It is
+	// managed by the provider and isn't part of the check bundle.
+	certExpiry *checkCertExpiry
+}
 
 const (
 	// CheckBundle.Status can be one of these values.
@@ -22,37 +26,19 @@ const (
 	checkStatusDisabled = "disabled"
 )
 
-const (
-	apiCheckTypeCAQL       circonusCheckType = "caql"
-	apiCheckTypeConsul     circonusCheckType = "consul"
-	apiCheckTypeDNS        circonusCheckType = "dns"
-	apiCheckTypeICMPPing   circonusCheckType = "ping_icmp"
-	apiCheckTypeExternal   circonusCheckType = "external"
-	apiCheckTypeHTTP       circonusCheckType = "http"
-	apiCheckTypeJMX        circonusCheckType = "jmx"
-	apiCheckTypeMemcached  circonusCheckType = "memcached"
-	apiCheckTypeJSON       circonusCheckType = "json"
-	apiCheckTypeMySQL      circonusCheckType = "mysql"
-	apiCheckTypeNTP        circonusCheckType = "ntp"
-	apiCheckTypeRedis      circonusCheckType = "redis"
-	apiCheckTypeSMTP       circonusCheckType = "smtp"
-	apiCheckTypeSNMP       circonusCheckType = "snmp"
-	apiCheckTypeSSH2       circonusCheckType = "ssh2"
-	apiCheckTypeStatsd     circonusCheckType = "statsd"
-	apiCheckTypePostgreSQL circonusCheckType = "postgres"
-	apiCheckTypePromText   circonusCheckType = "promtext"
-	apiCheckTypeTCP        circonusCheckType = "tcp"
-)
-
 func newCheck() circonusCheck {
 	return circonusCheck{
 		CheckBundle: *api.NewCheckBundle(),
 	}
 }
 
-func loadCheck(ctxt *providerContext, cid api.CIDType) (circonusCheck, error) {
+func loadCheck(ctx context.Context, ctxt *providerContext, cid api.CIDType) (circonusCheck, error) {
 	var c circonusCheck
-	cb, err := ctxt.client.FetchCheckBundle(cid)
+	var cb *api.CheckBundle
+	err := ctxt.apiRequest(ctx, http.MethodGet, *cid, func(client *api.API) (err error) {
+		cb, err = client.FetchCheckBundle(cid)
+		return err
+	})
 	if err != nil {
 		return circonusCheck{}, err
 	}
@@ -61,7 +47,7 @@ func loadCheck(ctxt *providerContext, cid api.CIDType) (circonusCheck, error) {
 	return c, nil
 }
 
-func checkAPIStatusToBool(s string) bool {
+func checkAPIStatusToBool(ctx context.Context, s string) bool {
 	var active bool
 	switch s {
 	case checkStatusActive:
@@ -69,7 +55,7 @@ func checkAPIStatusToBool(s string) bool {
 	case checkStatusDisabled:
 		active = false
 	default:
-		log.Printf("[ERROR] PROVIDER BUG: check status %q unsupported", s)
+		tflog.Error(ctx, "PROVIDER BUG: check status unsupported", map[string]interface{}{"status": s})
 	}
 
 	return active
@@ -83,23 +69,34 @@ func checkActiveToAPIStatus(active bool) string {
 	return checkStatusDisabled
 }
 
-func (c *circonusCheck) Create(ctxt *providerContext) error {
-	cb, err := ctxt.client.CreateCheckBundle(&c.CheckBundle)
+func (c *circonusCheck) Create(ctx context.Context, ctxt *providerContext) error {
+	var cb *api.CheckBundle
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.CheckBundlePrefix, func(client *api.API) (err error) {
+		cb, err = client.CreateCheckBundle(&c.CheckBundle)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	c.CID = cb.CID
+	c.Checks = cb.Checks
 
 	return nil
 }
 
-func (c *circonusCheck) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateCheckBundle(&c.CheckBundle)
+func (c *circonusCheck) Update(ctx context.Context, ctxt *providerContext) error {
+	var cb *api.CheckBundle
+	err := ctxt.apiRequest(ctx, http.MethodPut, c.CID, func(client *api.API) (err error) {
+		cb, err = client.UpdateCheckBundle(&c.CheckBundle)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update check bundle %s: %w", c.CID, err)
 	}
 
+	c.Checks = cb.Checks
+
 	return nil
 }
 
@@ -119,27 +116,20 @@ func (c *circonusCheck) Fixup() error {
 func (c *circonusCheck) Validate() error {
 	// there must be at least 1 metric or at least 1 metric_filter but only one of the lists can contain members.
 	if len(c.Metrics) > 0 && len(c.MetricFilters) > 0 {
-		return fmt.Errorf("Metrics and MetricFilters both have entries, you can only have one or the other")
+		return attrErrorf(attrPath(checkMetricFilterAttr), "Metrics and MetricFilters both have entries, you can only have one or the other")
 	}
 
 	if len(c.Metrics) == 0 && len(c.MetricFilters) == 0 {
-		return fmt.Errorf("You must supply one or more 'metric' blocks *or* one or more 'metric_filter' blocks")
+		return attrErrorf(attrPath(checkMetricAttr), "You must supply one or more 'metric' blocks *or* one or more 'metric_filter' blocks")
 	}
 
 	if c.Timeout > float32(c.Period) {
-		return fmt.Errorf("Timeout (%f) can not exceed period (%d)", c.Timeout, c.Period)
+		return attrErrorf(attrPath(checkTimeoutAttr), "Timeout (%f) can not exceed period (%d)", c.Timeout, c.Period)
 	}
 
 	// Check-type specific validation
-	switch apiCheckType(c.Type) {
-	case apiCheckTypeCloudWatchAttr:
-		if !(c.Period == 60 || c.Period == 300) {
-			return fmt.Errorf("Period must be either 1m or 5m for a %s check", apiCheckTypeCloudWatchAttr)
-		}
-	case apiCheckTypeConsulAttr:
-		if v, found := c.Config[config.URL]; !found || v == "" {
-			return fmt.Errorf("%s must have at least one check mode set: %s, %s, or %s must be set", checkConsulAttr, checkConsulServiceAttr, checkConsulNodeAttr, checkConsulStateAttr)
-		}
+	if t, found := checkTypeForAPIType(c.Type); found && t.validate != nil {
+		return t.validate(c)
 	}
 
 	return nil
@@ -0,0 +1,341 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.collector_selector.* resource attribute names.
+	checkCollectorSelectorMinCountAttr = "min_count"
+	checkCollectorSelectorRegionAttr   = "region"
+	checkCollectorSelectorTagsAttr     = "tags"
+	checkCollectorSelectorTypeAttr     = "type"
+
+	// Collectors are assigned to a region with a tag of this category.
+	collectorRegionTagCategory = "region"
+
+	collectorStatusActive = "active"
+
+	defaultCheckCollectorSelectorMinCount = 1
+)
+
+var checkCollectorSelectorDescriptions = attrDescrs{
+	checkCollectorSelectorMinCountAttr: "The number of collectors to run the check on",
+	checkCollectorSelectorRegionAttr:   "Only select collectors tagged with this region",
+	checkCollectorSelectorTagsAttr:     "Only select collectors with all of these tags",
+	checkCollectorSelectorTypeAttr:     "Only select collectors of this type: circonus (public) or enterprise",
+}
+
+var validCollectorTypes = validStringValues{
+	`circonus`,
+	`enterprise`,
+}
+
+// collectorSelector is the collector_selector block of a check.
+type collectorSelector struct {
+	MinCount int
+	Region   string
+	Tags     []string
+	Type     string
+}
+
+func schemaCheckCollectorSelector() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkCollectorSelectorDescriptions, map[schemaAttr]*schema.Schema{
+				checkCollectorSelectorMinCountAttr: {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      defaultCheckCollectorSelectorMinCount,
+					ValidateFunc: validateIntMin(checkCollectorSelectorMinCountAttr, 1),
+				},
+				checkCollectorSelectorRegionAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkCollectorSelectorRegionAttr, `^\S+$`),
+				},
+				checkCollectorSelectorTagsAttr: tagMakeConfigSchema(checkCollectorSelectorTagsAttr),
+				checkCollectorSelectorTypeAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringIn(checkCollectorSelectorTypeAttr, validCollectorTypes),
+				},
+			}),
+		},
+	}
+}
+
+// checkCollectorSelectorFromConfig returns the check's collector_selector
+// block, if it has one.
+func checkCollectorSelectorFromConfig(d checkConfigReader) (collectorSelector, bool) {
+	var sel collectorSelector
+
+	v, found := d.GetOk(checkCollectorSelectorAttr)
+	if !found {
+		return sel, false
+	}
+
+	l, _ := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return sel, false
+	}
+
+	m := newInterfaceMap(l[0])
+	sel.MinCount, _ = m[checkCollectorSelectorMinCountAttr].(int)
+	if sel.MinCount == 0 {
+		sel.MinCount = defaultCheckCollectorSelectorMinCount
+	}
+	sel.Region, _ = m[checkCollectorSelectorRegionAttr].(string)
+	sel.Type, _ = m[checkCollectorSelectorTypeAttr].(string)
+	if tags, ok := m[checkCollectorSelectorTagsAttr].(*schema.Set); ok {
+		sel.Tags = derefStringList(flattenSet(tags))
+		sort.Strings(sel.Tags)
+	}
+
+	return sel, true
+}
+
+// Matches reports whether the collector b is active and satisfies every
+// criteria of the selector.
+func (sel collectorSelector) Matches(b api.Broker) bool {
+	if sel.Type != "" && b.Type != sel.Type {
+		return false
+	}
+
+	tags := make(map[string]struct{}, len(b.Tags))
+	for _, tag := range b.Tags {
+		tags[tag] = struct{}{}
+	}
+
+	required := sel.Tags
+	if sel.Region != "" {
+		required = append(append([]string(nil), required...), collectorRegionTagCategory+":"+sel.Region)
+	}
+
+	for _, tag := range required {
+		if _, found := tags[tag]; !found {
+			return false
+		}
+	}
+
+	for _, detail := range b.Details {
+		if detail.Status == collectorStatusActive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Select returns the CIDs of MinCount collectors out of brokers.  The
+// collectors in current that still match are kept so the selection doesn't
+// change from one plan to the next, the rest are picked in CID order.
+func (sel collectorSelector) Select(brokers []api.Broker, current []string) ([]string, error) {
+	matching := make(map[string]struct{}, len(brokers))
+	candidates := make([]string, 0, len(brokers))
+	for _, b := range brokers {
+		if !sel.Matches(b) {
+			continue
+		}
+
+		if _, found := matching[b.CID]; found {
+			continue
+		}
+		matching[b.CID] = struct{}{}
+		candidates = append(candidates, b.CID)
+	}
+
+	if len(candidates) < sel.MinCount {
+		return nil, attrErrorf(attrPath(checkCollectorSelectorAttr), "%d active collectors match, %s requires %d", len(candidates), checkCollectorSelectorMinCountAttr, sel.MinCount)
+	}
+
+	selected := make([]string, 0, sel.MinCount)
+	chosen := make(map[string]struct{}, sel.MinCount)
+	for _, cid := range current {
+		if len(selected) == sel.MinCount {
+			break
+		}
+
+		if _, found := matching[cid]; found {
+			selected = append(selected, cid)
+			chosen[cid] = struct{}{}
+		}
+	}
+
+	sort.Strings(candidates)
+	for _, cid := range candidates {
+		if len(selected) == sel.MinCount {
+			break
+		}
+
+		if _, found := chosen[cid]; !found {
+			selected = append(selected, cid)
+			chosen[cid] = struct{}{}
+		}
+	}
+
+	sort.Strings(selected)
+
+	return selected, nil
+}
+
+// searchCollectors returns the collectors that may satisfy sel.  The search
+// narrows the list down by type and tags, Select applies the full criteria.
+func searchCollectors(ctx context.Context, ctxt *providerContext, sel collectorSelector) ([]api.Broker, error) {
+	filter := api.SearchFilterType{}
+	if sel.Type != "" {
+		filter["f__type"] = []string{sel.Type}
+	}
+
+	tags := append([]string(nil), sel.Tags...)
+	if sel.Region != "" {
+		tags = append(tags, collectorRegionTagCategory+":"+sel.Region)
+	}
+	if len(tags) > 0 {
+		filter["f__tags_has"] = tags
+	}
+
+	var brokers *[]api.Broker
+	err := ctxt.apiRequest(ctx, http.MethodGet, config.BrokerPrefix, func(client *api.API) (err error) {
+		brokers, err = client.SearchBrokers(nil, &filter)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to search collectors: %w", err)
+	}
+
+	if brokers == nil {
+		return nil, nil
+	}
+
+	return *brokers, nil
+}
+
+// resolveCheckCollectors resolves the collector_selector of d, keeping the
+// collectors in current where possible.  It returns nil when d has no
+// selector.
+func resolveCheckCollectors(ctx context.Context, ctxt *providerContext, d checkConfigReader, current []string) ([]string, error) {
+	sel, found := checkCollectorSelectorFromConfig(d)
+	if !found {
+		return nil, nil
+	}
+
+	brokers, err := searchCollectors(ctx, ctxt, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	return sel.Select(brokers, current)
+}
+
+// checkCollectorIDs returns the collector IDs of a collector attribute value.
+func checkCollectorIDs(v interface{}) []string {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+
+	return interfaceList(set.List()).CollectList(checkCollectorIDAttr)
+}
+
+// checkCustomizeDiffCollectors plans the collectors chosen by the check's
+// collector_selector.  The collectors in the state are kept as long as they
+// still match, so the plan only changes when the selector or the collectors
+// available change.
+func checkCustomizeDiffCollectors(ctx context.Context, d *schema.ResourceDiff, meta interface{}, known func(attrs ...string) bool) error {
+	if _, found := checkCollectorSelectorFromConfig(d); !found {
+		return nil
+	}
+
+	// collector is computed from the selector, so only the raw config tells
+	// whether it is configured as well.  Without one, as when the diff isn't
+	// coming from Terraform, a new check's collectors can only be configured.
+	configured := false
+	switch rawConfig := d.GetRawConfig(); {
+	case rawConfig.IsNull():
+		_, found := d.GetOk(checkCollectorAttr)
+		configured = found && d.Id() == ""
+	case rawConfig.IsKnown():
+		collectors := rawConfig.GetAttr(checkCollectorAttr)
+		configured = !collectors.IsNull() && (!collectors.IsKnown() || collectors.LengthInt() > 0)
+	}
+
+	if configured {
+		return attrErrorf(attrPath(checkCollectorSelectorAttr), "only one of %s or %s may be set", checkCollectorAttr, checkCollectorSelectorAttr)
+	}
+
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt.client == nil || !known(checkCollectorSelectorAttr) {
+		return d.SetNewComputed(checkCollectorAttr)
+	}
+
+	o, _ := d.GetChange(checkCollectorAttr)
+	current := checkCollectorIDs(o)
+
+	selected, err := resolveCheckCollectors(ctx, ctxt, d, current)
+	if err != nil {
+		return err
+	}
+
+	if stringSlicesEqual(selected, current) {
+		return nil
+	}
+
+	for _, attr := range []string{checkOutByCollectorAttr, checkOutChecksAttr, checkOutCheckUUIDsAttr, checkOutIDAttr, checkOutStatusAttr} {
+		if err := d.SetNewComputed(attr); err != nil {
+			return err
+		}
+	}
+
+	return d.SetNew(checkCollectorAttr, stringListToSet(selected, checkCollectorIDAttr))
+}
+
+// resolveCollectors sets the check's brokers from its collector_selector when
+// they weren't known at plan time.
+func (c *circonusCheck) resolveCollectors(ctx context.Context, ctxt *providerContext, d *schema.ResourceData) error {
+	if len(c.Brokers) > 0 {
+		return nil
+	}
+
+	o, _ := d.GetChange(checkCollectorAttr)
+	brokers, err := resolveCheckCollectors(ctx, ctxt, d, checkCollectorIDs(o))
+	if err != nil {
+		return err
+	}
+
+	if brokers != nil {
+		c.Brokers = brokers
+	}
+
+	return nil
+}
+
+// stringSlicesEqual reports whether a and b hold the same strings, in any
+// order.
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+)
+
+func Test_CollectorSelectorSelect(t *testing.T) {
+	broker := func(cid, brokerType, status string, tags ...string) api.Broker {
+		return api.Broker{
+			CID:     cid,
+			Type:    brokerType,
+			Tags:    tags,
+			Details: []api.BrokerDetail{{Status: status}},
+		}
+	}
+
+	brokers := []api.Broker{
+		broker("/broker/4", "circonus", "active", "region:us-east-1", "provider:aws"),
+		broker("/broker/1", "circonus", "active", "region:us-east-1", "provider:aws"),
+		broker("/broker/2", "circonus", "active", "region:us-east-1", "provider:gcp"),
+		broker("/broker/3", "circonus", "unprovisioned", "region:us-east-1", "provider:aws"),
+		broker("/broker/5", "enterprise", "active", "region:us-east-1", "provider:aws"),
+		broker("/broker/6", "circonus", "active", "region:eu-west-1", "provider:aws"),
+	}
+
+	tests := []struct {
+		name     string
+		selector collectorSelector
+		current  []string
+		expected []string
+		fail     bool
+	}{
+		{
+			name:     "first in CID order",
+			selector: collectorSelector{MinCount: 2, Region: "us-east-1", Type: "circonus"},
+			expected: []string{"/broker/1", "/broker/2"},
+		},
+		{
+			name:     "tags",
+			selector: collectorSelector{MinCount: 2, Region: "us-east-1", Tags: []string{"provider:aws"}, Type: "circonus"},
+			expected: []string{"/broker/1", "/broker/4"},
+		},
+		{
+			name:     "current collectors are kept",
+			selector: collectorSelector{MinCount: 2, Region: "us-east-1", Type: "circonus"},
+			current:  []string{"/broker/4", "/broker/2"},
+			expected: []string{"/broker/2", "/broker/4"},
+		},
+		{
+			name:     "collectors that no longer match are replaced",
+			selector: collectorSelector{MinCount: 2, Region: "us-east-1", Type: "circonus"},
+			current:  []string{"/broker/3", "/broker/4"},
+			expected: []string{"/broker/1", "/broker/4"},
+		},
+		{
+			name:     "current collectors beyond min_count are dropped",
+			selector: collectorSelector{MinCount: 1},
+			current:  []string{"/broker/1", "/broker/2", "/broker/4"},
+			expected: []string{"/broker/1"},
+		},
+		{
+			name:     "not enough collectors",
+			selector: collectorSelector{MinCount: 2, Region: "eu-west-1"},
+			fail:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected, err := test.selector.Select(brokers, test.current)
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(selected, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, selected)
+			}
+		})
+	}
+}
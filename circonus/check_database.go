@@ -0,0 +1,318 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The database check types (mongodb, mysql, postgresql and redis) share their
+// TLS options, and the SQL check types share their named queries.  The helpers
+// in this file are used by each of those check types' files.
+
+const (
+	// circonus_check.{mongodb,mysql,postgresql,redis}.* shared resource attribute names.
+	checkDatabaseNamedQueryAttr = "named_query"
+	checkDatabasePasswordAttr   = "password"
+	checkDatabaseTLSAttr        = "tls"
+
+	// circonus_check.{mysql,postgresql}.named_query.* resource attribute names.
+	checkDatabaseNamedQueryNameAttr = "name"
+	checkDatabaseNamedQuerySQLAttr  = "sql"
+
+	// circonus_check.{mongodb,mysql,postgresql,redis}.tls.* resource attribute names.
+	checkDatabaseTLSCAChainAttr    = "ca_chain"
+	checkDatabaseTLSCertFileAttr   = "certificate_file"
+	checkDatabaseTLSKeyFileAttr    = "key_file"
+	checkDatabaseTLSVerifyModeAttr = "verify_mode"
+
+	// apiDatabaseNamedQueryPrefix prefixes the name of each query of a multi
+	// query database check in the check bundle's config.  Metrics are returned
+	// prefixed with the query's name.
+	apiDatabaseNamedQueryPrefix = "sql_"
+
+	// apiDatabaseTLSVerifyMode is how the broker verifies the server's
+	// certificate.
+	apiDatabaseTLSVerifyMode = config.Key("tls_verify_mode")
+
+	defaultCheckDatabaseVerifyMode = checkTLSVerifyModeFull
+)
+
+var checkDatabaseNamedQueryDescriptions = attrDescrs{
+	checkDatabaseNamedQueryNameAttr: "The name of the query, used as the prefix of its metrics",
+	checkDatabaseNamedQuerySQLAttr:  "The SQL to use as the query",
+}
+
+var checkDatabaseTLSDescriptions = attrDescrs{
+	checkDatabaseTLSCAChainAttr:    "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate",
+	checkDatabaseTLSCertFileAttr:   "A path to a file containing the client certificate that will be presented to the remote server",
+	checkDatabaseTLSKeyFileAttr:    "A path to a file containing key to be used in conjunction with the client certificate",
+	checkDatabaseTLSVerifyModeAttr: "How the server's certificate is verified: none, verify-ca or verify-full",
+}
+
+// schemaCheckDatabaseNamedQuery returns the schema of the named_query blocks of
+// the SQL check types.
+func schemaCheckDatabaseNamedQuery() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkDatabaseNamedQueryDescriptions, map[schemaAttr]*schema.Schema{
+				checkDatabaseNamedQueryNameAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateRegexp(checkDatabaseNamedQueryNameAttr, `^[\w-]+$`),
+				},
+				checkDatabaseNamedQuerySQLAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					StateFunc:    suppressWhitespace,
+					ValidateFunc: validateRegexp(checkDatabaseNamedQuerySQLAttr, `.+`),
+				},
+			}),
+		},
+	}
+}
+
+// schemaCheckDatabasePassword returns the schema of the password attribute of
+// the database check types.
+func schemaCheckDatabasePassword() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		ValidateFunc: validateRegexp(checkDatabasePasswordAttr, `.+`),
+	}
+}
+
+// schemaCheckDatabaseTLS returns the schema of the tls block of the database
+// check types.  Connections use TLS when the block is present.
+func schemaCheckDatabaseTLS() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkDatabaseTLSDescriptions, map[schemaAttr]*schema.Schema{
+				checkDatabaseTLSCAChainAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkDatabaseTLSCAChainAttr, `.+`),
+				},
+				checkDatabaseTLSCertFileAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkDatabaseTLSCertFileAttr, `.+`),
+				},
+				checkDatabaseTLSKeyFileAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkDatabaseTLSKeyFileAttr, `.+`),
+				},
+				checkDatabaseTLSVerifyModeAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultCheckDatabaseVerifyMode,
+					ValidateFunc: validateStringIn(checkDatabaseTLSVerifyModeAttr, validCheckTLSVerifyModes),
+				},
+			}),
+		},
+	}
+}
+
+// checkDatabaseNamedQueries returns the named_query blocks of a database check
+// type's block sorted by name.  v is either the *schema.Set read from the
+// config or the list built when reading the check bundle.
+func checkDatabaseNamedQueries(v interface{}) []map[string]interface{} {
+	var l []interface{}
+	switch queries := v.(type) {
+	case *schema.Set:
+		l = queries.List()
+	case []interface{}:
+		l = queries
+	}
+
+	queries := make([]map[string]interface{}, 0, len(l))
+	for _, queryRaw := range l {
+		if query, ok := queryRaw.(map[string]interface{}); ok {
+			queries = append(queries, query)
+		}
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i][string(checkDatabaseNamedQueryNameAttr)].(string) < queries[j][string(checkDatabaseNamedQueryNameAttr)].(string)
+	})
+
+	return queries
+}
+
+// checkDatabaseConfigToAPI copies the shared password, tls and named_query
+// attributes of a database check type's block into the check bundle.
+func checkDatabaseConfigToAPI(c *circonusCheck, dbConfig interfaceMap) {
+	if v, found := dbConfig[checkDatabasePasswordAttr]; found && v.(string) != "" {
+		c.Config[config.Password] = v.(string)
+	}
+
+	if v, found := dbConfig[checkDatabaseNamedQueryAttr]; found {
+		for _, query := range checkDatabaseNamedQueries(v) {
+			name := query[string(checkDatabaseNamedQueryNameAttr)].(string)
+			c.Config[config.Key(apiDatabaseNamedQueryPrefix+name)] = query[string(checkDatabaseNamedQuerySQLAttr)].(string)
+		}
+	}
+
+	if v, found := dbConfig[checkDatabaseTLSAttr]; found {
+		for _, tlsRaw := range v.([]interface{}) {
+			c.Config[config.UseSSL] = "true"
+
+			tlsConfig, ok := tlsRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if v, found := tlsConfig[string(checkDatabaseTLSCAChainAttr)]; found && v.(string) != "" {
+				c.Config[config.CAChain] = v.(string)
+			}
+
+			if v, found := tlsConfig[string(checkDatabaseTLSCertFileAttr)]; found && v.(string) != "" {
+				c.Config[config.CertFile] = v.(string)
+			}
+
+			if v, found := tlsConfig[string(checkDatabaseTLSKeyFileAttr)]; found && v.(string) != "" {
+				c.Config[config.KeyFile] = v.(string)
+			}
+
+			if v, found := tlsConfig[string(checkDatabaseTLSVerifyModeAttr)]; found && v.(string) != "" {
+				c.Config[apiDatabaseTLSVerifyMode] = v.(string)
+			}
+		}
+	}
+}
+
+// checkDatabaseAPIToState copies the shared password, tls and named_query
+// config of a database check bundle into dbConfig, removing each key it reads
+// from swamp.
+func checkDatabaseAPIToState(c *circonusCheck, dbConfig map[string]interface{}, swamp map[config.Key]string) {
+	if v, found := c.Config[config.Password]; found && v != "" {
+		dbConfig[string(checkDatabasePasswordAttr)] = v
+	}
+	delete(swamp, config.Password)
+
+	queries := make([]interface{}, 0)
+	for k, v := range c.Config {
+		name := strings.TrimPrefix(string(k), apiDatabaseNamedQueryPrefix)
+		if name == string(k) {
+			continue
+		}
+
+		queries = append(queries, map[string]interface{}{
+			string(checkDatabaseNamedQueryNameAttr): name,
+			string(checkDatabaseNamedQuerySQLAttr):  v,
+		})
+		delete(swamp, k)
+	}
+
+	if len(queries) > 0 {
+		dbConfig[string(checkDatabaseNamedQueryAttr)] = queries
+	}
+
+	if c.Config[config.UseSSL] == "true" {
+		tlsConfig := map[string]interface{}{
+			string(checkDatabaseTLSVerifyModeAttr): defaultCheckDatabaseVerifyMode,
+		}
+
+		saveString := func(apiKey config.Key, attrName schemaAttr) {
+			if v, found := c.Config[apiKey]; found && v != "" {
+				tlsConfig[string(attrName)] = v
+			}
+		}
+
+		saveString(config.CAChain, checkDatabaseTLSCAChainAttr)
+		saveString(config.CertFile, checkDatabaseTLSCertFileAttr)
+		saveString(config.KeyFile, checkDatabaseTLSKeyFileAttr)
+		saveString(apiDatabaseTLSVerifyMode, checkDatabaseTLSVerifyModeAttr)
+
+		dbConfig[string(checkDatabaseTLSAttr)] = []interface{}{tlsConfig}
+	}
+
+	for _, k := range []config.Key{config.UseSSL, config.CAChain, config.CertFile, config.KeyFile, apiDatabaseTLSVerifyMode} {
+		delete(swamp, k)
+	}
+}
+
+// hashCheckDatabase writes the shared password, tls and named_query attributes
+// of a database check type's block to b.
+func hashCheckDatabase(b *bytes.Buffer, m map[string]interface{}) {
+	if v, ok := m[string(checkDatabaseNamedQueryAttr)]; ok {
+		for _, query := range checkDatabaseNamedQueries(v) {
+			fmt.Fprint(b, query[string(checkDatabaseNamedQueryNameAttr)].(string))
+			fmt.Fprint(b, strings.TrimSpace(query[string(checkDatabaseNamedQuerySQLAttr)].(string)))
+		}
+	}
+
+	if v, ok := m[string(checkDatabasePasswordAttr)]; ok && v.(string) != "" {
+		fmt.Fprint(b, v.(string))
+	}
+
+	if v, ok := m[string(checkDatabaseTLSAttr)]; ok {
+		for _, tlsRaw := range v.([]interface{}) {
+			fmt.Fprint(b, checkDatabaseTLSAttr)
+
+			tlsConfig, ok := tlsRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			// Order writes to the buffer using lexically sorted list for easy
+			// visual reconciliation with other lists.
+			for _, attrName := range []schemaAttr{checkDatabaseTLSCAChainAttr, checkDatabaseTLSCertFileAttr, checkDatabaseTLSKeyFileAttr, checkDatabaseTLSVerifyModeAttr} {
+				if v, ok := tlsConfig[string(attrName)]; ok && v.(string) != "" {
+					fmt.Fprint(b, strings.TrimSpace(v.(string)))
+				}
+			}
+		}
+	}
+}
+
+// validateCheckSQL validates the shared attributes of a SQL check type: exactly
+// one of its query or named_query attributes must be set, and its TLS options
+// must be valid.
+func validateCheckSQL(blockAttr, queryAttr schemaAttr) func(*circonusCheck) error {
+	validateTLS := validateCheckDatabaseTLS(blockAttr)
+
+	return func(c *circonusCheck) error {
+		var named bool
+		for k := range c.Config {
+			if strings.HasPrefix(string(k), apiDatabaseNamedQueryPrefix) {
+				named = true
+				break
+			}
+		}
+
+		_, single := c.Config[config.SQL]
+		switch {
+		case single && named:
+			return attrErrorf(attrPath(blockAttr), "only one of %s or %s may be set", queryAttr, checkDatabaseNamedQueryAttr)
+		case !single && !named:
+			return attrErrorf(attrPath(blockAttr), "one of %s or %s must be set", queryAttr, checkDatabaseNamedQueryAttr)
+		}
+
+		return validateTLS(c)
+	}
+}
+
+// validateCheckDatabaseTLS requires a client certificate to come with its key.
+func validateCheckDatabaseTLS(blockAttr schemaAttr) func(*circonusCheck) error {
+	return func(c *circonusCheck) error {
+		_, cert := c.Config[config.CertFile]
+		_, key := c.Config[config.KeyFile]
+		if cert != key {
+			return attrErrorf(attrPath(blockAttr), "%s.%s and %s.%s must be set together", checkDatabaseTLSAttr, checkDatabaseTLSCertFileAttr, checkDatabaseTLSAttr, checkDatabaseTLSKeyFileAttr)
+		}
+
+		return nil
+	}
+}
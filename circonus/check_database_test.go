@@ -0,0 +1,126 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_CheckDatabaseConfig(t *testing.T) {
+	dbConfig := newInterfaceMap(map[string]interface{}{
+		string(checkDatabasePasswordAttr): "secret",
+		string(checkDatabaseNamedQueryAttr): schema.NewSet(schema.HashResource(schemaCheckDatabaseNamedQuery().Elem.(*schema.Resource)), []interface{}{
+			map[string]interface{}{"name": "tables", "sql": "SELECT 'tables', count(*) FROM pg_stat_all_tables"},
+			map[string]interface{}{"name": "locks", "sql": "SELECT 'locks', count(*) FROM pg_locks"},
+		}),
+		string(checkDatabaseTLSAttr): []interface{}{
+			map[string]interface{}{
+				"ca_chain":         "/etc/ssl/db-ca.pem",
+				"certificate_file": "",
+				"key_file":         "",
+				"verify_mode":      "verify-ca",
+			},
+		},
+	})
+
+	c := newCheck()
+	checkDatabaseConfigToAPI(&c, dbConfig)
+
+	expected := map[config.Key]string{
+		config.CAChain:           "/etc/ssl/db-ca.pem",
+		config.Password:          "secret",
+		config.UseSSL:            "true",
+		apiDatabaseTLSVerifyMode: "verify-ca",
+		"sql_locks":              "SELECT 'locks', count(*) FROM pg_locks",
+		"sql_tables":             "SELECT 'tables', count(*) FROM pg_stat_all_tables",
+	}
+
+	if len(c.Config) != len(expected) {
+		t.Fatalf("expected %d config keys, got %#v", len(expected), c.Config)
+	}
+
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("config key %q: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	state := make(map[string]interface{})
+	checkDatabaseAPIToState(&c, state, swamp)
+
+	if len(swamp) != 0 {
+		t.Errorf("config keys not read into the state: %#v", swamp)
+	}
+
+	if state[string(checkDatabasePasswordAttr)] != "secret" {
+		t.Errorf("password not read into the state: %#v", state)
+	}
+
+	if queries := checkDatabaseNamedQueries(state[string(checkDatabaseNamedQueryAttr)]); len(queries) != 2 || queries[0]["name"] != "locks" {
+		t.Errorf("named queries not read into the state: %#v", queries)
+	}
+
+	tlsConfig := state[string(checkDatabaseTLSAttr)].([]interface{})[0].(map[string]interface{})
+	if tlsConfig["ca_chain"] != "/etc/ssl/db-ca.pem" || tlsConfig["verify_mode"] != "verify-ca" {
+		t.Errorf("tls not read into the state: %#v", tlsConfig)
+	}
+}
+
+func Test_ValidateCheckSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[config.Key]string
+		fail   bool
+	}{
+		{
+			name:   "single query",
+			config: map[config.Key]string{config.SQL: "SELECT 1"},
+		},
+		{
+			name:   "named queries",
+			config: map[config.Key]string{"sql_one": "SELECT 1", "sql_two": "SELECT 2"},
+		},
+		{
+			name:   "no query",
+			config: map[config.Key]string{config.DSN: "host=db.example.org"},
+			fail:   true,
+		},
+		{
+			name:   "both query forms",
+			config: map[config.Key]string{config.SQL: "SELECT 1", "sql_one": "SELECT 1"},
+			fail:   true,
+		},
+		{
+			name:   "client certificate without key",
+			config: map[config.Key]string{config.SQL: "SELECT 1", config.UseSSL: "true", config.CertFile: "/etc/ssl/client.pem"},
+			fail:   true,
+		},
+		{
+			name:   "client certificate and key",
+			config: map[config.Key]string{config.SQL: "SELECT 1", config.UseSSL: "true", config.CertFile: "/etc/ssl/client.pem", config.KeyFile: "/etc/ssl/client.key"},
+		},
+	}
+
+	validate := validateCheckSQL(checkPostgreSQLAttr, checkPostgreSQLQueryAttr)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			c.Config = test.config
+
+			err := validate(&c)
+			if test.fail && err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !test.fail && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package circonus
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.http.step.* resource attribute names.
+	checkHTTPStepAttr         = "step"
+	checkHTTPStepCaptureAttr  = "capture"
+	checkHTTPStepHeaderAttr   = "header"
+	checkHTTPStepJSONPathAttr = "json_path"
+	checkHTTPStepNameAttr     = "name"
+	checkHTTPStepRegexpAttr   = "regexp"
+
+	// apiHTTPSteps is the broker's scripted HTTP option holding the JSON
+	// encoded steps of a multi-step HTTP check.  The broker runs the steps in
+	// order and reports each step's metrics prefixed with the step's name, e.g.
+	// login`duration.
+	apiHTTPSteps = config.Key("steps")
+
+	// checkHTTPStepLatencyMetric is the per-step metric collected for every
+	// step of a check that lists its metrics.
+	checkHTTPStepLatencyMetric = "duration"
+)
+
+var checkHTTPStepDescriptions = attrDescrs{
+	checkHTTPBodyRegexpAttr:  checkHTTPDescriptions[checkHTTPBodyRegexpAttr],
+	checkHTTPCodeRegexpAttr:  checkHTTPDescriptions[checkHTTPCodeRegexpAttr],
+	checkHTTPHeadersAttr:     "Map of HTTP Headers to send with this step's request",
+	checkHTTPMethodAttr:      checkHTTPDescriptions[checkHTTPMethodAttr],
+	checkHTTPPayloadAttr:     checkHTTPDescriptions[checkHTTPPayloadAttr],
+	checkHTTPStepCaptureAttr: "A value captured from the response for use by later steps as {{name}}",
+	checkJSONAssertAttr:      "A JSONPath expression that must match the response body",
+	checkHTTPStepNameAttr:    "The name of the step, used to name its metrics",
+	checkHTTPURLAttr:         "The URL requested by this step",
+}
+
+var checkHTTPStepCaptureDescriptions = attrDescrs{
+	checkHTTPStepHeaderAttr:   "The response header to capture",
+	checkHTTPStepJSONPathAttr: "The JSONPath expression of the response body value to capture",
+	checkHTTPStepNameAttr:     "The variable name the value is captured as",
+	checkHTTPStepRegexpAttr:   "A regular expression matched against the response body; the first capture group is captured",
+}
+
+var (
+	checkHTTPStepNameRegexp     = regexp.MustCompile(`^[\w-]+$`)
+	checkHTTPStepVariableRegexp = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+)
+
+var schemaCheckHTTPStep = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkHTTPStepDescriptions, map[schemaAttr]*schema.Schema{
+			checkHTTPBodyRegexpAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkHTTPBodyRegexpAttr, `.+`),
+			},
+			checkHTTPCodeRegexpAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultCheckHTTPCodeRegexp,
+				ValidateFunc: validateRegexp(checkHTTPCodeRegexpAttr, `.+`),
+			},
+			checkHTTPHeadersAttr: {
+				Type:         schema.TypeMap,
+				Elem:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateHTTPHeaders,
+			},
+			checkHTTPMethodAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultCheckHTTPMethod,
+				ValidateFunc: validateRegexp(checkHTTPMethodAttr, `\S+`),
+			},
+			checkHTTPPayloadAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkHTTPPayloadAttr, `\S+`),
+			},
+			checkHTTPStepCaptureAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkHTTPStepCaptureDescriptions, map[schemaAttr]*schema.Schema{
+						checkHTTPStepHeaderAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkHTTPStepHeaderAttr, `^[^:\s]+$`),
+						},
+						checkHTTPStepJSONPathAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateJSONPath(checkHTTPStepJSONPathAttr),
+						},
+						checkHTTPStepNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkHTTPStepNameAttr, `^\w+$`),
+						},
+						checkHTTPStepRegexpAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexpSyntax(checkHTTPStepRegexpAttr),
+						},
+					}),
+				},
+			},
+			checkJSONAssertAttr: schemaCheckJSONAssert(),
+			checkHTTPStepNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkHTTPStepNameAttr, checkHTTPStepNameRegexp.String()),
+			},
+			checkHTTPURLAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkHTTPURLAttr, `^https?://\S+$`),
+			},
+		}),
+	},
+}
+
+// checkHTTPStep is a single request of a multi-step HTTP check, as encoded in
+// the broker config.
+type checkHTTPStep struct {
+	Name        string                 `json:"name"`
+	Method      string                 `json:"method"`
+	URL         string                 `json:"url"`
+	Headers     map[string]string      `json:"headers,omitempty"`
+	Payload     string                 `json:"payload,omitempty"`
+	Code        string                 `json:"code"`
+	Body        string                 `json:"body,omitempty"`
+	JSONAsserts []checkJSONPathAssert  `json:"json_asserts,omitempty"`
+	Captures    []checkHTTPStepCapture `json:"captures,omitempty"`
+}
+
+type checkHTTPStepCapture struct {
+	Name     string `json:"name"`
+	JSONPath string `json:"json_path,omitempty"`
+	Regexp   string `json:"regexp,omitempty"`
+	Header   string `json:"header,omitempty"`
+}
+
+// checkHTTPStepsFromList converts the step blocks of the http attribute.
+func checkHTTPStepsFromList(l []interface{}) []checkHTTPStep {
+	steps := make([]checkHTTPStep, 0, len(l))
+	for _, stepRaw := range l {
+		stepConfig := newInterfaceMap(stepRaw)
+
+		step := checkHTTPStep{Headers: stepConfig.CollectMap(checkHTTPHeadersAttr)}
+		step.Name, _ = stepConfig[checkHTTPStepNameAttr].(string)
+		step.Method, _ = stepConfig[checkHTTPMethodAttr].(string)
+		step.URL, _ = stepConfig[checkHTTPURLAttr].(string)
+		step.Payload, _ = stepConfig[checkHTTPPayloadAttr].(string)
+		step.Code, _ = stepConfig[checkHTTPCodeRegexpAttr].(string)
+		step.Body, _ = stepConfig[checkHTTPBodyRegexpAttr].(string)
+
+		if asserts, _ := stepConfig[checkJSONAssertAttr].([]interface{}); len(asserts) > 0 {
+			step.JSONAsserts = checkJSONAssertsFromList(asserts)
+		}
+
+		captures, _ := stepConfig[checkHTTPStepCaptureAttr].([]interface{})
+		for _, captureRaw := range captures {
+			captureConfig := newInterfaceMap(captureRaw)
+			var c checkHTTPStepCapture
+			c.Name, _ = captureConfig[checkHTTPStepNameAttr].(string)
+			c.JSONPath, _ = captureConfig[checkHTTPStepJSONPathAttr].(string)
+			c.Regexp, _ = captureConfig[checkHTTPStepRegexpAttr].(string)
+			c.Header, _ = captureConfig[checkHTTPStepHeaderAttr].(string)
+			step.Captures = append(step.Captures, c)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+// checkHTTPStepsToList is the inverse of checkHTTPStepsFromList.
+func checkHTTPStepsToList(steps []checkHTTPStep) []interface{} {
+	l := make([]interface{}, 0, len(steps))
+	for _, step := range steps {
+		headers := make(map[string]interface{}, len(step.Headers))
+		for k, v := range step.Headers {
+			headers[k] = v
+		}
+
+		captures := make([]interface{}, 0, len(step.Captures))
+		for _, c := range step.Captures {
+			captures = append(captures, map[string]interface{}{
+				string(checkHTTPStepHeaderAttr):   c.Header,
+				string(checkHTTPStepJSONPathAttr): c.JSONPath,
+				string(checkHTTPStepNameAttr):     c.Name,
+				string(checkHTTPStepRegexpAttr):   c.Regexp,
+			})
+		}
+
+		l = append(l, map[string]interface{}{
+			string(checkHTTPBodyRegexpAttr):  step.Body,
+			string(checkHTTPCodeRegexpAttr):  step.Code,
+			string(checkHTTPHeadersAttr):     headers,
+			string(checkHTTPMethodAttr):      step.Method,
+			string(checkHTTPPayloadAttr):     step.Payload,
+			string(checkHTTPStepCaptureAttr): captures,
+			string(checkHTTPStepNameAttr):    step.Name,
+			string(checkHTTPURLAttr):         step.URL,
+			string(checkJSONAssertAttr):      checkJSONAssertsToList(step.JSONAsserts),
+		})
+	}
+
+	return l
+}
+
+// validateCheckHTTPSteps checks that step names and captured variable names
+// are unique, that every capture has exactly one source and that {{name}}
+// references only use variables captured by an earlier step.
+func validateCheckHTTPSteps(steps []checkHTTPStep) error {
+	stepPath := attrPath(checkHTTPAttr)
+	stepNames := make(map[string]struct{}, len(steps))
+	captured := make(map[string]struct{})
+
+	for _, step := range steps {
+		if _, found := stepNames[step.Name]; found {
+			return attrErrorf(stepPath, "duplicate %s name %q", checkHTTPStepAttr, step.Name)
+		}
+		stepNames[step.Name] = struct{}{}
+
+		refs := []string{step.URL, step.Payload}
+		headerNames := make([]string, 0, len(step.Headers))
+		for k := range step.Headers {
+			headerNames = append(headerNames, k)
+		}
+		sort.Strings(headerNames)
+		for _, k := range headerNames {
+			refs = append(refs, step.Headers[k])
+		}
+
+		for _, ref := range refs {
+			for _, m := range checkHTTPStepVariableRegexp.FindAllStringSubmatch(ref, -1) {
+				if _, found := captured[m[1]]; !found {
+					return attrErrorf(stepPath, "step %q uses {{%s}} which no earlier step captures", step.Name, m[1])
+				}
+			}
+		}
+
+		if err := validateJSONPathAsserts(stepPath, step.JSONAsserts); err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+
+		for _, c := range step.Captures {
+			sources := 0
+			for _, v := range []string{c.Header, c.JSONPath, c.Regexp} {
+				if v != "" {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return attrErrorf(stepPath, "step %q: capture %q needs exactly one of %s, %s or %s", step.Name, c.Name, checkHTTPStepHeaderAttr, checkHTTPStepJSONPathAttr, checkHTTPStepRegexpAttr)
+			}
+
+			if _, found := captured[c.Name]; found {
+				return attrErrorf(stepPath, "step %q: %q is already captured", step.Name, c.Name)
+			}
+			captured[c.Name] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// checkHTTPStepsToAPI validates the steps and stores them in the broker config.
+func checkHTTPStepsToAPI(c *circonusCheck, steps []checkHTTPStep) error {
+	if err := validateCheckHTTPSteps(steps); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(steps)
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %w", checkHTTPStepAttr, err)
+	}
+	c.Config[apiHTTPSteps] = string(buf)
+	c.addHTTPStepMetrics(steps)
+
+	return nil
+}
+
+// checkHTTPStepMetricName returns the name of the broker's metric for a step.
+func checkHTTPStepMetricName(step, metric string) string {
+	return step + "`" + metric
+}
+
+// addHTTPStepMetrics adds the latency metric of each step to a check that
+// lists its metrics.  Checks using metric filters collect whatever the filters
+// allow.
+func (c *circonusCheck) addHTTPStepMetrics(steps []checkHTTPStep) {
+	if len(c.MetricFilters) > 0 {
+		return
+	}
+
+	names := make(map[string]struct{}, len(c.Metrics))
+	for _, m := range c.Metrics {
+		names[m.Name] = struct{}{}
+	}
+
+	for _, step := range steps {
+		name := checkHTTPStepMetricName(step.Name, checkHTTPStepLatencyMetric)
+		if _, found := names[name]; found {
+			continue
+		}
+
+		c.Metrics = append(c.Metrics, api.CheckBundleMetric{
+			Name:   name,
+			Status: metricActiveToAPIStatus(true),
+			Type:   "numeric",
+		})
+	}
+}
+
+// checkHTTPStepMetricImplicit reports whether the metric m was added by
+// addHTTPStepMetrics rather than configured, in which case it is left out of
+// the state.
+func checkHTTPStepMetricImplicit(c *circonusCheck, d *schema.ResourceData, m api.CheckBundleMetric) bool {
+	v, ok := c.Config[apiHTTPSteps]
+	if !ok {
+		return false
+	}
+
+	var steps []checkHTTPStep
+	if err := json.Unmarshal([]byte(v), &steps); err != nil {
+		return false
+	}
+
+	found := false
+	for _, step := range steps {
+		if m.Name == checkHTTPStepMetricName(step.Name, checkHTTPStepLatencyMetric) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	configured, _ := d.Get(checkMetricAttr).([]interface{})
+	for _, v := range configured {
+		if attrs, ok := v.(map[string]interface{}); ok && attrs[string(metricNameAttr)] == m.Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkHTTPStepsToState decodes the steps stored in the broker config.
+func checkHTTPStepsToState(v string) ([]interface{}, error) {
+	var steps []checkHTTPStep
+	if err := json.Unmarshal([]byte(v), &steps); err != nil {
+		return nil, fmt.Errorf("unable to decode %s config %q: %w", apiHTTPSteps, v, err)
+	}
+
+	return checkHTTPStepsToList(steps), nil
+}
@@ -0,0 +1,162 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+)
+
+func Test_ValidateCheckHTTPSteps(t *testing.T) {
+	login := checkHTTPStep{
+		Name:     "login",
+		Method:   "POST",
+		URL:      "https://api.example.com/login",
+		Code:     defaultCheckHTTPCodeRegexp,
+		Captures: []checkHTTPStepCapture{{Name: "token", JSONPath: "$.access_token"}},
+	}
+
+	tests := []struct {
+		name  string
+		steps []checkHTTPStep
+		fail  bool
+	}{
+		{
+			name: "capture used by a later step",
+			steps: []checkHTTPStep{
+				login,
+				{Name: "profile", URL: "https://api.example.com/me", Headers: map[string]string{"Authorization": "Bearer {{ token }}"}},
+			},
+		},
+		{
+			name: "variable used before it is captured",
+			steps: []checkHTTPStep{
+				{Name: "profile", URL: "https://api.example.com/me?t={{token}}"},
+				login,
+			},
+			fail: true,
+		},
+		{
+			name:  "variable captured by the same step",
+			steps: []checkHTTPStep{{Name: "login", URL: "https://api.example.com/login", Payload: "{{token}}", Captures: login.Captures}},
+			fail:  true,
+		},
+		{
+			name:  "duplicate step names",
+			steps: []checkHTTPStep{login, {Name: "login", URL: "https://api.example.com/"}},
+			fail:  true,
+		},
+		{
+			name:  "capture without a source",
+			steps: []checkHTTPStep{{Name: "login", URL: "https://api.example.com/", Captures: []checkHTTPStepCapture{{Name: "token"}}}},
+			fail:  true,
+		},
+		{
+			name:  "capture with two sources",
+			steps: []checkHTTPStep{{Name: "login", URL: "https://api.example.com/", Captures: []checkHTTPStepCapture{{Name: "token", Header: "X-Token", Regexp: "(.*)"}}}},
+			fail:  true,
+		},
+		{
+			name:  "invalid json assertion regexp",
+			steps: []checkHTTPStep{{Name: "login", URL: "https://api.example.com/", JSONAsserts: []checkJSONPathAssert{{Path: "$.ok", Regexp: "("}}}},
+			fail:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateCheckHTTPSteps(test.steps)
+			if test.fail && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !test.fail && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_CheckHTTPStepsRoundTrip(t *testing.T) {
+	steps := []checkHTTPStep{
+		{
+			Name:     "login",
+			Method:   "POST",
+			URL:      "https://api.example.com/login",
+			Headers:  map[string]string{"Content-Type": "application/json"},
+			Payload:  `{"user":"monitor"}`,
+			Code:     "^200$",
+			Captures: []checkHTTPStepCapture{{Name: "token", JSONPath: "$.access_token"}},
+		},
+		{
+			Name:        "profile",
+			Method:      "GET",
+			URL:         "https://api.example.com/me",
+			Headers:     map[string]string{"Authorization": "Bearer {{token}}"},
+			Code:        "^200$",
+			Body:        "active",
+			JSONAsserts: []checkJSONPathAssert{{Path: "$.user.active", Regexp: "^true$"}},
+		},
+	}
+
+	c := newCheck()
+	if err := checkHTTPStepsToAPI(&c, steps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	l, err := checkHTTPStepsToState(c.Config[apiHTTPSteps])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := checkHTTPStepsFromList(l); !reflect.DeepEqual(got, steps) {
+		t.Errorf("expected %#v, got %#v", steps, got)
+	}
+}
+
+func Test_CheckHTTPStepMetrics(t *testing.T) {
+	steps := []checkHTTPStep{{Name: "login"}, {Name: "profile"}}
+
+	tests := []struct {
+		name    string
+		metrics []api.CheckBundleMetric
+		filters [][]string
+		want    []string
+	}{
+		{
+			name: "latency metric added per step",
+			want: []string{"login`duration", "profile`duration"},
+		},
+		{
+			name:    "configured latency metric kept",
+			metrics: []api.CheckBundleMetric{{Name: "login`duration", Type: "numeric", Status: "available"}},
+			want:    []string{"login`duration", "profile`duration"},
+		},
+		{
+			name:    "metric filters left alone",
+			filters: [][]string{{"allow", "^.+$", "everything"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			c.Metrics = append(c.Metrics, test.metrics...)
+			c.MetricFilters = test.filters
+
+			c.addHTTPStepMetrics(steps)
+
+			var got []string
+			for _, m := range c.Metrics {
+				got = append(got, m.Name)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected metrics %v, got %v", test.want, got)
+			}
+
+			if len(test.metrics) > 0 && c.Metrics[0].Status != "available" {
+				t.Errorf("expected the configured metric to keep its status, got %q", c.Metrics[0].Status)
+			}
+		})
+	}
+}
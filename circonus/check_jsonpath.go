@@ -0,0 +1,202 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// json_assert.* resource attribute names, shared by the json check type
+	// and the steps of the http check type.
+	checkJSONAssertAttr       = "json_assert"
+	checkJSONAssertPathAttr   = "path"
+	checkJSONAssertRegexpAttr = "regexp"
+)
+
+var checkJSONAssertDescriptions = attrDescrs{
+	checkJSONAssertPathAttr:   "The JSONPath expression, e.g. $.status",
+	checkJSONAssertRegexpAttr: "A regular expression the value found must match.  When empty the path only has to exist",
+}
+
+// schemaCheckJSONAssert returns the schema of a list of json_assert blocks.
+func schemaCheckJSONAssert() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkJSONAssertDescriptions, map[schemaAttr]*schema.Schema{
+				checkJSONAssertPathAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateJSONPath(checkJSONAssertPathAttr),
+				},
+				checkJSONAssertRegexpAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexpSyntax(checkJSONAssertRegexpAttr),
+				},
+			}),
+		},
+	}
+}
+
+// checkJSONPathAssert is a JSONPath expression that must be found in a JSON
+// response body and, when Regexp is set, whose value must match Regexp.
+type checkJSONPathAssert struct {
+	Path   string `json:"path"`
+	Regexp string `json:"regexp,omitempty"`
+}
+
+var (
+	jsonPathNameRegexp  = regexp.MustCompile(`^[A-Za-z_][\w-]*`)
+	jsonPathIndexRegexp = regexp.MustCompile(`^-?\d+$`)
+	jsonPathSliceRegexp = regexp.MustCompile(`^(-?\d+)?:(-?\d+)?(?::(-?\d+))?$`)
+	jsonPathQuoteRegexp = regexp.MustCompile(`^(?:'[^']*'|"[^"]*")$`)
+)
+
+// parseJSONPath checks the syntax of a JSONPath expression.  It accepts the
+// root $ followed by any number of .name, .*, ..name, ..*, [n], [n,m],
+// [start:end:step], [*], ['name'] and [?(filter)] segments.
+func parseJSONPath(path string) error {
+	if !strings.HasPrefix(path, "$") {
+		return fmt.Errorf("JSONPath %q must start with $", path)
+	}
+
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				continue
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			if strings.HasPrefix(rest, "*") {
+				rest = rest[1:]
+				continue
+			}
+
+			name := jsonPathNameRegexp.FindString(rest)
+			if name == "" {
+				return fmt.Errorf("JSONPath %q: expected a member name at %q", path, rest)
+			}
+			rest = rest[len(name):]
+		case strings.HasPrefix(rest, "["):
+			end, err := jsonPathBracketEnd(rest)
+			if err != nil {
+				return fmt.Errorf("JSONPath %q: %w", path, err)
+			}
+
+			if err := parseJSONPathBracket(rest[1:end]); err != nil {
+				return fmt.Errorf("JSONPath %q: %w", path, err)
+			}
+			rest = rest[end+1:]
+		default:
+			return fmt.Errorf("JSONPath %q: unexpected %q", path, rest)
+		}
+	}
+
+	return nil
+}
+
+// jsonPathBracketEnd returns the index of the ] closing the [ that s starts
+// with, skipping over quoted strings and nested brackets.
+func jsonPathBracketEnd(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated [ in %q", s)
+}
+
+func parseJSONPathBracket(expr string) error {
+	expr = strings.TrimSpace(expr)
+	switch {
+	case expr == "*":
+		return nil
+	case strings.HasPrefix(expr, "?("):
+		if !strings.HasSuffix(expr, ")") || strings.TrimSpace(expr[2:len(expr)-1]) == "" {
+			return fmt.Errorf("invalid filter expression [%s]", expr)
+		}
+		return nil
+	case jsonPathSliceRegexp.MatchString(expr):
+		return nil
+	}
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if !jsonPathIndexRegexp.MatchString(part) && !jsonPathQuoteRegexp.MatchString(part) {
+			return fmt.Errorf("invalid subscript [%s]", expr)
+		}
+	}
+
+	return nil
+}
+
+// checkJSONAssertsFromList converts a list of json_assert blocks.
+func checkJSONAssertsFromList(l []interface{}) []checkJSONPathAssert {
+	asserts := make([]checkJSONPathAssert, 0, len(l))
+	for _, assertRaw := range l {
+		assertConfig := newInterfaceMap(assertRaw)
+		var a checkJSONPathAssert
+		a.Path, _ = assertConfig[checkJSONAssertPathAttr].(string)
+		a.Regexp, _ = assertConfig[checkJSONAssertRegexpAttr].(string)
+		asserts = append(asserts, a)
+	}
+
+	return asserts
+}
+
+// checkJSONAssertsToList is the inverse of checkJSONAssertsFromList.
+func checkJSONAssertsToList(asserts []checkJSONPathAssert) []interface{} {
+	l := make([]interface{}, 0, len(asserts))
+	for _, a := range asserts {
+		l = append(l, map[string]interface{}{
+			string(checkJSONAssertPathAttr):   a.Path,
+			string(checkJSONAssertRegexpAttr): a.Regexp,
+		})
+	}
+
+	return l
+}
+
+// validateJSONPathAsserts checks the path and regexp of each assertion.
+func validateJSONPathAsserts(p cty.Path, asserts []checkJSONPathAssert) error {
+	for _, a := range asserts {
+		if err := parseJSONPath(a.Path); err != nil {
+			return attrErrorf(p, "%v", err)
+		}
+
+		if a.Regexp == "" {
+			continue
+		}
+
+		if _, err := regexp.Compile(a.Regexp); err != nil {
+			return attrErrorf(p, "invalid regexp %q for %s: %v", a.Regexp, a.Path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package circonus
+
+import "testing"
+
+func Test_ParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		fail bool
+	}{
+		{path: "$"},
+		{path: "$.status"},
+		{path: "$.stats.requests"},
+		{path: "$.servers[0].name"},
+		{path: "$.servers[-1]"},
+		{path: "$.servers[0,2]"},
+		{path: "$.servers[1:3]"},
+		{path: "$.servers[::2]"},
+		{path: "$.servers[*].name"},
+		{path: "$.*"},
+		{path: "$..name"},
+		{path: "$..[0]"},
+		{path: "$['first name']"},
+		{path: `$["a]b"].c`},
+		{path: "$.servers[?(@.up == true)].name"},
+		{path: "$.servers[?(@.tags[0] == 'web')]"},
+		{path: "status", fail: true},
+		{path: "$.", fail: true},
+		{path: "$.servers[", fail: true},
+		{path: "$.servers[abc]", fail: true},
+		{path: "$.servers[?()]", fail: true},
+		{path: "$ status", fail: true},
+		{path: "$.9lives", fail: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			err := parseJSONPath(test.path)
+			if test.fail && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !test.fail && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
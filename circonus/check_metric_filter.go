@@ -0,0 +1,333 @@
+package circonus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.metric_filter.* resource attribute names.
+	checkMetricFilterCommentAttr  = "comment"
+	checkMetricFilterNameAttr     = "name"
+	checkMetricFilterPriorityAttr = "priority"
+	checkMetricFilterRegexAttr    = "regex"
+	checkMetricFilterTagQueryAttr = "tag_query"
+	checkMetricFilterTypeAttr     = "type"
+
+	// apiCheckMetricFilterNames holds the JSON encoded names and priorities of
+	// the check's metric filters.  The API only stores filters as an ordered
+	// list, so the names are kept alongside the filters they belong to.
+	apiCheckMetricFilterNames = config.Key("metric_filter_names")
+
+	// metricFilterTagsMarker precedes the tag query in an API metric filter.
+	metricFilterTagsMarker = "tags"
+)
+
+var checkMetricFilterRuleDescriptions = attrDescrs{
+	checkMetricFilterCommentAttr:  "Comment on this filter",
+	checkMetricFilterNameAttr:     "Name of the filter, unique within the check",
+	checkMetricFilterPriorityAttr: "Filters are applied in ascending priority, then name, order",
+	checkMetricFilterRegexAttr:    "Regex of the filter",
+	checkMetricFilterTagQueryAttr: "The tag query to apply",
+	checkMetricFilterTypeAttr:     "'allow' or 'deny'",
+}
+
+// checkMetricFilterName is the name and priority of a metric filter, stored in
+// the check's config with the filter it names.
+type checkMetricFilterName struct {
+	Name     string   `json:"name"`
+	Priority int      `json:"priority"`
+	Filter   []string `json:"filter"`
+}
+
+// checkMetricFilterDefaultNameRE matches the names given to filters without
+// one.  They are reserved so a generated name is never mistaken for one that
+// was configured.
+var checkMetricFilterDefaultNameRE = regexp.MustCompile(`^filter_[0-9]+$`)
+
+// schemaCheckMetricFilter returns the schema of circonus_check's metric_filter
+// attribute.  Filters are a set keyed by their rule and, when named, their
+// name and priority, so adding, removing or reordering one filter leaves the
+// others out of the plan.
+func schemaCheckMetricFilter() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Set:      hashCheckMetricFilter,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkMetricFilterRuleDescriptions, map[schemaAttr]*schema.Schema{
+				checkMetricFilterCommentAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkMetricFilterCommentAttr, `.+`),
+				},
+				checkMetricFilterNameAttr: {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+					ValidateFunc: validateFuncs(
+						validateRegexp(checkMetricFilterNameAttr, `^\S+$`),
+						validateCheckMetricFilterName,
+					),
+				},
+				checkMetricFilterPriorityAttr: {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validateIntMin(checkMetricFilterPriorityAttr, 1),
+				},
+				checkMetricFilterRegexAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateRegexp(checkMetricFilterRegexAttr, `.+`),
+				},
+				checkMetricFilterTagQueryAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkMetricFilterTagQueryAttr, `.+`),
+				},
+				checkMetricFilterTypeAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateRegexp(checkMetricFilterTypeAttr, `allow|deny`),
+				},
+			}),
+		},
+	}
+}
+
+// checkMetricFiltersToAPI sets the check's metric filters from its
+// metric_filter blocks, in priority order, and records their names.  Filters
+// without a name get the name and priority of their position in l, the name
+// skipping any taken by another filter.  Only named filters may set a
+// priority: an unnamed filter's priority isn't part of its key, so a change to
+// it would never be planned.
+func checkMetricFiltersToAPI(c *circonusCheck, l []interface{}) error {
+	seen := make(map[string]struct{}, len(l))
+	for _, v := range l {
+		attrs := newInterfaceMap(v)
+
+		name, _ := attrs[checkMetricFilterNameAttr].(string)
+		if name == "" {
+			continue
+		}
+
+		if _, found := seen[name]; found {
+			return attrErrorf(attrPath(checkMetricFilterAttr), "duplicate %s %s %q", checkMetricFilterAttr, checkMetricFilterNameAttr, name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	names := make([]checkMetricFilterName, 0, len(l))
+	for i, v := range l {
+		attrs := newInterfaceMap(v)
+
+		name, _ := attrs[checkMetricFilterNameAttr].(string)
+		priority, _ := attrs[checkMetricFilterPriorityAttr].(int)
+		if name == "" {
+			if priority != 0 {
+				return attrErrorf(attrPath(checkMetricFilterAttr), "%s %s requires a %s", checkMetricFilterAttr, checkMetricFilterPriorityAttr, checkMetricFilterNameAttr)
+			}
+
+			for n := i; name == ""; n++ {
+				if _, found := seen[defaultCheckMetricFilterName(n)]; !found {
+					name = defaultCheckMetricFilterName(n)
+					seen[name] = struct{}{}
+				}
+			}
+			priority = defaultCheckMetricFilterPriority(i)
+		}
+
+		names = append(names, checkMetricFilterName{
+			Name:     name,
+			Priority: priority,
+			Filter:   metricFilterToAPI(attrs),
+		})
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		if names[i].Priority != names[j].Priority {
+			return names[i].Priority < names[j].Priority
+		}
+
+		return names[i].Name < names[j].Name
+	})
+
+	c.MetricFilters = make([][]string, 0, len(names))
+	for _, n := range names {
+		c.MetricFilters = append(c.MetricFilters, n.Filter)
+	}
+
+	buf, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("unable to encode %s names: %w", checkMetricFilterAttr, err)
+	}
+	c.Config[apiCheckMetricFilterNames] = string(buf)
+
+	return nil
+}
+
+// checkMetricFiltersToState returns the metric_filter blocks of a check read
+// from the API.  Each filter takes the name and priority recorded for it.
+// Filters without one, such as those of checks created outside of Terraform,
+// are given a generated name and a priority that keeps them in API order.
+// The recorded names are removed from the check's config.
+func checkMetricFiltersToState(c *circonusCheck, d *schema.ResourceData) ([]interface{}, error) {
+	var names []checkMetricFilterName
+	if v, found := c.Config[apiCheckMetricFilterNames]; found {
+		delete(c.Config, apiCheckMetricFilterNames)
+
+		if err := json.Unmarshal([]byte(v), &names); err != nil {
+			return nil, fmt.Errorf("unable to decode check config %q: %w", apiCheckMetricFilterNames, err)
+		}
+	}
+
+	used := make([]bool, len(names))
+	filters := make([]interface{}, 0, len(c.MetricFilters))
+	for i, f := range c.MetricFilters {
+		n, found := checkMetricFilterNameOf(f, names, used)
+		if !found {
+			if checkCertExpiryMetricFilterImplicit(d, f) {
+				continue
+			}
+
+			n = checkMetricFilterName{Name: defaultCheckMetricFilterName(i), Priority: defaultCheckMetricFilterPriority(i)}
+		}
+
+		attrs := metricFilterToState(f)
+		attrs[checkMetricFilterNameAttr] = n.Name
+		attrs[checkMetricFilterPriorityAttr] = n.Priority
+		filters = append(filters, attrs)
+	}
+
+	return filters, nil
+}
+
+// checkMetricFilterNameOf returns the first unused recorded name of filter f.
+func checkMetricFilterNameOf(f []string, names []checkMetricFilterName, used []bool) (checkMetricFilterName, bool) {
+	key := strings.Join(f, "\x00")
+	for i, n := range names {
+		if !used[i] && strings.Join(n.Filter, "\x00") == key {
+			used[i] = true
+			return n, true
+		}
+	}
+
+	return checkMetricFilterName{}, false
+}
+
+// defaultCheckMetricFilterName is the name given to the i'th filter of a check
+// that has no recorded name.
+func defaultCheckMetricFilterName(i int) string {
+	return fmt.Sprintf("filter_%d", i+1)
+}
+
+// defaultCheckMetricFilterPriority is the priority given to the i'th filter of
+// a check that has no recorded priority.  Priorities are spaced out so filters
+// can be inserted between them.
+func defaultCheckMetricFilterPriority(i int) int {
+	return (i + 1) * 10
+}
+
+// validateCheckMetricFilterName rejects names reserved for filters configured
+// without one.
+func validateCheckMetricFilterName(v interface{}, key string) (warnings []string, errors []error) {
+	if name := v.(string); checkMetricFilterDefaultNameRE.MatchString(name) {
+		errors = append(errors, fmt.Errorf("Invalid %s specified (%q): names of the form filter_N are given to filters without a name", checkMetricFilterNameAttr, name))
+	}
+
+	return warnings, errors
+}
+
+// hashCheckMetricFilter hashes a metric_filter block by its rule: type, regex,
+// tag query and comment, then the name and priority of a named filter.  The
+// generated name and priority of an unnamed filter are left out, so it hashes
+// the same in the state, where they are set, as in its configuration.
+func hashCheckMetricFilter(v interface{}) int {
+	m := v.(map[string]interface{})
+
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	fmt.Fprint(b, strings.Join(metricFilterToAPI(metricFilterHashAttrs(m)), "\x00"))
+
+	if name, _ := m[checkMetricFilterNameAttr].(string); name != "" && !checkMetricFilterDefaultNameRE.MatchString(name) {
+		fmt.Fprint(b, name)
+		fmt.Fprintf(b, "%x", m[checkMetricFilterPriorityAttr])
+	}
+
+	return hashcode.String(b.String())
+}
+
+// metricFilterHashAttrs returns the attributes of a metric_filter block that
+// are set, so a block read back from the state hashes like its configuration.
+func metricFilterHashAttrs(m map[string]interface{}) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(m))
+	for _, attr := range []string{checkMetricFilterTypeAttr, checkMetricFilterRegexAttr, checkMetricFilterTagQueryAttr, checkMetricFilterCommentAttr} {
+		if v, _ := m[attr].(string); v != "" {
+			attrs[attr] = v
+		}
+	}
+
+	return attrs
+}
+
+// metricFilterToAPI converts a metric_filter block to the API's metric filter
+// form: type, regex, optionally "tags" and the tag query, then the comment.
+func metricFilterToAPI(metricFilterAttrs map[string]interface{}) []string {
+	m := make([]string, 0, 5)
+	if av, found := metricFilterAttrs[checkMetricFilterTypeAttr]; found {
+		m = append(m, av.(string))
+	}
+	if av, found := metricFilterAttrs[checkMetricFilterRegexAttr]; found {
+		m = append(m, av.(string))
+	}
+	if av, found := metricFilterAttrs[checkMetricFilterTagQueryAttr]; found {
+		m = append(m, metricFilterTagsMarker, av.(string))
+	}
+
+	if av, found := metricFilterAttrs[checkMetricFilterCommentAttr]; found {
+		m = append(m, av.(string))
+	}
+
+	return m
+}
+
+// metricFilterToState converts an API metric filter to a metric_filter block.
+// A filter carries a tag query only when it has the five elements of that
+// form, so a comment of "tags" isn't mistaken for one.
+func metricFilterToState(m []string) map[string]interface{} {
+	metricFilterAttrs := map[string]interface{}{
+		checkMetricFilterTypeAttr:     "",
+		checkMetricFilterRegexAttr:    "",
+		checkMetricFilterTagQueryAttr: "",
+		checkMetricFilterCommentAttr:  "",
+	}
+
+	if len(m) > 0 {
+		metricFilterAttrs[checkMetricFilterTypeAttr] = m[0]
+	}
+	if len(m) > 1 {
+		metricFilterAttrs[checkMetricFilterRegexAttr] = m[1]
+	}
+
+	switch {
+	case len(m) == 5 && m[2] == metricFilterTagsMarker:
+		metricFilterAttrs[checkMetricFilterTagQueryAttr] = m[3]
+		metricFilterAttrs[checkMetricFilterCommentAttr] = m[4]
+	case len(m) == 4 && m[2] == metricFilterTagsMarker:
+		metricFilterAttrs[checkMetricFilterTagQueryAttr] = m[3]
+	case len(m) > 2:
+		metricFilterAttrs[checkMetricFilterCommentAttr] = m[2]
+	}
+
+	return metricFilterAttrs
+}
@@ -0,0 +1,188 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_CheckMetricFilters(t *testing.T) {
+	filter := func(name string, priority int, typ, regex, tagQuery, comment string) map[string]interface{} {
+		return map[string]interface{}{
+			string(checkMetricFilterCommentAttr):  comment,
+			string(checkMetricFilterNameAttr):     name,
+			string(checkMetricFilterPriorityAttr): priority,
+			string(checkMetricFilterRegexAttr):    regex,
+			string(checkMetricFilterTagQueryAttr): tagQuery,
+			string(checkMetricFilterTypeAttr):     typ,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filters  []interface{}
+		expected [][]string
+		state    []interface{}
+		fail     bool
+	}{
+		{
+			name: "priority order",
+			filters: []interface{}{
+				filter("deny_all", 100, "deny", ".*", "", "Deny everything else"),
+				filter("cpu", 10, "allow", "^cpu", "", ""),
+				filter("tagged", 20, "allow", ".*", "and(env:prod)", "Production"),
+			},
+			expected: [][]string{
+				{"allow", "^cpu", "tags", "", ""},
+				{"allow", ".*", "tags", "and(env:prod)", "Production"},
+				{"deny", ".*", "tags", "", "Deny everything else"},
+			},
+		},
+		{
+			name: "equal priorities in name order",
+			filters: []interface{}{
+				filter("b", 10, "allow", "^b", "", ""),
+				filter("a", 10, "allow", "^a", "", ""),
+			},
+			expected: [][]string{
+				{"allow", "^a", "tags", "", ""},
+				{"allow", "^b", "tags", "", ""},
+			},
+		},
+		{
+			name: "unnamed filters",
+			filters: []interface{}{
+				filter("", 0, "allow", "^a", "", ""),
+				filter("filter_1", 15, "deny", ".*", "", ""),
+				filter("", 0, "allow", "^c", "", ""),
+				filter("first", 0, "allow", "^b", "", ""),
+			},
+			expected: [][]string{
+				{"allow", "^b", "tags", "", ""},
+				{"allow", "^a", "tags", "", ""},
+				{"deny", ".*", "tags", "", ""},
+				{"allow", "^c", "tags", "", ""},
+			},
+			state: []interface{}{
+				filter("filter_2", 10, "allow", "^a", "", ""),
+				filter("filter_1", 15, "deny", ".*", "", ""),
+				filter("filter_3", 30, "allow", "^c", "", ""),
+				filter("first", 0, "allow", "^b", "", ""),
+			},
+		},
+		{
+			name: "priority without a name",
+			filters: []interface{}{
+				filter("", 10, "allow", "^a", "", ""),
+			},
+			fail: true,
+		},
+		{
+			name: "duplicate name",
+			filters: []interface{}{
+				filter("a", 10, "allow", "^a", "", ""),
+				filter("a", 20, "deny", ".*", "", ""),
+			},
+			fail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkMetricFiltersToAPI(&c, test.filters)
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(c.MetricFilters, test.expected) {
+				t.Fatalf("filters = %q, expected %q", c.MetricFilters, test.expected)
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+			state, err := checkMetricFiltersToState(&c, d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, found := c.Config[apiCheckMetricFilterNames]; found {
+				t.Fatalf("config %q not removed", apiCheckMetricFilterNames)
+			}
+
+			actual := schema.NewSet(schema.HashResource(schemaCheckMetricFilter().Elem.(*schema.Resource)), state)
+			expected := test.state
+			if expected == nil {
+				expected = test.filters
+			}
+			configured := schema.NewSet(schema.HashResource(schemaCheckMetricFilter().Elem.(*schema.Resource)), expected)
+			if !actual.Equal(configured) {
+				t.Fatalf("state = %v, expected %v", actual.List(), configured.List())
+			}
+		})
+	}
+}
+
+func Test_CheckMetricFiltersToStateUnnamed(t *testing.T) {
+	c := newCheck()
+	c.MetricFilters = [][]string{
+		{"allow", "^cpu", "tags"},
+		{"allow", ".*", "tags", "and(env:prod)", "Production"},
+		{"deny", ".*"},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+	state, err := checkMetricFiltersToState(&c, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"name": "filter_1", "priority": 10, "type": "allow", "regex": "^cpu", "tag_query": "", "comment": "tags"},
+		map[string]interface{}{"name": "filter_2", "priority": 20, "type": "allow", "regex": ".*", "tag_query": "and(env:prod)", "comment": "Production"},
+		map[string]interface{}{"name": "filter_3", "priority": 30, "type": "deny", "regex": ".*", "tag_query": "", "comment": ""},
+	}
+
+	if !reflect.DeepEqual(state, expected) {
+		t.Fatalf("state = %v, expected %v", state, expected)
+	}
+}
+
+func Test_HashCheckMetricFilter(t *testing.T) {
+	filter := func(name string, priority int) map[string]interface{} {
+		return map[string]interface{}{
+			string(checkMetricFilterCommentAttr):  "",
+			string(checkMetricFilterNameAttr):     name,
+			string(checkMetricFilterPriorityAttr): priority,
+			string(checkMetricFilterRegexAttr):    ".*",
+			string(checkMetricFilterTagQueryAttr): "",
+			string(checkMetricFilterTypeAttr):     "allow",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		state  map[string]interface{}
+		equal  bool
+	}{
+		{"unnamed", filter("", 0), filter("filter_1", 10), true},
+		{"named", filter("all", 10), filter("all", 10), true},
+		{"renamed", filter("all", 10), filter("filter_1", 10), false},
+		{"priority changed", filter("all", 20), filter("all", 10), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if equal := hashCheckMetricFilter(test.config) == hashCheckMetricFilter(test.state); equal != test.equal {
+				t.Fatalf("hashes equal = %t, expected %t", equal, test.equal)
+			}
+		})
+	}
+}
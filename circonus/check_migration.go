@@ -0,0 +1,768 @@
+package circonus
+
+// Migrating a check moves the objects that reference its checks, rule sets,
+// graphs and dashboards, to the checks that replace them.  It runs when a
+// check with `migrate` set changes type, which replaces its check bundle, or
+// moves off some of its collectors, which replaces the checks on those
+// collectors.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.pending_migration.* resource attribute names.
+	checkPendingMigrationBundlesAttr    = "check_bundles"
+	checkPendingMigrationChecksAttr     = "checks"
+	checkPendingMigrationCollectorsAttr = "collectors"
+	checkPendingMigrationCopiesAttr     = "rule_set_copies"
+	checkPendingMigrationUUIDsAttr      = "uuids"
+)
+
+var checkPendingMigrationDescriptions = attrDescrs{
+	checkPendingMigrationBundlesAttr:    "The check bundles replaced by the migration, deleted once it completes",
+	checkPendingMigrationChecksAttr:     "The checks being migrated",
+	checkPendingMigrationCollectorsAttr: "The collector of each check of the bundles before the migration",
+	checkPendingMigrationCopiesAttr:     "The rule sets copied so far, mapped to their copies",
+	checkPendingMigrationUUIDsAttr:      "The UUID of each check of the bundles before the migration",
+}
+
+// checkChangeReader is satisfied by both *schema.ResourceData and
+// *schema.ResourceDiff so a migration can be planned and applied from the
+// same state.
+type checkChangeReader interface {
+	GetChange(key string) (interface{}, interface{})
+}
+
+// checkMigrationTarget is the check that replaces a migrated check, and the
+// collector it runs on.  Only the collector is known when the migration is
+// planned, before the check exists.
+type checkMigrationTarget struct {
+	CID       string
+	UUID      string
+	Collector string
+}
+
+// checkMigration holds the dependents of the checks being migrated, loaded
+// before the checks are replaced.
+type checkMigration struct {
+	// checks are the CIDs of the checks being migrated.
+	checks []string
+
+	// collectors maps every check of the bundle before the change to its
+	// collector, and uuids to its UUID.
+	collectors map[string]string
+	uuids      map[string]string
+
+	// bundles are the check bundles replaced by the migration, deleted once
+	// it completes, and copies maps the rule sets copied so far to their
+	// copies.  Both are kept in pending_migration until then so that an
+	// apply failing part way through is resumed by the next one.
+	bundles []string
+	copies  map[string]string
+
+	ruleSets   []api.RuleSet
+	graphs     []api.Graph
+	dashboards []api.Dashboard
+}
+
+// checkMigrationRuleSet is what migrating a rule set does: copy it to its
+// target check, or skip it and say why.
+type checkMigrationRuleSet struct {
+	old  api.RuleSet
+	copy api.RuleSet
+
+	// collector is the collector of the check the rule set is copied to.
+	collector string
+
+	// parent is the migrated rule set whose copy is the parent of the copy,
+	// and sameAs the rule set copied to the same check as an identical copy.
+	parent string
+	sameAs string
+	skip   string
+}
+
+// checkPendingMigration returns the migration an earlier apply of the check
+// didn't complete, recorded in its state, or an empty migration.
+func checkPendingMigration(d checkChangeReader) (*checkMigration, error) {
+	m := &checkMigration{
+		collectors: make(map[string]string),
+		uuids:      make(map[string]string),
+		copies:     make(map[string]string),
+	}
+
+	o, _ := d.GetChange(checkOutPendingMigrationAttr)
+	l, _ := o.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return m, nil
+	}
+
+	attrs := newInterfaceMap(l[0])
+	bundles, _ := attrs[string(checkPendingMigrationBundlesAttr)].([]interface{})
+	var err error
+	if m.bundles, err = interfaceList(bundles).List(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", checkOutPendingMigrationAttr, err)
+	}
+
+	checksRaw, _ := attrs[string(checkPendingMigrationChecksAttr)].([]interface{})
+	checks, err := interfaceList(checksRaw).List()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", checkOutPendingMigrationAttr, err)
+	}
+
+	m.add(checks, attrs.CollectMap(checkPendingMigrationCollectorsAttr), attrs.CollectMap(checkPendingMigrationUUIDsAttr))
+	for cid, copyCID := range attrs.CollectMap(checkPendingMigrationCopiesAttr) {
+		m.copies[cid] = copyCID
+	}
+
+	return m, nil
+}
+
+// add migrates checks too, given the collector and UUID of each check of the
+// bundle before the change.
+func (m *checkMigration) add(checks []string, collectors, uuids map[string]string) {
+	for cid, collector := range collectors {
+		m.collectors[cid] = collector
+	}
+
+	for cid, uuid := range uuids {
+		m.uuids[cid] = uuid
+	}
+
+	migrated := m.migrated()
+	for _, cid := range checks {
+		if _, found := migrated[cid]; !found {
+			m.checks = append(m.checks, cid)
+		}
+	}
+	sort.Strings(m.checks)
+}
+
+// complete forgets the migrated checks once their dependents are migrated,
+// leaving the replaced check bundles to delete.
+func (m *checkMigration) complete() {
+	m.checks = nil
+	m.collectors = make(map[string]string)
+	m.uuids = make(map[string]string)
+	m.copies = make(map[string]string)
+	m.ruleSets, m.graphs, m.dashboards = nil, nil, nil
+}
+
+// pendingToState returns what is left of the migration as a
+// pending_migration attribute value.
+func (m *checkMigration) pendingToState() []interface{} {
+	if len(m.checks) == 0 && len(m.bundles) == 0 {
+		return []interface{}{}
+	}
+
+	stringMap := func(m map[string]string) map[string]interface{} {
+		v := make(map[string]interface{}, len(m))
+		for k, s := range m {
+			v[k] = s
+		}
+		return v
+	}
+
+	stringList := func(l []string) []interface{} {
+		v := make([]interface{}, 0, len(l))
+		for _, s := range l {
+			v = append(v, s)
+		}
+		return v
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			string(checkPendingMigrationBundlesAttr):    stringList(m.bundles),
+			string(checkPendingMigrationChecksAttr):     stringList(m.checks),
+			string(checkPendingMigrationCollectorsAttr): stringMap(m.collectors),
+			string(checkPendingMigrationCopiesAttr):     stringMap(m.copies),
+			string(checkPendingMigrationUUIDsAttr):      stringMap(m.uuids),
+		},
+	}
+}
+
+// deleteBundles deletes the check bundles replaced by the migration.
+func (m *checkMigration) deleteBundles(ctx context.Context, ctxt *providerContext) error {
+	for len(m.bundles) > 0 {
+		cid := m.bundles[0]
+		err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+			_, err := client.Delete(cid)
+			return err
+		})
+		if err != nil && !apiNotFound(err) {
+			return fmt.Errorf("check bundle %s was replaced but could not be deleted: %w", cid, err)
+		}
+
+		m.bundles = m.bundles[1:]
+	}
+
+	return nil
+}
+
+// checkMigrationSources returns the checks of the bundle's state that a
+// change migrates, those on collectors other than kept.  kept is nil when the
+// bundle is replaced, migrating all of them.  It also returns the collector
+// and UUID of every check in the state.
+func checkMigrationSources(d checkChangeReader, kept []string) ([]string, map[string]string, map[string]string) {
+	oldByCollector, _ := d.GetChange(checkOutByCollectorAttr)
+	oldChecks, _ := d.GetChange(checkOutChecksAttr)
+	oldUUIDs, _ := d.GetChange(checkOutCheckUUIDsAttr)
+
+	collectors := make(map[string]string)
+	byCollector, _ := oldByCollector.(map[string]interface{})
+	for collector, v := range byCollector {
+		if cid, ok := v.(string); ok && cid != "" {
+			collectors[cid] = collector
+		}
+	}
+
+	uuids := make(map[string]string)
+	checkList, _ := oldChecks.([]interface{})
+	uuidList, _ := oldUUIDs.([]interface{})
+	for i, v := range checkList {
+		cid, _ := v.(string)
+		if cid == "" || i >= len(uuidList) {
+			continue
+		}
+		uuids[cid], _ = uuidList[i].(string)
+	}
+
+	keep := make(map[string]struct{}, len(kept))
+	for _, collector := range kept {
+		keep[collector] = struct{}{}
+	}
+
+	checks := make([]string, 0, len(collectors))
+	for cid, collector := range collectors {
+		if _, found := keep[collector]; !found {
+			checks = append(checks, cid)
+		}
+	}
+	sort.Strings(checks)
+
+	return checks, collectors, uuids
+}
+
+// Load loads the rule sets, graphs and dashboards referencing the migrated
+// checks.  Rule sets in skip and those managed by a cert_expiry block are left
+// to the check managing them.  Rule sets are searched by check, one call per
+// migrated check, but graphs and dashboards can't be searched by the checks
+// they reference: every graph and dashboard of the account is fetched, which
+// is the expensive part of planning or applying a migration.
+func (m *checkMigration) Load(ctx context.Context, ctxt *providerContext, skip []string) error {
+	skipped := make(map[string]struct{}, len(skip))
+	for _, cid := range skip {
+		skipped[cid] = struct{}{}
+	}
+
+	for _, checkCID := range m.checks {
+		filter := api.SearchFilterType{"f_check": []string{checkCID}}
+		var ruleSets *[]api.RuleSet
+		err := ctxt.apiRequest(ctx, http.MethodGet, config.RuleSetPrefix, func(client *api.API) (err error) {
+			ruleSets, err = client.SearchRuleSets(nil, &filter)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("unable to search the rule sets of check %s: %w", checkCID, err)
+		}
+
+		if ruleSets == nil {
+			continue
+		}
+
+		for _, rs := range *ruleSets {
+			if _, found := skipped[rs.CID]; found || rs.CheckCID != checkCID || isCheckCertExpiryRuleSet(&rs) {
+				continue
+			}
+			m.ruleSets = append(m.ruleSets, rs)
+		}
+	}
+
+	var graphs *[]api.Graph
+	err := ctxt.apiRequest(ctx, http.MethodGet, config.GraphPrefix, func(client *api.API) (err error) {
+		graphs, err = client.FetchGraphs()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to fetch graphs: %w", err)
+	}
+
+	if graphs != nil {
+		for _, g := range *graphs {
+			if m.graphDatapoints(&g, nil) > 0 {
+				m.graphs = append(m.graphs, g)
+			}
+		}
+	}
+
+	var dashboards *[]api.Dashboard
+	err = ctxt.apiRequest(ctx, http.MethodGet, config.DashboardPrefix, func(client *api.API) (err error) {
+		dashboards, err = client.FetchDashboards()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to fetch dashboards: %w", err)
+	}
+
+	if dashboards != nil {
+		for _, db := range *dashboards {
+			if m.dashboardWidgets(&db, nil) > 0 {
+				m.dashboards = append(m.dashboards, db)
+			}
+		}
+	}
+
+	sort.Slice(m.ruleSets, func(i, j int) bool { return m.ruleSets[i].CID < m.ruleSets[j].CID })
+	sort.Slice(m.graphs, func(i, j int) bool { return m.graphs[i].CID < m.graphs[j].CID })
+	sort.Slice(m.dashboards, func(i, j int) bool { return m.dashboards[i].CID < m.dashboards[j].CID })
+
+	return nil
+}
+
+// Plan describes what migrating to the checks targets, collecting metrics,
+// does to each dependent.  metrics is nil when every metric is collected.
+// Targets are named by their collector, so the plan made before the checks
+// exist is the one recorded once they are migrated.
+func (m *checkMigration) Plan(targets map[string]checkMigrationTarget, metrics map[string]struct{}) []string {
+	plan := make([]string, 0, len(m.ruleSets)+len(m.graphs)+len(m.dashboards))
+
+	for _, e := range m.ruleSetCopies(targets, metrics) {
+		switch {
+		case e.skip != "":
+			plan = append(plan, fmt.Sprintf("rule set %s: skip, %s", e.old.CID, e.skip))
+		case e.sameAs != "":
+			plan = append(plan, fmt.Sprintf("rule set %s: skip, same as rule set %s on the check on %s", e.old.CID, e.sameAs, e.collector))
+		default:
+			plan = append(plan, fmt.Sprintf("rule set %s: copy from %s to the check on %s", e.old.CID, e.old.CheckCID, e.collector))
+		}
+	}
+
+	for _, g := range m.graphs {
+		plan = append(plan, checkMigrationCountEntry("graph", g.CID, "datapoint", m.graphDatapoints(&g, nil), m.graphDatapoints(&g, metrics)))
+	}
+
+	for _, db := range m.dashboards {
+		plan = append(plan, checkMigrationCountEntry("dashboard", db.CID, "widget", m.dashboardWidgets(&db, nil), m.dashboardWidgets(&db, metrics)))
+	}
+
+	return plan
+}
+
+// Apply copies the rule sets and repoints the graphs and dashboards of the
+// migrated checks to their targets, as described by Plan.  Rule sets already
+// copied, by an earlier apply that failed, aren't copied again.
+func (m *checkMigration) Apply(ctx context.Context, ctxt *providerContext, targets map[string]checkMigrationTarget, metrics map[string]struct{}) error {
+	if m.copies == nil {
+		m.copies = make(map[string]string, len(m.ruleSets))
+	}
+
+	for _, e := range m.ruleSetCopies(targets, metrics) {
+		if _, found := m.copies[e.old.CID]; found || e.skip != "" {
+			continue
+		}
+
+		if e.sameAs != "" {
+			m.copies[e.old.CID] = m.copies[e.sameAs]
+			continue
+		}
+
+		rs := newRuleSet()
+		rs.RuleSet = e.copy
+		if e.parent != "" {
+			if parent, found := m.copies[e.parent]; found {
+				rs.Parent = &parent
+			}
+		}
+
+		if err := rs.Create(ctx, ctxt); err != nil {
+			return fmt.Errorf("unable to copy rule set %s to check %s: %w", e.old.CID, e.copy.CheckCID, err)
+		}
+		m.copies[e.old.CID] = rs.CID
+	}
+
+	for _, old := range m.graphs {
+		g := old
+		g.Datapoints = append([]api.GraphDatapoint(nil), old.Datapoints...)
+		for i, dp := range g.Datapoints {
+			checkCID := checkCIDFromID(dp.CheckID)
+			target, found := targets[checkCID]
+			if !found || !checkMigrationCollects(metrics, dp.MetricName) {
+				continue
+			}
+
+			id, err := checkIDFromCID(target.CID)
+			if err != nil {
+				return err
+			}
+			g.Datapoints[i].CheckID = id
+		}
+
+		err := ctxt.apiRequest(ctx, http.MethodPut, g.CID, func(client *api.API) error {
+			_, err := client.UpdateGraph(&g)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("unable to repoint graph %s: %w", g.CID, err)
+		}
+	}
+
+	checksByUUID := m.checksByUUID()
+	for _, old := range m.dashboards {
+		db := old
+		db.Widgets = append([]api.DashboardWidget(nil), old.Widgets...)
+		for i, w := range db.Widgets {
+			target, found := targets[checksByUUID[w.Settings.CheckUUID]]
+			if !found || w.Settings.CheckUUID == "" || !checkMigrationCollects(metrics, w.Settings.MetricName) {
+				continue
+			}
+
+			db.Widgets[i].Settings.CheckUUID = target.UUID
+		}
+
+		err := ctxt.apiRequest(ctx, http.MethodPut, db.CID, func(client *api.API) error {
+			_, err := client.UpdateDashboard(&db)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("unable to repoint dashboard %s: %w", db.CID, err)
+		}
+	}
+
+	return nil
+}
+
+// ruleSetCopies returns what migrating each rule set to targets does, parents
+// first so the copies of their children can point at the copied parents.
+// Checks left without a check of their own share a target, so a rule set
+// identical to one already copied to its target is only copied once.
+func (m *checkMigration) ruleSetCopies(targets map[string]checkMigrationTarget, metrics map[string]struct{}) []checkMigrationRuleSet {
+	ruleSets := append([]api.RuleSet(nil), m.ruleSets...)
+	sort.SliceStable(ruleSets, func(i, j int) bool {
+		return ruleSets[i].Parent == nil && ruleSets[j].Parent != nil
+	})
+
+	entries := make([]checkMigrationRuleSet, 0, len(ruleSets))
+	copied := make(map[string]string, len(ruleSets))
+	copiedAs := make(map[string]string, len(ruleSets))
+	for _, old := range ruleSets {
+		e := checkMigrationRuleSet{old: old}
+
+		target, found := targets[old.CheckCID]
+		switch {
+		case !checkMigrationCollects(metrics, old.MetricName):
+			e.skip = fmt.Sprintf("metric %q is not collected", old.MetricName)
+		case !found:
+			e.skip = fmt.Sprintf("no check replaces %s", old.CheckCID)
+		default:
+			e.copy = old
+			e.copy.CID = ""
+			e.copy.Host = ""
+			e.copy.CheckCID = target.CID
+			e.collector = target.Collector
+			if old.Parent != nil {
+				if parent, found := copiedAs[*old.Parent]; found {
+					e.parent = parent
+					e.copy.Parent = nil
+				}
+			}
+
+			// The parents' copies don't exist yet, copies are compared
+			// by the rule sets their parents are copied from.
+			buf, _ := json.Marshal(e.copy)
+			key := e.parent + " " + e.collector + " " + string(buf)
+			if first, found := copied[key]; found {
+				e.sameAs = first
+			} else {
+				copied[key] = old.CID
+			}
+
+			copiedAs[old.CID] = old.CID
+			if e.sameAs != "" {
+				copiedAs[old.CID] = e.sameAs
+			}
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+// Targets returns the check of the bundle c that replaces each migrated
+// check.
+func (m *checkMigration) Targets(c *circonusCheck) map[string]checkMigrationTarget {
+	candidates := make([]checkMigrationTarget, 0, len(c.Checks))
+	for i, cid := range c.Checks {
+		t := checkMigrationTarget{CID: cid}
+		if i < len(c.CheckUUIDs) {
+			t.UUID = c.CheckUUIDs[i]
+		}
+		if i < len(c.Brokers) {
+			t.Collector = c.Brokers[i]
+		}
+		candidates = append(candidates, t)
+	}
+
+	return m.targets(candidates)
+}
+
+// PlannedTargets returns the collector of the check that replaces each
+// migrated check once the check runs on collectors.
+func (m *checkMigration) PlannedTargets(collectors []string) map[string]checkMigrationTarget {
+	candidates := make([]checkMigrationTarget, 0, len(collectors))
+	for _, collector := range collectors {
+		candidates = append(candidates, checkMigrationTarget{Collector: collector})
+	}
+
+	return m.targets(candidates)
+}
+
+// targets matches each migrated check with one of candidates.  A check is
+// replaced by the candidate on the same collector, then by a candidate on a
+// collector the bundle didn't run on before, in collector order.  The
+// candidate on the first collector replaces any check left over.
+func (m *checkMigration) targets(candidates []checkMigrationTarget) map[string]checkMigrationTarget {
+	targets := make(map[string]checkMigrationTarget, len(m.checks))
+	if len(candidates) == 0 {
+		return targets
+	}
+
+	byCollector := make(map[string]checkMigrationTarget, len(candidates))
+	collectors := make([]string, 0, len(candidates))
+	for _, t := range candidates {
+		byCollector[t.Collector] = t
+		collectors = append(collectors, t.Collector)
+	}
+	sort.Strings(collectors)
+
+	previous := make(map[string]struct{}, len(m.collectors))
+	for _, collector := range m.collectors {
+		previous[collector] = struct{}{}
+	}
+
+	fresh := make([]string, 0, len(byCollector))
+	for collector := range byCollector {
+		if _, found := previous[collector]; !found {
+			fresh = append(fresh, collector)
+		}
+	}
+	sort.Strings(fresh)
+
+	unmatched := make([]string, 0, len(m.checks))
+	for _, cid := range m.checks {
+		if t, found := byCollector[m.collectors[cid]]; found {
+			targets[cid] = t
+			continue
+		}
+		unmatched = append(unmatched, cid)
+	}
+
+	sort.Slice(unmatched, func(i, j int) bool {
+		return m.collectors[unmatched[i]] < m.collectors[unmatched[j]]
+	})
+
+	for i, cid := range unmatched {
+		if i < len(fresh) {
+			targets[cid] = byCollector[fresh[i]]
+			continue
+		}
+
+		targets[cid] = byCollector[collectors[0]]
+	}
+
+	return targets
+}
+
+// graphDatapoints returns the number of datapoints of g that reference a
+// migrated check and a metric in metrics.
+func (m *checkMigration) graphDatapoints(g *api.Graph, metrics map[string]struct{}) int {
+	migrated := m.migrated()
+
+	n := 0
+	for _, dp := range g.Datapoints {
+		if dp.CheckID == 0 {
+			continue
+		}
+
+		if _, found := migrated[checkCIDFromID(dp.CheckID)]; found && checkMigrationCollects(metrics, dp.MetricName) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// dashboardWidgets returns the number of widgets of db that reference a
+// migrated check and a metric in metrics.
+func (m *checkMigration) dashboardWidgets(db *api.Dashboard, metrics map[string]struct{}) int {
+	migrated := m.migrated()
+	checksByUUID := m.checksByUUID()
+
+	n := 0
+	for _, w := range db.Widgets {
+		if w.Settings.CheckUUID == "" {
+			continue
+		}
+
+		if _, found := migrated[checksByUUID[w.Settings.CheckUUID]]; found && checkMigrationCollects(metrics, w.Settings.MetricName) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (m *checkMigration) migrated() map[string]struct{} {
+	migrated := make(map[string]struct{}, len(m.checks))
+	for _, cid := range m.checks {
+		migrated[cid] = struct{}{}
+	}
+
+	return migrated
+}
+
+func (m *checkMigration) checksByUUID() map[string]string {
+	checks := make(map[string]string, len(m.uuids))
+	for cid, uuid := range m.uuids {
+		if uuid != "" {
+			checks[uuid] = cid
+		}
+	}
+
+	return checks
+}
+
+// checkMigrationMetrics returns the names of the metrics the check c
+// collects, or nil when it collects every metric its filters allow.
+func checkMigrationMetrics(c *circonusCheck) map[string]struct{} {
+	if c == nil || len(c.Metrics) == 0 {
+		return nil
+	}
+
+	metrics := make(map[string]struct{}, len(c.Metrics))
+	for _, metric := range c.Metrics {
+		metrics[canonicalMetricName(metric.Name)] = struct{}{}
+	}
+
+	return metrics
+}
+
+// checkMigrationCollects reports whether metric is in metrics.  Dependents
+// matching metrics by pattern, without a metric name, are always migrated.
+func checkMigrationCollects(metrics map[string]struct{}, metric string) bool {
+	if metrics == nil || metric == "" {
+		return true
+	}
+
+	_, found := metrics[canonicalMetricName(metric)]
+
+	return found
+}
+
+func checkMigrationCountEntry(kind, cid, noun string, total, repointed int) string {
+	if repointed == 0 {
+		return fmt.Sprintf("%s %s: skip, none of its %d %ss' metrics are collected", kind, cid, total, noun)
+	}
+
+	entry := fmt.Sprintf("%s %s: repoint %d %s", kind, cid, repointed, noun)
+	if repointed > 1 {
+		entry += "s"
+	}
+
+	if skipped := total - repointed; skipped > 0 {
+		entry += fmt.Sprintf(", skip %d", skipped)
+	}
+
+	return entry
+}
+
+// checkMigrationPlanToState returns a migration plan as a migration_plan
+// attribute value.
+func checkMigrationPlanToState(plan []string) []interface{} {
+	l := make([]interface{}, 0, len(plan))
+	for _, entry := range plan {
+		l = append(l, entry)
+	}
+
+	return l
+}
+
+// checkMigrationSkippedRuleSets returns the rule sets the check manages itself.
+func checkMigrationSkippedRuleSets(d checkChangeReader) ([]string, error) {
+	o, _ := d.GetChange(checkOutCertExpiryRuleSetsAttr)
+	l, _ := o.([]interface{})
+
+	return interfaceList(l).List()
+}
+
+func checkCIDFromID(id uint) string {
+	return fmt.Sprintf("%s/%d", config.CheckPrefix, id)
+}
+
+func checkIDFromCID(cid string) (uint, error) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(cid, config.CheckPrefix+"/"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check ID %q: %w", cid, err)
+	}
+
+	return uint(id), nil
+}
+
+// schemaCheckPendingMigration returns the schema of circonus_check's
+// pending_migration attribute.
+func schemaCheckPendingMigration() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkPendingMigrationDescriptions, map[schemaAttr]*schema.Schema{
+				checkPendingMigrationBundlesAttr: {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				checkPendingMigrationChecksAttr: {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				checkPendingMigrationCollectorsAttr: {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				checkPendingMigrationCopiesAttr: {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				checkPendingMigrationUUIDsAttr: {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			}),
+		},
+	}
+}
+
+// schemaCheckMigrationPlan returns the schema of circonus_check's
+// migration_plan attribute.
+func schemaCheckMigrationPlan() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
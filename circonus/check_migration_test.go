@@ -0,0 +1,407 @@
+package circonus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testCheckChangeReader returns the old and new values of each key.
+type testCheckChangeReader map[string][2]interface{}
+
+func (r testCheckChangeReader) GetChange(key string) (interface{}, interface{}) {
+	return r[key][0], r[key][1]
+}
+
+func Test_CheckMigrationSources(t *testing.T) {
+	d := testCheckChangeReader{
+		checkOutByCollectorAttr: {map[string]interface{}{"/broker/1": "/check/11", "/broker/2": "/check/12"}, nil},
+		checkOutChecksAttr:      {[]interface{}{"/check/11", "/check/12"}, nil},
+		checkOutCheckUUIDsAttr:  {[]interface{}{"uuid-11", "uuid-12"}, nil},
+	}
+
+	wantCollectors := map[string]string{"/check/11": "/broker/1", "/check/12": "/broker/2"}
+	wantUUIDs := map[string]string{"/check/11": "uuid-11", "/check/12": "uuid-12"}
+
+	tests := []struct {
+		name   string
+		kept   []string
+		checks []string
+	}{
+		{"collector moved", []string{"/broker/2", "/broker/3"}, []string{"/check/11"}},
+		{"bundle replaced", nil, []string{"/check/11", "/check/12"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checks, collectors, uuids := checkMigrationSources(d, test.kept)
+
+			if !reflect.DeepEqual(checks, test.checks) {
+				t.Errorf("expected checks %v, got %v", test.checks, checks)
+			}
+
+			if !reflect.DeepEqual(collectors, wantCollectors) {
+				t.Errorf("expected collectors %v, got %v", wantCollectors, collectors)
+			}
+
+			if !reflect.DeepEqual(uuids, wantUUIDs) {
+				t.Errorf("expected uuids %v, got %v", wantUUIDs, uuids)
+			}
+		})
+	}
+}
+
+func Test_CheckMigrationTargets(t *testing.T) {
+	m := &checkMigration{
+		checks:     []string{"/check/11", "/check/12", "/check/13"},
+		collectors: map[string]string{"/check/11": "/broker/1", "/check/12": "/broker/2", "/check/13": "/broker/3"},
+	}
+
+	tests := []struct {
+		name    string
+		check   circonusCheck
+		targets map[string]checkMigrationTarget
+	}{
+		{
+			name: "same collectors",
+			check: circonusCheck{CheckBundle: api.CheckBundle{
+				Brokers:    []string{"/broker/1", "/broker/2", "/broker/3"},
+				Checks:     []string{"/check/21", "/check/22", "/check/23"},
+				CheckUUIDs: []string{"uuid-21", "uuid-22", "uuid-23"},
+			}},
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/21", "uuid-21", "/broker/1"},
+				"/check/12": {"/check/22", "uuid-22", "/broker/2"},
+				"/check/13": {"/check/23", "uuid-23", "/broker/3"},
+			},
+		},
+		{
+			name: "new collectors",
+			check: circonusCheck{CheckBundle: api.CheckBundle{
+				Brokers:    []string{"/broker/2", "/broker/5", "/broker/4"},
+				Checks:     []string{"/check/22", "/check/25", "/check/24"},
+				CheckUUIDs: []string{"uuid-22", "uuid-25", "uuid-24"},
+			}},
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/24", "uuid-24", "/broker/4"},
+				"/check/12": {"/check/22", "uuid-22", "/broker/2"},
+				"/check/13": {"/check/25", "uuid-25", "/broker/5"},
+			},
+		},
+		{
+			name: "fewer new collectors",
+			check: circonusCheck{CheckBundle: api.CheckBundle{
+				Brokers:    []string{"/broker/5", "/broker/4"},
+				Checks:     []string{"/check/25", "/check/24"},
+				CheckUUIDs: []string{"uuid-25", "uuid-24"},
+			}},
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/24", "uuid-24", "/broker/4"},
+				"/check/12": {"/check/25", "uuid-25", "/broker/5"},
+				"/check/13": {"/check/24", "uuid-24", "/broker/4"},
+			},
+		},
+		{
+			name: "fewer collectors",
+			check: circonusCheck{CheckBundle: api.CheckBundle{
+				Brokers:    []string{"/broker/4"},
+				Checks:     []string{"/check/24"},
+				CheckUUIDs: []string{"uuid-24"},
+			}},
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/24", "uuid-24", "/broker/4"},
+				"/check/12": {"/check/24", "uuid-24", "/broker/4"},
+				"/check/13": {"/check/24", "uuid-24", "/broker/4"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if targets := m.Targets(&test.check); !reflect.DeepEqual(targets, test.targets) {
+				t.Errorf("expected targets %v, got %v", test.targets, targets)
+			}
+
+			// The migration is planned before the checks exist, by their
+			// collectors alone.
+			planned := m.PlannedTargets(test.check.Brokers)
+			for cid, target := range test.targets {
+				if planned[cid] != (checkMigrationTarget{Collector: target.Collector}) {
+					t.Errorf("expected check %s to be planned on %s, got %v", cid, target.Collector, planned[cid])
+				}
+			}
+		})
+	}
+}
+
+func Test_CheckMigrationPlan(t *testing.T) {
+	m := &checkMigration{
+		checks:     []string{"/check/11"},
+		collectors: map[string]string{"/check/11": "/broker/1"},
+		uuids:      map[string]string{"/check/11": "uuid-11"},
+		ruleSets: []api.RuleSet{
+			{CID: "/rule_set/1", CheckCID: "/check/11", MetricName: "duration"},
+			{CID: "/rule_set/2", CheckCID: "/check/11", MetricName: "code"},
+			{CID: "/rule_set/3", CheckCID: "/check/11", MetricPattern: "^dur"},
+		},
+		graphs: []api.Graph{
+			{CID: "/graph/a", Datapoints: []api.GraphDatapoint{
+				{CheckID: 11, MetricName: "duration"},
+				{CheckID: 11, MetricName: "code"},
+				{CheckID: 99, MetricName: "duration"},
+			}},
+			{CID: "/graph/b", Datapoints: []api.GraphDatapoint{
+				{CheckID: 11, MetricName: "code"},
+			}},
+		},
+		dashboards: []api.Dashboard{
+			{CID: "/dashboard/1", Widgets: []api.DashboardWidget{
+				{Settings: api.DashboardWidgetSettings{CheckUUID: "uuid-11", MetricName: "duration"}},
+				{Settings: api.DashboardWidgetSettings{CheckUUID: "uuid-11", MetricName: "duration|ST[env:prod]"}},
+				{Settings: api.DashboardWidgetSettings{CheckUUID: "uuid-99", MetricName: "duration"}},
+			}},
+		},
+	}
+
+	targets := map[string]checkMigrationTarget{"/check/11": {"/check/21", "uuid-21", "/broker/1"}}
+
+	tests := []struct {
+		name    string
+		metrics map[string]struct{}
+		plan    []string
+	}{
+		{
+			name:    "some metrics",
+			metrics: map[string]struct{}{"duration": {}, "duration|ST[env:prod]": {}},
+			plan: []string{
+				"rule set /rule_set/1: copy from /check/11 to the check on /broker/1",
+				`rule set /rule_set/2: skip, metric "code" is not collected`,
+				"rule set /rule_set/3: copy from /check/11 to the check on /broker/1",
+				"graph /graph/a: repoint 1 datapoint, skip 1",
+				"graph /graph/b: skip, none of its 1 datapoints' metrics are collected",
+				"dashboard /dashboard/1: repoint 2 widgets",
+			},
+		},
+		{
+			name: "all metrics",
+			plan: []string{
+				"rule set /rule_set/1: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/2: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/3: copy from /check/11 to the check on /broker/1",
+				"graph /graph/a: repoint 2 datapoints",
+				"graph /graph/b: repoint 1 datapoint",
+				"dashboard /dashboard/1: repoint 2 widgets",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if plan := m.Plan(targets, test.metrics); !reflect.DeepEqual(plan, test.plan) {
+				t.Errorf("expected plan:\n%q\ngot:\n%q", test.plan, plan)
+			}
+		})
+	}
+}
+
+func Test_CheckMigrationRuleSetCopies(t *testing.T) {
+	parent := func(cid string) *string { return &cid }
+
+	m := &checkMigration{
+		checks:     []string{"/check/11", "/check/12"},
+		collectors: map[string]string{"/check/11": "/broker/1", "/check/12": "/broker/2"},
+		ruleSets: []api.RuleSet{
+			{CID: "/rule_set/1", CheckCID: "/check/11", MetricName: "duration"},
+			{CID: "/rule_set/2", CheckCID: "/check/11", MetricName: "duration", Parent: parent("/rule_set/1")},
+			{CID: "/rule_set/3", CheckCID: "/check/12", MetricName: "duration"},
+			{CID: "/rule_set/4", CheckCID: "/check/12", MetricName: "duration", Parent: parent("/rule_set/3")},
+			{CID: "/rule_set/5", CheckCID: "/check/12", MetricName: "code"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		targets map[string]checkMigrationTarget
+		plan    []string
+	}{
+		{
+			name: "separate targets",
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/21", "uuid-21", "/broker/1"},
+				"/check/12": {"/check/22", "uuid-22", "/broker/2"},
+			},
+			plan: []string{
+				"rule set /rule_set/1: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/3: copy from /check/12 to the check on /broker/2",
+				"rule set /rule_set/5: copy from /check/12 to the check on /broker/2",
+				"rule set /rule_set/2: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/4: copy from /check/12 to the check on /broker/2",
+			},
+		},
+		{
+			name: "shared target",
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/21", "uuid-21", "/broker/1"},
+				"/check/12": {"/check/21", "uuid-21", "/broker/1"},
+			},
+			plan: []string{
+				"rule set /rule_set/1: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/3: skip, same as rule set /rule_set/1 on the check on /broker/1",
+				"rule set /rule_set/5: copy from /check/12 to the check on /broker/1",
+				"rule set /rule_set/2: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/4: skip, same as rule set /rule_set/2 on the check on /broker/1",
+			},
+		},
+		{
+			name: "no target",
+			targets: map[string]checkMigrationTarget{
+				"/check/11": {"/check/21", "uuid-21", "/broker/1"},
+			},
+			plan: []string{
+				"rule set /rule_set/1: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/3: skip, no check replaces /check/12",
+				"rule set /rule_set/5: skip, no check replaces /check/12",
+				"rule set /rule_set/2: copy from /check/11 to the check on /broker/1",
+				"rule set /rule_set/4: skip, no check replaces /check/12",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if plan := m.Plan(test.targets, nil); !reflect.DeepEqual(plan, test.plan) {
+				t.Errorf("expected plan:\n%q\ngot:\n%q", test.plan, plan)
+			}
+		})
+	}
+}
+
+func Test_CheckPendingMigration(t *testing.T) {
+	m, err := checkPendingMigration(testCheckChangeReader{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.add([]string{"/check/11"}, map[string]string{"/check/11": "/broker/1"}, map[string]string{"/check/11": "uuid-11"})
+	m.bundles = []string{"/check_bundle/1"}
+	m.copies["/rule_set/1"] = "/rule_set/2"
+
+	d := testCheckChangeReader{checkOutPendingMigrationAttr: {m.pendingToState(), nil}}
+	if pending, err := checkPendingMigration(d); err != nil || !reflect.DeepEqual(pending, m) {
+		t.Fatalf("expected %#v, got %#v", m, pending)
+	}
+
+	m.add([]string{"/check/11", "/check/10"}, nil, nil)
+	if expected := []string{"/check/10", "/check/11"}; !reflect.DeepEqual(m.checks, expected) {
+		t.Errorf("expected checks %v, got %v", expected, m.checks)
+	}
+
+	m.complete()
+	state := m.pendingToState()
+	if pending, err := checkPendingMigration(testCheckChangeReader{checkOutPendingMigrationAttr: {state, nil}}); err != nil || len(pending.checks) != 0 || !reflect.DeepEqual(pending.bundles, m.bundles) {
+		t.Errorf("expected only the bundles %v to be pending, got %#v", m.bundles, pending)
+	}
+
+	m.bundles = nil
+	if state := m.pendingToState(); len(state) != 0 {
+		t.Errorf("expected nothing pending, got %v", state)
+	}
+}
+
+func Test_CheckCustomizeDiffTypeChange(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "/check_bundle/1",
+		Attributes: map[string]string{
+			"id":                           "/check_bundle/1",
+			"type":                         "ping_icmp",
+			"collector.#":                  "1",
+			"collector.0.id":               "/broker/1",
+			"check_by_collector.%":         "1",
+			"check_by_collector./broker/1": "/check/11",
+			"checks.#":                     "1",
+			"checks.0":                     "/check/11",
+			"target":                       "api.circonus.com",
+		},
+	}
+
+	config := func(migrate bool) map[string]interface{} {
+		return map[string]interface{}{
+			"collector": []interface{}{map[string]interface{}{"id": "/broker/1"}},
+			"http": []interface{}{
+				map[string]interface{}{"url": "https://api.circonus.com/"},
+			},
+			"metric": []interface{}{
+				map[string]interface{}{"name": "duration", "type": "numeric"},
+			},
+			"migrate": migrate,
+			"target":  "api.circonus.com",
+		}
+	}
+
+	r := resourceCheck()
+	for _, migrate := range []bool{false, true} {
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config(migrate)), &providerContext{})
+		if err != nil {
+			t.Fatalf("migrate=%t: unexpected error: %v", migrate, err)
+		}
+
+		if diff.RequiresNew() == migrate {
+			t.Errorf("migrate=%t: expected RequiresNew %t", migrate, !migrate)
+		}
+
+		if !migrate {
+			continue
+		}
+
+		if typeDiff := diff.Attributes["type"]; typeDiff == nil || typeDiff.New != "http" {
+			t.Errorf("migrate=true: expected type to change to http, got %#v", typeDiff)
+		}
+
+		if planDiff := diff.Attributes["migration_plan.#"]; planDiff == nil || !planDiff.NewComputed {
+			t.Errorf("migrate=true: expected migration_plan to be computed, got %#v", planDiff)
+		}
+	}
+}
+
+func Test_CheckCustomizeDiffPendingMigration(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "/check_bundle/2",
+		Attributes: map[string]string{
+			"id":                                  "/check_bundle/2",
+			"type":                                "http",
+			"collector.#":                         "1",
+			"collector.0.id":                      "/broker/1",
+			"pending_migration.#":                 "1",
+			"pending_migration.0.check_bundles.#": "1",
+			"pending_migration.0.check_bundles.0": "/check_bundle/1",
+			"pending_migration.0.checks.#":        "0",
+			"target":                              "api.circonus.com",
+		},
+	}
+
+	config := map[string]interface{}{
+		"collector": []interface{}{map[string]interface{}{"id": "/broker/1"}},
+		"http": []interface{}{
+			map[string]interface{}{"url": "https://api.circonus.com/"},
+		},
+		"metric": []interface{}{
+			map[string]interface{}{"name": "duration", "type": "numeric"},
+		},
+		"target": "api.circonus.com",
+	}
+
+	diff, err := resourceCheck().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), &providerContext{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff.RequiresNew() {
+		t.Error("expected the check to be updated in place")
+	}
+
+	if pendingDiff := diff.Attributes["pending_migration.#"]; pendingDiff == nil || !pendingDiff.NewComputed {
+		t.Errorf("expected pending_migration to be computed, got %#v", pendingDiff)
+	}
+}
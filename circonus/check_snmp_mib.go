@@ -0,0 +1,190 @@
+package circonus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// snmpMIB maps the object descriptors defined in a set of MIB files to their
+// numeric OIDs.  Descriptors are indexed both on their own and qualified by
+// their module (e.g. IF-MIB::ifTable).
+type snmpMIB struct {
+	oids map[string]string
+}
+
+// snmpMIBObject is a MIB definition whose OID has not been resolved yet.
+type snmpMIBObject struct {
+	module string
+	name   string
+	parent string
+	subIDs []string
+}
+
+var (
+	snmpMIBStringRegexp     = regexp.MustCompile(`"[^"]*"`)
+	snmpMIBCommentRegexp    = regexp.MustCompile(`(?m)--.*?(?:--|$)`)
+	snmpMIBModuleRegexp     = regexp.MustCompile(`(?s)([A-Za-z][\w-]*)\s+DEFINITIONS\s*(?:[A-Z]+\s+)*::=\s*BEGIN(.*?)\bEND\b`)
+	snmpMIBObjectRegexp     = regexp.MustCompile(`(?s)\b([a-z][\w-]*)\s+(?:OBJECT\s+IDENTIFIER|OBJECT-TYPE|MODULE-IDENTITY|OBJECT-IDENTITY|NOTIFICATION-TYPE|OBJECT-GROUP|NOTIFICATION-GROUP|MODULE-COMPLIANCE|AGENT-CAPABILITIES)\b.*?::=\s*\{([^}]*)\}`)
+	snmpMIBSubIDRegexp      = regexp.MustCompile(`^(?:([A-Za-z][\w-]*)|([A-Za-z][\w-]*)?\((\d+)\)|(\d+))$`)
+	snmpNumericOIDRegexp    = regexp.MustCompile(`^\.?\d+(?:\.\d+)*$`)
+	snmpSymbolicOIDRegexp   = regexp.MustCompile(`^(?:([A-Za-z][\w-]*)::)?([A-Za-z][\w-]*)((?:\.\d+)*)$`)
+	snmpMIBWellKnownObjects = map[string]string{
+		"ccitt":           "0",
+		"iso":             "1",
+		"joint-iso-ccitt": "2",
+		"org":             "1.3",
+		"dod":             "1.3.6",
+		"internet":        "1.3.6.1",
+		"directory":       "1.3.6.1.1",
+		"mgmt":            "1.3.6.1.2",
+		"mib-2":           "1.3.6.1.2.1",
+		"transmission":    "1.3.6.1.2.1.10",
+		"experimental":    "1.3.6.1.3",
+		"private":         "1.3.6.1.4",
+		"enterprises":     "1.3.6.1.4.1",
+		"security":        "1.3.6.1.5",
+		"snmpV2":          "1.3.6.1.6",
+		"snmpDomains":     "1.3.6.1.6.1",
+		"snmpProxys":      "1.3.6.1.6.2",
+		"snmpModules":     "1.3.6.1.6.3",
+		"zeroDotZero":     "0.0",
+	}
+)
+
+// snmpOIDIsNumeric reports whether path is already in dotted decimal notation.
+func snmpOIDIsNumeric(path string) bool {
+	return snmpNumericOIDRegexp.MatchString(path)
+}
+
+// loadSNMPMIBs parses every MIB file found in dirs.  Subdirectories are not
+// searched.
+func loadSNMPMIBs(dirs []string) (*snmpMIB, error) {
+	objects := make([]snmpMIBObject, 0)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read MIB directory %q: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			buf, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("unable to read MIB file %q: %w", entry.Name(), err)
+			}
+
+			objects = append(objects, parseSNMPMIB(string(buf))...)
+		}
+	}
+
+	return resolveSNMPMIBObjects(objects), nil
+}
+
+// parseSNMPMIB extracts the OID definitions from the text of a MIB file.
+func parseSNMPMIB(text string) []snmpMIBObject {
+	text = snmpMIBStringRegexp.ReplaceAllString(text, `""`)
+	text = snmpMIBCommentRegexp.ReplaceAllString(text, "")
+
+	objects := make([]snmpMIBObject, 0)
+	for _, module := range snmpMIBModuleRegexp.FindAllStringSubmatch(text, -1) {
+		for _, def := range snmpMIBObjectRegexp.FindAllStringSubmatch(module[2], -1) {
+			obj := snmpMIBObject{
+				module: module[1],
+				name:   def[1],
+			}
+
+			valid := true
+			for i, tok := range strings.Fields(def[2]) {
+				m := snmpMIBSubIDRegexp.FindStringSubmatch(tok)
+				switch {
+				case m == nil:
+					valid = false
+				case m[1] != "" && i == 0:
+					obj.parent = m[1]
+				case m[1] != "":
+					valid = false
+				case m[3] != "":
+					obj.subIDs = append(obj.subIDs, m[3])
+				default:
+					obj.subIDs = append(obj.subIDs, m[4])
+				}
+			}
+
+			if valid && len(obj.subIDs) > 0 {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	return objects
+}
+
+// resolveSNMPMIBObjects resolves the parent of each object until no further
+// progress can be made.  Objects whose parent is never defined are dropped.
+func resolveSNMPMIBObjects(objects []snmpMIBObject) *snmpMIB {
+	mib := &snmpMIB{oids: make(map[string]string, len(snmpMIBWellKnownObjects)+2*len(objects))}
+	for name, oid := range snmpMIBWellKnownObjects {
+		mib.oids[name] = oid
+	}
+
+	for resolved := true; resolved && len(objects) > 0; {
+		resolved = false
+		pending := objects[:0]
+		for _, obj := range objects {
+			oid := strings.Join(obj.subIDs, ".")
+			if obj.parent != "" {
+				parentOID, found := mib.oids[obj.module+"::"+obj.parent]
+				if !found {
+					parentOID, found = mib.oids[obj.parent]
+				}
+				if !found {
+					pending = append(pending, obj)
+					continue
+				}
+				oid = parentOID + "." + oid
+			}
+
+			mib.oids[obj.module+"::"+obj.name] = oid
+			if _, found := mib.oids[obj.name]; !found {
+				mib.oids[obj.name] = oid
+			}
+			resolved = true
+		}
+		objects = pending
+	}
+
+	return mib
+}
+
+// Resolve returns the numeric OID of path.  path may already be numeric, or
+// be a descriptor, optionally qualified by its module and followed by
+// instance sub-identifiers (e.g. IF-MIB::ifHCInOctets.1).
+func (mib *snmpMIB) Resolve(path string) (string, error) {
+	if snmpOIDIsNumeric(path) {
+		return path, nil
+	}
+
+	m := snmpSymbolicOIDRegexp.FindStringSubmatch(path)
+	if m == nil {
+		return "", fmt.Errorf("invalid OID %q", path)
+	}
+
+	name := m[2]
+	if m[1] != "" {
+		name = m[1] + "::" + m[2]
+	}
+
+	oid, found := mib.oids[name]
+	if !found {
+		return "", fmt.Errorf("OID %q not found in the MIB files loaded", name)
+	}
+
+	return "." + oid + m[3], nil
+}
@@ -0,0 +1,139 @@
+package circonus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSNMPIfMIB = `
+IF-MIB DEFINITIONS ::= BEGIN
+
+IMPORTS
+    MODULE-IDENTITY, OBJECT-TYPE, Counter64, mib-2
+        FROM SNMPv2-SMI;
+
+ifMIB MODULE-IDENTITY
+    LAST-UPDATED "200006140000Z"
+    DESCRIPTION
+            "The MIB module to describe generic objects for network
+            interface sub-layers -- not a comment."
+    ::= { mib-2 31 }
+
+interfaces   OBJECT IDENTIFIER ::= { mib-2 2 }
+
+-- the Interfaces table OBJECT-TYPE ::= { bogus 1 }
+
+ifTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF IfEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A list of interface entries."
+    ::= { interfaces 2 }
+
+ifEntry OBJECT-TYPE
+    SYNTAX      IfEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    INDEX   { ifIndex }
+    ::= { ifTable 1 }
+
+ifIndex OBJECT-TYPE
+    SYNTAX      InterfaceIndex
+    MAX-ACCESS  read-only
+    STATUS      current
+    ::= { ifEntry 1 }
+
+ifMIBObjects OBJECT IDENTIFIER ::= { ifMIB 1 }
+
+ifXTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF IfXEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    ::= { ifMIBObjects 1 }
+
+ifXEntry OBJECT-TYPE
+    SYNTAX      IfXEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    ::= { ifXTable 1 }
+
+ifName OBJECT-TYPE
+    SYNTAX      DisplayString
+    MAX-ACCESS  read-only
+    STATUS      current
+    ::= { ifXEntry 1 }
+
+ifHCInOctets OBJECT-TYPE
+    SYNTAX      Counter64
+    MAX-ACCESS  read-only
+    STATUS      current
+    ::= { ifXEntry 6 }
+
+orphan OBJECT IDENTIFIER ::= { undefinedParent 7 }
+
+END
+`
+
+const testSNMPVendorMIB = `
+VENDOR-MIB DEFINITIONS ::= BEGIN
+vendor OBJECT IDENTIFIER ::= { iso org(3) dod(6) internet(1) private(4) enterprises(1) 318 }
+upsBattery OBJECT IDENTIFIER ::= { vendor 1 1 1 2 }
+END
+`
+
+func Test_SNMPMIBResolve(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{"IF-MIB.txt": testSNMPIfMIB, "VENDOR-MIB.txt": testSNMPVendorMIB} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mib, err := loadSNMPMIBs([]string{dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+		fail     bool
+	}{
+		{path: ".1.3.6.1.2.1.1.3.0", expected: ".1.3.6.1.2.1.1.3.0"},
+		{path: "ifTable", expected: ".1.3.6.1.2.1.2.2"},
+		{path: "ifIndex", expected: ".1.3.6.1.2.1.2.2.1.1"},
+		{path: "IF-MIB::ifHCInOctets", expected: ".1.3.6.1.2.1.31.1.1.1.6"},
+		{path: "IF-MIB::ifName.3", expected: ".1.3.6.1.2.1.31.1.1.1.1.3"},
+		{path: "upsBattery", expected: ".1.3.6.1.4.1.318.1.1.1.2"},
+		{path: "mib-2", expected: ".1.3.6.1.2.1"},
+		{path: "VENDOR-MIB::ifName", fail: true},
+		{path: "orphan", fail: true},
+		{path: "bogus", fail: true},
+		{path: "ifName.x", fail: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			oid, err := mib.Resolve(test.path)
+			if test.fail {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", oid)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if oid != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, oid)
+			}
+		})
+	}
+
+	if _, err := loadSNMPMIBs([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("expected an error for a missing MIB directory")
+	}
+}
@@ -0,0 +1,223 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.check_status.* resource attribute names.
+	checkStatusActiveAttr        = "active"
+	checkStatusActiveMetricsAttr = "active_metrics"
+	checkStatusCheckIDAttr       = "check_id"
+	checkStatusCollectorAttr     = "collector"
+	checkStatusErrorAttr         = "error"
+	checkStatusLastRunAttr       = "last_run"
+	checkStatusStatusAttr        = "status"
+
+	// Keys of a check's details reported by its collector.
+	apiCheckDetailActiveMetrics = config.Key("active_metrics")
+	apiCheckDetailError         = config.Key("error")
+	apiCheckDetailLastRun       = config.Key("last_run")
+	apiCheckDetailStatus        = config.Key("status")
+)
+
+// checkStatusPollInterval is how often a check is fetched while waiting for
+// its first run.
+var checkStatusPollInterval = 5 * time.Second
+
+var checkStatusDescriptions = attrDescrs{
+	checkStatusActiveAttr:        "Whether the check is active on the collector",
+	checkStatusActiveMetricsAttr: "The number of active metrics the check collects",
+	checkStatusCheckIDAttr:       "The check's ID on the collector",
+	checkStatusCollectorAttr:     "The collector the check runs on",
+	checkStatusErrorAttr:         "The error of the check's last run, if it failed",
+	checkStatusLastRunAttr:       "When the check last ran, in seconds since the epoch",
+	checkStatusStatusAttr:        "The status of the check as reported by the collector",
+}
+
+// checkStatus is the operational status of a check on one collector.
+type checkStatus struct {
+	Collector     string
+	CheckID       string
+	Active        bool
+	ActiveMetrics int
+	Error         string
+	LastRun       int
+	Status        string
+}
+
+func schemaCheckStatus() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkStatusDescriptions, map[schemaAttr]*schema.Schema{
+				checkStatusActiveAttr: {
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+				checkStatusActiveMetricsAttr: {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				checkStatusCheckIDAttr: {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				checkStatusCollectorAttr: {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				checkStatusErrorAttr: {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				checkStatusLastRunAttr: {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				checkStatusStatusAttr: {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			}),
+		},
+	}
+}
+
+// newCheckStatus returns the status of the check chk on collector.  The
+// details are check type specific, those a collector doesn't report are left
+// empty.
+func newCheckStatus(collector string, chk *api.Check) checkStatus {
+	s := checkStatus{
+		Collector: collector,
+		CheckID:   chk.CID,
+		Active:    chk.Active,
+		Error:     strings.TrimSpace(chk.Details[apiCheckDetailError]),
+		Status:    chk.Details[apiCheckDetailStatus],
+	}
+
+	if v, err := strconv.Atoi(chk.Details[apiCheckDetailActiveMetrics]); err == nil {
+		s.ActiveMetrics = v
+	}
+
+	if v, err := strconv.ParseFloat(chk.Details[apiCheckDetailLastRun], 64); err == nil {
+		s.LastRun = int(v)
+	}
+
+	return s
+}
+
+// Succeeded reports whether the check has run without an error.
+func (s checkStatus) Succeeded() bool {
+	return s.LastRun > 0 && s.Error == ""
+}
+
+// loadCheckStatuses fetches the check on each of the check bundle's
+// collectors.
+func loadCheckStatuses(ctx context.Context, ctxt *providerContext, c *circonusCheck) ([]checkStatus, error) {
+	statuses := make([]checkStatus, 0, len(c.Checks))
+	for i, cid := range c.Checks {
+		cid := cid
+		var chk *api.Check
+		err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+			chk, err = client.FetchCheck(api.CIDType(&cid))
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to load check %q: %w", cid, err)
+		}
+
+		collector := chk.BrokerCID
+		if collector == "" && i < len(c.Brokers) {
+			collector = c.Brokers[i]
+		}
+
+		statuses = append(statuses, newCheckStatus(collector, chk))
+	}
+
+	return statuses, nil
+}
+
+// checkStatusDiags reports the failure to load the status of check bundle cid
+// as a warning.
+func checkStatusDiags(cid string, err error) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Unable to load the check's status",
+			Detail:   fmt.Sprintf("The status of check %q could not be loaded, %s is left unchanged until the next refresh: %v", cid, checkOutStatusAttr, err),
+		},
+	}
+}
+
+func checkStatusesToState(statuses []checkStatus) []interface{} {
+	l := make([]interface{}, 0, len(statuses))
+	for _, s := range statuses {
+		l = append(l, map[string]interface{}{
+			string(checkStatusActiveAttr):        s.Active,
+			string(checkStatusActiveMetricsAttr): s.ActiveMetrics,
+			string(checkStatusCheckIDAttr):       s.CheckID,
+			string(checkStatusCollectorAttr):     s.Collector,
+			string(checkStatusErrorAttr):         s.Error,
+			string(checkStatusLastRunAttr):       s.LastRun,
+			string(checkStatusStatusAttr):        s.Status,
+		})
+	}
+
+	return l
+}
+
+// waitForCheckFirstRun polls the check until it has run successfully on every
+// collector.  It fails as soon as a collector reports an error, so a
+// misconfigured check fails the apply instead of being left in a bad state.
+// Transient API errors are retried at the next poll.
+func waitForCheckFirstRun(ctx context.Context, ctxt *providerContext, c *circonusCheck) error {
+	for {
+		statuses, err := loadCheckStatuses(ctx, ctxt, c)
+		if err != nil && !apiTransient(err) {
+			return err
+		}
+
+		pending := make([]string, 0, len(c.Brokers))
+		if err != nil {
+			tflog.Warn(ctx, "Unable to load the check's status, retrying", map[string]interface{}{logFieldError: err.Error()})
+			pending = append(pending, c.Brokers...)
+		}
+
+		for _, s := range statuses {
+			if s.Error != "" {
+				return fmt.Errorf("check %s failed on collector %s: %s", s.CheckID, s.Collector, s.Error)
+			}
+
+			if !s.Succeeded() {
+				pending = append(pending, s.Collector)
+			}
+		}
+
+		if err == nil && len(pending) == 0 {
+			return nil
+		}
+
+		tflog.Debug(ctx, "Waiting for the check's first run", map[string]interface{}{"collectors": pending})
+
+		t := time.NewTimer(checkStatusPollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("check did not complete a successful run on %s before the create timeout: %w", strings.Join(pending, ", "), ctx.Err())
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package circonus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+)
+
+func Test_NewCheckStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		details   api.CheckDetails
+		expected  checkStatus
+		succeeded bool
+	}{
+		{
+			name:     "not run yet",
+			details:  api.CheckDetails{},
+			expected: checkStatus{Collector: "/broker/1", CheckID: "/check/1", Active: true},
+		},
+		{
+			name: "successful run",
+			details: api.CheckDetails{
+				apiCheckDetailActiveMetrics: "12",
+				apiCheckDetailLastRun:       "1700000000.25",
+				apiCheckDetailStatus:        "good",
+			},
+			expected:  checkStatus{Collector: "/broker/1", CheckID: "/check/1", Active: true, ActiveMetrics: 12, LastRun: 1700000000, Status: "good"},
+			succeeded: true,
+		},
+		{
+			name: "failed run",
+			details: api.CheckDetails{
+				apiCheckDetailError:   "connection refused ",
+				apiCheckDetailLastRun: "1700000000",
+				apiCheckDetailStatus:  "bad",
+			},
+			expected: checkStatus{Collector: "/broker/1", CheckID: "/check/1", Active: true, Error: "connection refused", LastRun: 1700000000, Status: "bad"},
+		},
+		{
+			name: "unparsable details",
+			details: api.CheckDetails{
+				apiCheckDetailActiveMetrics: "many",
+				apiCheckDetailLastRun:       "yesterday",
+				config.Key("module"):        "ping_icmp",
+			},
+			expected: checkStatus{Collector: "/broker/1", CheckID: "/check/1", Active: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newCheckStatus("/broker/1", &api.Check{CID: "/check/1", Active: true, Details: test.details})
+			if s != test.expected {
+				t.Fatalf("status = %+v, expected %+v", s, test.expected)
+			}
+
+			if s.Succeeded() != test.succeeded {
+				t.Fatalf("Succeeded() = %t, expected %t", s.Succeeded(), test.succeeded)
+			}
+		})
+	}
+}
+
+func Test_CheckStatusDiags(t *testing.T) {
+	diags := checkStatusDiags("/check_bundle/1", errors.New("connection reset"))
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	if diags.HasError() {
+		t.Fatalf("expected a warning, got %v", diags)
+	}
+
+	if !strings.Contains(diags[0].Detail, "connection reset") {
+		t.Fatalf("expected the error in the detail, got %q", diags[0].Detail)
+	}
+}
@@ -0,0 +1,155 @@
+package circonus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultCollectorPort is the port collectors accept submissions on when
+	// the collector's details don't name one.
+	defaultCollectorPort = 43191
+
+	// defaultCheckStatsdPort is the UDP port collectors receive statsd on.
+	defaultCheckStatsdPort = 8125
+
+	// checkSecretLen is the number of random bytes in a generated secret.
+	checkSecretLen = 16
+)
+
+// collectorModuleSubmissionURL returns a submissionURL function for the push
+// check types submitted over HTTPS to the collector module named module.
+func collectorModuleSubmissionURL(module string) func(*circonusCheck, string, uint16) string {
+	return func(c *circonusCheck, host string, port uint16) string {
+		if len(c.CheckUUIDs) == 0 {
+			return ""
+		}
+
+		u := fmt.Sprintf("https://%s/module/%s/%s", net.JoinHostPort(host, strconv.Itoa(int(port))), module, c.CheckUUIDs[0])
+		if secret := c.Config[config.Secret]; secret != "" {
+			u += "/" + secret
+		}
+
+		return u
+	}
+}
+
+// collectorSubmissionAddress returns the host and port of the collector
+// instance that push-based checks submit to: the first active instance's
+// external host and port, falling back to its CN and port.
+func collectorSubmissionAddress(b *api.Broker) (string, uint16, bool) {
+	if len(b.Details) == 0 {
+		return "", 0, false
+	}
+
+	detail := b.Details[0]
+	for _, d := range b.Details {
+		if d.Status == "active" {
+			detail = d
+			break
+		}
+	}
+
+	if detail.ExternalHost != nil && *detail.ExternalHost != "" {
+		port := detail.ExternalPort
+		if port == 0 {
+			port = defaultCollectorPort
+		}
+
+		return *detail.ExternalHost, port, true
+	}
+
+	port := uint16(defaultCollectorPort)
+	if detail.Port != nil && *detail.Port != 0 {
+		port = *detail.Port
+	}
+
+	return detail.CN, port, true
+}
+
+// loadCheckSubmissionURL returns the URL metrics are pushed to for a push-based
+// check.  The URL returned by the API is used when there is one, otherwise it
+// is derived from the details of the check's first collector.  An empty string
+// is returned for poll-based checks.
+func loadCheckSubmissionURL(ctx context.Context, ctxt *providerContext, c *circonusCheck) (string, error) {
+	if u := c.Config[config.SubmissionURL]; u != "" {
+		return u, nil
+	}
+
+	t, found := checkTypeForAPIType(c.Type)
+	if !found || t.submissionURL == nil || len(c.Brokers) == 0 {
+		return "", nil
+	}
+
+	cid := c.Brokers[0]
+	b, err := ctxt.fetchCollector(ctx, cid)
+	if err != nil {
+		return "", fmt.Errorf("unable to load collector %q: %w", cid, err)
+	}
+
+	host, port, ok := collectorSubmissionAddress(b)
+	if !ok {
+		tflog.Warn(ctx, "Collector has no details, unable to build the submission URL", map[string]interface{}{"collector": cid})
+		return "", nil
+	}
+
+	return t.collectorSubmissionURL(c, host, port), nil
+}
+
+// collectorSubmissionURL returns the check's submission URL given the host and
+// port of its collector.
+func (t *checkType) collectorSubmissionURL(c *circonusCheck, host string, port uint16) string {
+	if t.submissionPort != 0 {
+		port = t.submissionPort
+	}
+
+	return t.submissionURL(c, host, port)
+}
+
+// fetchCollector returns the collector cid.  Collectors are cached for the
+// life of the provider so that refreshing many push-based checks on the same
+// collector only fetches it once.
+func (p *providerContext) fetchCollector(ctx context.Context, cid string) (*api.Broker, error) {
+	p.collectorsMu.Lock()
+	b, found := p.collectors[cid]
+	p.collectorsMu.Unlock()
+	if found {
+		return b, nil
+	}
+
+	err := p.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		b, err = client.FetchBroker(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.collectorsMu.Lock()
+	if p.collectors == nil {
+		p.collectors = make(map[string]*api.Broker)
+	}
+	p.collectors[cid] = b
+	p.collectorsMu.Unlock()
+
+	return b, nil
+}
+
+// newCheckSecret returns a random secret for the push-based check types.
+func newCheckSecret() (string, error) {
+	b := make([]byte, checkSecretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate a secret: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
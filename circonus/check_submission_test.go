@@ -0,0 +1,126 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+)
+
+func Test_CollectorSubmissionAddress(t *testing.T) {
+	externalHost := "trap.example.com"
+	port := uint16(43192)
+
+	tests := []struct {
+		name    string
+		details []api.BrokerDetail
+		host    string
+		port    uint16
+		ok      bool
+	}{
+		{
+			name: "no details",
+		},
+		{
+			name: "external host",
+			details: []api.BrokerDetail{
+				{CN: "broker1.example.com", Status: "active", ExternalHost: &externalHost, ExternalPort: 443},
+			},
+			host: "trap.example.com",
+			port: 443,
+			ok:   true,
+		},
+		{
+			name: "external host without port",
+			details: []api.BrokerDetail{
+				{CN: "broker1.example.com", Status: "active", ExternalHost: &externalHost},
+			},
+			host: "trap.example.com",
+			port: defaultCollectorPort,
+			ok:   true,
+		},
+		{
+			name: "cn of the active instance",
+			details: []api.BrokerDetail{
+				{CN: "broker1.example.com", Status: "unprovisioned"},
+				{CN: "broker2.example.com", Status: "active", Port: &port},
+			},
+			host: "broker2.example.com",
+			port: 43192,
+			ok:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, port, ok := collectorSubmissionAddress(&api.Broker{Details: test.details})
+			if ok != test.ok || host != test.host || port != test.port {
+				t.Errorf("expected %q, %d, %t, got %q, %d, %t", test.host, test.port, test.ok, host, port, ok)
+			}
+		})
+	}
+}
+
+func Test_CheckSubmissionURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkAPI apiCheckType
+		config   map[config.Key]string
+		expected string
+	}{
+		{
+			name:     "httptrap",
+			checkAPI: apiCheckTypeHTTPTrapAttr,
+			config:   map[config.Key]string{config.Secret: "12345"},
+			expected: "https://trap.example.com:443/module/httptrap/fa28a8b6-b1b8-4f3b-a4a5-fe0bc8c1b5b7/12345",
+		},
+		{
+			name:     "prometheus",
+			checkAPI: apiCheckTypePrometheusAttr,
+			config:   map[config.Key]string{},
+			expected: "https://trap.example.com:443/module/prometheus/fa28a8b6-b1b8-4f3b-a4a5-fe0bc8c1b5b7",
+		},
+		{
+			name:     "statsd",
+			checkAPI: apiCheckTypeStatsdAttr,
+			config:   map[config.Key]string{},
+			expected: "udp://trap.example.com:8125",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ct, found := checkTypeForAPIType(string(test.checkAPI))
+			if !found || ct.submissionURL == nil {
+				t.Fatalf("check type %q is not push-based", test.checkAPI)
+			}
+
+			c := newCheck()
+			c.Config = test.config
+			c.CheckUUIDs = []string{"fa28a8b6-b1b8-4f3b-a4a5-fe0bc8c1b5b7"}
+
+			if u := ct.collectorSubmissionURL(&c, "trap.example.com", 443); u != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, u)
+			}
+		})
+	}
+}
+
+func Test_NewCheckSecret(t *testing.T) {
+	validSecret := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+	a, err := newCheckSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := newCheckSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !validSecret.MatchString(a) || a == b {
+		t.Errorf("expected two different valid secrets, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,584 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// TLS resource attribute names, shared by the http and tcp check types.
+	checkTLSCertExpiryAttr    = "cert_expiry"
+	checkTLSExpectedNamesAttr = "expected_cert_names"
+	checkTLSMinVersionAttr    = "min_tls_version"
+	checkTLSSNIAttr           = "sni"
+	checkTLSVerifyModeAttr    = "verify_mode"
+
+	// cert_expiry.* resource attribute names.
+	checkCertExpiryContactGroupsAttr = "contact_groups"
+	checkCertExpiryDaysAttr          = "days"
+	checkCertExpirySeverityAttr      = "severity"
+
+	// Broker config keys of the TLS settings.  cert_expiry has none, it is
+	// managed by the provider.
+	apiTLSExpectedNames = config.Key("expected_cert_names")
+	apiTLSMinVersion    = config.Key("min_tls_version")
+	apiTLSSNI           = config.Key("sni")
+	apiTLSVerifyMode    = config.Key("verify_mode")
+
+	// The metric the cert_expiry rule set alerts on: the number of seconds
+	// until the certificate presented by the target expires.
+	checkCertExpiryMetric = "cert_end_in"
+
+	// verify_mode values, shared with the tls block of the database check
+	// types.
+	checkTLSVerifyModeNone = "none"
+	checkTLSVerifyModeCA   = "verify-ca"
+	checkTLSVerifyModeFull = "verify-full"
+
+	defaultCheckCertExpirySeverity = 1
+)
+
+var checkTLSDescriptions = attrDescrs{
+	checkTLSCertExpiryAttr:    "Collects the certificate metrics and manages a rule set that alerts before the certificate expires",
+	checkTLSExpectedNamesAttr: "The names the certificate must carry, as its common name or a subject alternative name",
+	checkTLSMinVersionAttr:    "The minimum TLS version to negotiate",
+	checkTLSSNIAttr:           "The server name sent with the TLS handshake (SNI)",
+	checkTLSVerifyModeAttr:    "How the certificate is verified: none, verify-ca (the certificate chain only) or verify-full (the chain and the host name)",
+}
+
+var checkCertExpiryDescriptions = attrDescrs{
+	checkCertExpiryContactGroupsAttr: "The contact groups notified when the certificate is about to expire",
+	checkCertExpiryDaysAttr:          "Alert when the certificate expires in fewer than this many days",
+	checkCertExpirySeverityAttr:      "The severity of the alert",
+}
+
+var (
+	validCheckTLSMinVersions = validStringValues{
+		`1.0`,
+		`1.1`,
+		`1.2`,
+		`1.3`,
+	}
+
+	validCheckTLSVerifyModes = validStringValues{
+		checkTLSVerifyModeNone,
+		checkTLSVerifyModeCA,
+		checkTLSVerifyModeFull,
+	}
+)
+
+// checkCertExpiryMetrics are the certificate metrics collected by checks with
+// a cert_expiry block.
+var checkCertExpiryMetrics = []api.CheckBundleMetric{
+	{Name: "cert_end", Type: "numeric"},
+	{Name: checkCertExpiryMetric, Type: "numeric"},
+	{Name: "cert_error", Type: "text"},
+	{Name: "cert_issuer", Type: "text"},
+	{Name: "cert_start", Type: "numeric"},
+	{Name: "cert_subject", Type: "text"},
+}
+
+// checkCertExpiryMetricFilter allows the certificate metrics of checks that
+// use metric filters instead of a list of metrics.
+var checkCertExpiryMetricFilter = []string{"allow", `^cert_(?:end|end_in|error|issuer|start|subject)$`, "cert_expiry"}
+
+// checkCertExpiry is the cert_expiry block.
+type checkCertExpiry struct {
+	Days          int
+	Severity      int
+	ContactGroups []string
+}
+
+// addCheckTLSSchema adds the TLS attributes to the schema of a check type.
+func addCheckTLSSchema(m map[schemaAttr]*schema.Schema) map[schemaAttr]*schema.Schema {
+	m[checkTLSCertExpiryAttr] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkCertExpiryDescriptions, map[schemaAttr]*schema.Schema{
+				checkCertExpiryContactGroupsAttr: {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validateContactGroupCID(checkCertExpiryContactGroupsAttr),
+					},
+				},
+				checkCertExpiryDaysAttr: {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validateIntMin(checkCertExpiryDaysAttr, 1),
+				},
+				checkCertExpirySeverityAttr: {
+					Type:     schema.TypeInt,
+					Optional: true,
+					Default:  defaultCheckCertExpirySeverity,
+					ValidateFunc: validateFuncs(
+						validateIntMin(checkCertExpirySeverityAttr, 1),
+						validateIntMax(checkCertExpirySeverityAttr, config.NumSeverityLevels),
+					),
+				},
+			}),
+		},
+	}
+	m[checkTLSExpectedNamesAttr] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validateRegexp(checkTLSExpectedNamesAttr, `^[^,\s]+$`),
+		},
+	}
+	m[checkTLSMinVersionAttr] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateStringIn(checkTLSMinVersionAttr, validCheckTLSMinVersions),
+	}
+	m[checkTLSSNIAttr] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateRegexp(checkTLSSNIAttr, `^[^\s:/]+$`),
+	}
+	m[checkTLSVerifyModeAttr] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateStringIn(checkTLSVerifyModeAttr, validCheckTLSVerifyModes),
+	}
+
+	return m
+}
+
+// checkTLSSet reports whether any of the TLS attributes is set.
+func checkTLSSet(m interfaceMap) bool {
+	for _, attr := range []schemaAttr{checkTLSMinVersionAttr, checkTLSSNIAttr, checkTLSVerifyModeAttr} {
+		if v, _ := m[string(attr)].(string); v != "" {
+			return true
+		}
+	}
+
+	for _, attr := range []schemaAttr{checkTLSCertExpiryAttr, checkTLSExpectedNamesAttr} {
+		if l, _ := m[string(attr)].([]interface{}); len(l) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkTLSConfigToAPI stores the TLS attributes of the blockAttr block in the
+// broker config.  A cert_expiry block is kept on the check for its rule sets
+// and adds the certificate metrics to the check.
+func checkTLSConfigToAPI(c *circonusCheck, blockAttr schemaAttr, m interfaceMap) error {
+	if v, _ := m[checkTLSSNIAttr].(string); v != "" {
+		c.Config[apiTLSSNI] = v
+	}
+
+	if v, _ := m[checkTLSMinVersionAttr].(string); v != "" {
+		c.Config[apiTLSMinVersion] = v
+	}
+
+	verifyMode, _ := m[checkTLSVerifyModeAttr].(string)
+	if verifyMode != "" {
+		c.Config[apiTLSVerifyMode] = verifyMode
+	}
+
+	namesRaw, _ := m[checkTLSExpectedNamesAttr].([]interface{})
+	names, err := interfaceList(namesRaw).List()
+	if err != nil {
+		return attrErrorf(attrPath(blockAttr, 0, checkTLSExpectedNamesAttr), "%v", err)
+	}
+	if len(names) > 0 {
+		if verifyMode == checkTLSVerifyModeNone {
+			return attrErrorf(attrPath(blockAttr), "%s can not be checked with a %s of %q", checkTLSExpectedNamesAttr, checkTLSVerifyModeAttr, verifyMode)
+		}
+		c.Config[apiTLSExpectedNames] = strings.Join(names, ",")
+	}
+
+	if l, _ := m[checkTLSCertExpiryAttr].([]interface{}); len(l) > 0 {
+		expiryConfig := newInterfaceMap(l[0])
+		contactGroupsRaw, _ := expiryConfig[checkCertExpiryContactGroupsAttr].([]interface{})
+		contactGroups, err := interfaceList(contactGroupsRaw).List()
+		if err != nil {
+			return attrErrorf(attrPath(blockAttr, 0, checkTLSCertExpiryAttr, 0, checkCertExpiryContactGroupsAttr), "%v", err)
+		}
+
+		e := &checkCertExpiry{
+			ContactGroups: contactGroups,
+		}
+		e.Days, _ = expiryConfig[checkCertExpiryDaysAttr].(int)
+		e.Severity, _ = expiryConfig[checkCertExpirySeverityAttr].(int)
+		if e.Severity == 0 {
+			e.Severity = defaultCheckCertExpirySeverity
+		}
+		c.certExpiry = e
+
+		c.addCertExpiryMetrics()
+	}
+
+	return nil
+}
+
+// checkTLSAPIToState reads the TLS settings out of the broker config into the
+// state of the blockAttr block, removing the keys it handles from swamp.  The
+// cert_expiry block isn't in the broker config, so it is kept from the state.
+func checkTLSAPIToState(c *circonusCheck, d *schema.ResourceData, blockAttr schemaAttr, swamp map[config.Key]string, state map[string]interface{}) {
+	if v, ok := c.Config[apiTLSSNI]; ok {
+		state[string(checkTLSSNIAttr)] = v
+	}
+	delete(swamp, apiTLSSNI)
+
+	if v, ok := c.Config[apiTLSMinVersion]; ok {
+		state[string(checkTLSMinVersionAttr)] = v
+	}
+	delete(swamp, apiTLSMinVersion)
+
+	if v, ok := c.Config[apiTLSVerifyMode]; ok {
+		state[string(checkTLSVerifyModeAttr)] = v
+	}
+	delete(swamp, apiTLSVerifyMode)
+
+	if v, ok := c.Config[apiTLSExpectedNames]; ok && v != "" {
+		names := make([]interface{}, 0)
+		for _, name := range strings.Split(v, ",") {
+			names = append(names, name)
+		}
+		state[string(checkTLSExpectedNamesAttr)] = names
+	}
+	delete(swamp, apiTLSExpectedNames)
+
+	if l := checkCertExpiryBlock(d.Get(string(blockAttr))); len(l) > 0 {
+		state[string(checkTLSCertExpiryAttr)] = l
+	}
+}
+
+// hashCheckTLS writes the TLS attributes of m to b, in lexical order.
+func hashCheckTLS(b *bytes.Buffer, m map[string]interface{}) {
+	if l, ok := m[string(checkTLSCertExpiryAttr)].([]interface{}); ok && len(l) > 0 && l[0] != nil {
+		expiryConfig := l[0].(map[string]interface{})
+		if cids, ok := expiryConfig[string(checkCertExpiryContactGroupsAttr)].([]interface{}); ok {
+			for _, cid := range cids {
+				fmt.Fprint(b, cid)
+			}
+		}
+		fmt.Fprintf(b, "%x", expiryConfig[string(checkCertExpiryDaysAttr)])
+		fmt.Fprintf(b, "%x", expiryConfig[string(checkCertExpirySeverityAttr)])
+	}
+
+	if l, ok := m[string(checkTLSExpectedNamesAttr)].([]interface{}); ok {
+		for _, name := range l {
+			fmt.Fprint(b, name)
+		}
+	}
+
+	for _, attr := range []schemaAttr{checkTLSMinVersionAttr, checkTLSSNIAttr, checkTLSVerifyModeAttr} {
+		if v, ok := m[string(attr)].(string); ok && v != "" {
+			fmt.Fprint(b, strings.TrimSpace(v))
+		}
+	}
+}
+
+// checkCertExpiryBlock returns the cert_expiry block of v, the value of an
+// http or tcp check type block read from the config or the state, or nil if
+// it has none.
+func checkCertExpiryBlock(v interface{}) []interface{} {
+	var blocks []interface{}
+	switch v := v.(type) {
+	case *schema.Set:
+		blocks = v.List()
+	case []interface{}:
+		blocks = v
+	}
+
+	for _, block := range blocks {
+		m, _ := block.(map[string]interface{})
+		if l, _ := m[string(checkTLSCertExpiryAttr)].([]interface{}); len(l) > 0 && l[0] != nil {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// checkCertExpiryEnabled reports whether the http or tcp block of d has a
+// cert_expiry block.
+func checkCertExpiryEnabled(d *schema.ResourceData) bool {
+	for _, attr := range []string{checkHTTPAttr, checkTCPAttr} {
+		if len(checkCertExpiryBlock(d.Get(attr))) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addCertExpiryMetrics adds the certificate metrics to a check, as metrics or
+// as a metric filter depending on which the check uses.
+func (c *circonusCheck) addCertExpiryMetrics() {
+	if len(c.MetricFilters) > 0 {
+		for _, f := range c.MetricFilters {
+			if strings.Join(f, "\x00") == strings.Join(checkCertExpiryMetricFilter, "\x00") {
+				return
+			}
+		}
+
+		// Filters are applied in order, so the allow filter goes first.
+		c.MetricFilters = append([][]string{checkCertExpiryMetricFilter}, c.MetricFilters...)
+		return
+	}
+
+	names := make(map[string]struct{}, len(c.Metrics))
+	for _, m := range c.Metrics {
+		names[m.Name] = struct{}{}
+	}
+
+	for _, m := range checkCertExpiryMetrics {
+		if _, found := names[m.Name]; found {
+			continue
+		}
+
+		m.Status = metricActiveToAPIStatus(true)
+		c.Metrics = append(c.Metrics, m)
+	}
+}
+
+// checkCertExpiryImplicit reports whether a metric or metric filter read from
+// the API was added by addCertExpiryMetrics rather than configured, in which
+// case it is left out of the state.
+func checkCertExpiryImplicit(d *schema.ResourceData, configured []interface{}, match func(map[string]interface{}) bool) bool {
+	if !checkCertExpiryEnabled(d) {
+		return false
+	}
+
+	for _, v := range configured {
+		if m, ok := v.(map[string]interface{}); ok && match(m) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkCertExpiryMetricImplicit reports whether the metric m was added by the
+// check's cert_expiry block.
+func checkCertExpiryMetricImplicit(d *schema.ResourceData, m api.CheckBundleMetric) bool {
+	found := false
+	for _, cm := range checkCertExpiryMetrics {
+		if cm.Name == m.Name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	configured, _ := d.Get(checkMetricAttr).([]interface{})
+	return checkCertExpiryImplicit(d, configured, func(attrs map[string]interface{}) bool {
+		return attrs[string(metricNameAttr)] == m.Name
+	})
+}
+
+// checkCertExpiryMetricFilterImplicit reports whether the metric filter f was
+// added by the check's cert_expiry block.
+func checkCertExpiryMetricFilterImplicit(d *schema.ResourceData, f []string) bool {
+	if strings.Join(f, "\x00") != strings.Join(checkCertExpiryMetricFilter, "\x00") {
+		return false
+	}
+
+	var configured []interface{}
+	if set, ok := d.Get(checkMetricFilterAttr).(*schema.Set); ok {
+		configured = set.List()
+	}
+
+	return checkCertExpiryImplicit(d, configured, func(attrs map[string]interface{}) bool {
+		return strings.Join(metricFilterToAPI(attrs), "\x00") == strings.Join(f, "\x00")
+	})
+}
+
+// checkCertExpiryRuleSetsChange reports whether applying d creates or deletes
+// cert_expiry rule sets: the block is added or removed, or the collectors of a
+// check with the block change.
+func checkCertExpiryRuleSetsChange(d *schema.ResourceDiff) bool {
+	enabled := func(v interface{}) bool {
+		return len(checkCertExpiryBlock(v)) > 0
+	}
+
+	for _, attr := range []string{checkHTTPAttr, checkTCPAttr} {
+		o, n := d.GetChange(attr)
+		if enabled(o) != enabled(n) {
+			return true
+		}
+
+		if enabled(n) && d.HasChange(checkCollectorAttr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkCertExpiryRuleSetNotes prefixes the notes of the rule sets managed by
+// a cert_expiry block, followed by the CID of their check bundle.
+var checkCertExpiryRuleSetNotes = fmt.Sprintf("Managed by the %s block of check ", checkTLSCertExpiryAttr)
+
+// isCheckCertExpiryRuleSet reports whether rs is managed by the cert_expiry
+// block of a check.
+func isCheckCertExpiryRuleSet(rs *api.RuleSet) bool {
+	return rs.Notes != nil && strings.HasPrefix(*rs.Notes, checkCertExpiryRuleSetNotes)
+}
+
+// newCheckCertExpiryRuleSet returns the rule set alerting on the certificate
+// expiry of the check checkCID.
+func newCheckCertExpiryRuleSet(c *circonusCheck, checkCID string, e *checkCertExpiry) circonusRuleSet {
+	rs := newRuleSet()
+	rs.CheckCID = checkCID
+	rs.MetricName = checkCertExpiryMetric
+	rs.MetricType = "numeric"
+	rs.Name = fmt.Sprintf("%s certificate expiry", c.DisplayName)
+
+	notes := checkCertExpiryRuleSetNotes + c.CID
+	rs.Notes = &notes
+
+	rs.Rules = append(rs.Rules, api.RuleSetRule{
+		Criteria: apiRuleSetMinValue,
+		Severity: uint(e.Severity),
+		Value:    strconv.Itoa(e.Days * 24 * 60 * 60),
+	})
+
+	if len(e.ContactGroups) > 0 {
+		rs.ContactGroups[uint8(e.Severity)] = append(rs.ContactGroups[uint8(e.Severity)], e.ContactGroups...)
+	}
+
+	return rs
+}
+
+// syncCheckCertExpiryRuleSets creates, updates and deletes the rule sets of a
+// check's cert_expiry block so there is one for each of the check's checks,
+// and stores their CIDs in the state.  The state is saved as each rule set is
+// created so a failed apply doesn't lose track of the rule sets it made.  c
+// must have been created or updated.
+func syncCheckCertExpiryRuleSets(ctx context.Context, ctxt *providerContext, d *schema.ResourceData, c *circonusCheck) error {
+	existing, err := interfaceList(d.Get(checkOutCertExpiryRuleSetsAttr).([]interface{})).List()
+	if err != nil {
+		return err
+	}
+
+	e := c.certExpiry
+	wanted := make(map[string]string, len(c.Checks))
+	if e != nil {
+		for _, checkCID := range c.Checks {
+			wanted[checkCID] = ""
+		}
+	}
+
+	stale := make([]string, 0, len(existing))
+	for _, cid := range existing {
+		var rs *api.RuleSet
+		err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+			rs, err = client.FetchRuleSet(api.CIDType(&cid))
+			return err
+		})
+		if err != nil {
+			if apiNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("unable to fetch %s rule set %s: %w", checkTLSCertExpiryAttr, cid, err)
+		}
+
+		if ruleSetCID, found := wanted[rs.CheckCID]; !found || ruleSetCID != "" {
+			stale = append(stale, cid)
+			continue
+		}
+
+		updated := newCheckCertExpiryRuleSet(c, rs.CheckCID, e)
+		updated.CID = cid
+		if err := updated.Update(ctx, ctxt); err != nil {
+			return err
+		}
+		wanted[rs.CheckCID] = cid
+	}
+
+	kept := make([]string, 0, len(wanted))
+	for _, cid := range wanted {
+		if cid != "" {
+			kept = append(kept, cid)
+		}
+	}
+	sort.Strings(kept)
+
+	if err := deleteCheckCertExpiryRuleSets(ctx, ctxt, stale); err != nil {
+		// Keep the stale rule sets in the state, those already deleted are
+		// skipped by the next apply.
+		if setErr := setCheckCertExpiryRuleSets(d, append(kept, stale...)); setErr != nil {
+			return setErr
+		}
+		return err
+	}
+
+	checkCIDs := make([]string, 0, len(wanted))
+	for checkCID := range wanted {
+		checkCIDs = append(checkCIDs, checkCID)
+	}
+	sort.Strings(checkCIDs)
+
+	ruleSets := make([]string, 0, len(checkCIDs))
+	for _, checkCID := range checkCIDs {
+		if cid := wanted[checkCID]; cid != "" {
+			ruleSets = append(ruleSets, cid)
+			continue
+		}
+
+		rs := newCheckCertExpiryRuleSet(c, checkCID, e)
+		if err := rs.Create(ctx, ctxt); err != nil {
+			if setErr := setCheckCertExpiryRuleSets(d, ruleSets); setErr != nil {
+				return setErr
+			}
+			return fmt.Errorf("unable to create %s rule set for %s: %w", checkTLSCertExpiryAttr, checkCID, err)
+		}
+		tflog.Debug(ctx, "Created certificate expiry rule set", map[string]interface{}{"rule_set_cid": rs.CID, "check_cid": checkCID})
+		ruleSets = append(ruleSets, rs.CID)
+
+		if err := setCheckCertExpiryRuleSets(d, ruleSets); err != nil {
+			return err
+		}
+	}
+
+	return setCheckCertExpiryRuleSets(d, ruleSets)
+}
+
+// setCheckCertExpiryRuleSets stores the cert_expiry rule set CIDs in the state.
+func setCheckCertExpiryRuleSets(d *schema.ResourceData, cids []string) error {
+	if err := d.Set(checkOutCertExpiryRuleSetsAttr, cids); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutCertExpiryRuleSetsAttr, err)
+	}
+
+	return nil
+}
+
+// deleteCheckCertExpiryRuleSets deletes the rule sets cids.  Rule sets that no
+// longer exist are ignored.
+func deleteCheckCertExpiryRuleSets(ctx context.Context, ctxt *providerContext, cids []string) error {
+	for _, cid := range cids {
+		cid := cid
+		err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+			_, err := client.DeleteRuleSetByCID(api.CIDType(&cid))
+			return err
+		})
+		if err != nil && !apiNotFound(err) {
+			return fmt.Errorf("unable to delete %s rule set %s: %w", checkTLSCertExpiryAttr, cid, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_CheckConfigToAPITLS(t *testing.T) {
+	certExpiry := []interface{}{
+		map[string]interface{}{
+			string(checkCertExpiryContactGroupsAttr): []interface{}{"/contact_group/1"},
+			string(checkCertExpiryDaysAttr):          14,
+			string(checkCertExpirySeverityAttr):      2,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		config        map[string]interface{}
+		metricFilters [][]string
+		expected      map[config.Key]string
+		certExpiry    *checkCertExpiry
+		metrics       int
+		filters       int
+		fail          bool
+	}{
+		{
+			name: "tls settings",
+			config: map[string]interface{}{
+				string(checkTLSExpectedNamesAttr): []interface{}{"example.com", "www.example.com"},
+				string(checkTLSMinVersionAttr):    "1.2",
+				string(checkTLSSNIAttr):           "www.example.com",
+				string(checkTLSVerifyModeAttr):    "verify-full",
+			},
+			expected: map[config.Key]string{
+				apiTLSExpectedNames: "example.com,www.example.com",
+				apiTLSMinVersion:    "1.2",
+				apiTLSSNI:           "www.example.com",
+				apiTLSVerifyMode:    "verify-full",
+			},
+			metrics: 1,
+		},
+		{
+			name: "cert expiry adds metrics",
+			config: map[string]interface{}{
+				string(checkTLSCertExpiryAttr): certExpiry,
+			},
+			certExpiry: &checkCertExpiry{Days: 14, Severity: 2, ContactGroups: []string{"/contact_group/1"}},
+			metrics:    len(checkCertExpiryMetrics),
+		},
+		{
+			name: "cert expiry adds a metric filter",
+			config: map[string]interface{}{
+				string(checkTLSCertExpiryAttr): certExpiry,
+			},
+			certExpiry:    &checkCertExpiry{Days: 14, Severity: 2, ContactGroups: []string{"/contact_group/1"}},
+			metricFilters: [][]string{{"deny", ".*", ""}},
+			filters:       2,
+		},
+		{
+			name: "expected names without verification",
+			config: map[string]interface{}{
+				string(checkTLSExpectedNamesAttr): []interface{}{"example.com"},
+				string(checkTLSVerifyModeAttr):    checkTLSVerifyModeNone,
+			},
+			fail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			if test.metricFilters != nil {
+				c.MetricFilters = test.metricFilters
+			} else {
+				// cert_end_in is configured already and must not be added twice.
+				c.Metrics = []api.CheckBundleMetric{{Name: checkCertExpiryMetric, Type: "numeric", Status: "active"}}
+			}
+
+			err := checkTLSConfigToAPI(&c, checkHTTPAttr, newInterfaceMap(test.config))
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for k, v := range test.expected {
+				if got := c.Config[k]; got != v {
+					t.Errorf("config key %q: expected %q, got %q", k, v, got)
+				}
+			}
+
+			// cert_expiry is managed by the provider, not the broker.
+			if len(c.Config) != len(test.expected) {
+				t.Errorf("expected %d config keys, got %v", len(test.expected), c.Config)
+			}
+
+			if !reflect.DeepEqual(c.certExpiry, test.certExpiry) {
+				t.Errorf("expected cert_expiry %#v, got %#v", test.certExpiry, c.certExpiry)
+			}
+
+			if len(c.Metrics) != test.metrics {
+				t.Errorf("expected %d metrics, got %d", test.metrics, len(c.Metrics))
+			}
+
+			if len(c.MetricFilters) != test.filters {
+				t.Errorf("expected %d metric filters, got %d", test.filters, len(c.MetricFilters))
+			}
+		})
+	}
+}
+
+func Test_NewCheckCertExpiryRuleSet(t *testing.T) {
+	c := newCheck()
+	c.CID = "/check_bundle/1"
+	c.DisplayName = "www"
+
+	rs := newCheckCertExpiryRuleSet(&c, "/check/2", &checkCertExpiry{Days: 30, Severity: 3, ContactGroups: []string{"/contact_group/4"}})
+
+	if rs.CheckCID != "/check/2" || rs.MetricName != checkCertExpiryMetric {
+		t.Fatalf("unexpected rule set target: %s %s", rs.CheckCID, rs.MetricName)
+	}
+
+	if len(rs.Rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(rs.Rules))
+	}
+
+	rule := rs.Rules[0]
+	if rule.Criteria != apiRuleSetMinValue || rule.Severity != 3 || rule.Value != "2592000" {
+		t.Errorf("unexpected rule: %#v", rule)
+	}
+
+	if groups := rs.ContactGroups[3]; len(groups) != 1 || groups[0] != "/contact_group/4" {
+		t.Errorf("unexpected severity 3 contact groups: %v", groups)
+	}
+
+	if err := rs.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func Test_CheckTLSAPIToStateCertExpiry(t *testing.T) {
+	certExpiry := map[string]interface{}{
+		"contact_groups": []interface{}{"/contact_group/1"},
+		"days":           14,
+		"severity":       2,
+	}
+
+	tests := []struct {
+		name     string
+		http     map[string]interface{}
+		expected []interface{}
+	}{
+		{
+			name:     "cert_expiry kept from the state",
+			http:     map[string]interface{}{"url": "https://www.example.com/", "cert_expiry": []interface{}{certExpiry}},
+			expected: []interface{}{certExpiry},
+		},
+		{
+			name: "no cert_expiry",
+			http: map[string]interface{}{"url": "https://www.example.com/"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+				"http": []interface{}{test.http},
+			})
+
+			c := newCheck()
+			c.Config[apiTLSSNI] = "www.example.com"
+			swamp := map[config.Key]string{apiTLSSNI: "www.example.com"}
+			state := make(map[string]interface{})
+
+			checkTLSAPIToState(&c, d, checkHTTPAttr, swamp, state)
+
+			if len(swamp) != 0 {
+				t.Errorf("expected an empty swamp, got %v", swamp)
+			}
+
+			if state[string(checkTLSSNIAttr)] != "www.example.com" {
+				t.Errorf("expected sni %q, got %v", "www.example.com", state[string(checkTLSSNIAttr)])
+			}
+
+			got, _ := state[string(checkTLSCertExpiryAttr)].([]interface{})
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected cert_expiry %#v, got %#v", test.expected, got)
+			}
+		})
+	}
+}
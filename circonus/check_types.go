@@ -0,0 +1,150 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// checkType describes one of the check type blocks of the `circonus_check`
+// resource.  Each check type registers its descriptor from its own
+// resource_circonus_check_<type>.go file.  The resource's schema, the
+// config/state dispatch tables and the plan time validation are all derived from
+// the registered descriptors, so adding a check type doesn't require changes
+// anywhere else.
+type checkType struct {
+	// apiType is the check bundle type sent to and read from the API.  The raw
+	// check type leaves this empty because it handles every type.
+	apiType apiCheckType
+
+	// attr is the name of the check type's block in circonus_check.
+	attr        schemaAttr
+	description attrDescr
+	schema      *schema.Schema
+
+	// toAPI parses the check type's block into the check bundle.
+	toAPI func(*circonusCheck, interfaceList) error
+
+	// toState stores the check bundle's config in the check type's block.
+	toState func(context.Context, *circonusCheck, *schema.ResourceData) error
+
+	// validate, if set, checks the parsed check bundle against rules specific
+	// to the check type.
+	validate func(*circonusCheck) error
+
+	// planInputs are top-level circonus_check attributes, other than metric,
+	// metric_filter, period and timeout, that toAPI or validate read.  Plan
+	// time validation is skipped until they are known.
+	planInputs []string
+
+	// submissionURL, if set, marks a push-based check type.  It returns the URL
+	// metrics are submitted to, given the check bundle and the host and port of
+	// the check's collector.  It is only used when the API doesn't return a
+	// submission URL itself.
+	submissionURL func(c *circonusCheck, host string, port uint16) string
+
+	// submissionPort, if set, is the port the collector receives the push-based
+	// check type's submissions on, in place of the collector's own port.
+	submissionPort uint16
+
+	// metricPathSeparator, if set, is what the broker joins the keys of nested
+	// documents with in the check type's metric names.  Metrics are configured
+	// with the keys joined with dots instead.
+	metricPathSeparator string
+}
+
+var (
+	checkTypesByAttr    = make(map[schemaAttr]*checkType)
+	checkTypesByAPIType = make(map[apiCheckType]*checkType)
+)
+
+// registerCheckType adds t to the check type registry.  It is called from the
+// init function of each check type's file.
+func registerCheckType(t *checkType) {
+	if _, found := checkTypesByAttr[t.attr]; found {
+		panic(fmt.Sprintf("PROVIDER BUG: check type block %q registered twice", t.attr))
+	}
+
+	if t.schema == nil || t.toAPI == nil || t.toState == nil {
+		panic(fmt.Sprintf("PROVIDER BUG: check type block %q is incomplete", t.attr))
+	}
+
+	checkTypesByAttr[t.attr] = t
+	checkDescriptions[t.attr] = t.description
+
+	if t.apiType == "" {
+		return
+	}
+
+	if _, found := checkTypesByAPIType[t.apiType]; found {
+		panic(fmt.Sprintf("PROVIDER BUG: check type %q registered twice", t.apiType))
+	}
+
+	checkTypesByAPIType[t.apiType] = t
+}
+
+// registeredCheckTypes returns every registered check type, sorted by block
+// name.
+func registeredCheckTypes() []*checkType {
+	types := make([]*checkType, 0, len(checkTypesByAttr))
+	for _, t := range checkTypesByAttr {
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].attr < types[j].attr
+	})
+
+	return types
+}
+
+// checkTypeAttrs returns the sorted names of every check type block.
+func checkTypeAttrs() []string {
+	types := registeredCheckTypes()
+	attrs := make([]string, 0, len(types))
+	for _, t := range types {
+		attrs = append(attrs, string(t.attr))
+	}
+
+	return attrs
+}
+
+// checkTypeForAPIType returns the check type with a dedicated block for the
+// check bundle type s.
+func checkTypeForAPIType(s string) (*checkType, bool) {
+	t, found := checkTypesByAPIType[apiCheckType(s)]
+	return t, found
+}
+
+// checkMetricPathSeparator returns the separator the broker joins the keys of
+// nested documents with in the metric names of c, or an empty string if its
+// metrics are named as the broker reports them.  Checks managed through a raw
+// block always use the broker's names.
+func checkMetricPathSeparator(c *circonusCheck, d checkConfigReader) string {
+	if _, found := d.GetOk(checkRawAttr); found {
+		return ""
+	}
+
+	t, ok := checkTypeForAPIType(c.Type)
+	if !ok {
+		return ""
+	}
+
+	return t.metricPathSeparator
+}
+
+// metricPathToAPI joins the keys of the dotted metric path name with sep,
+// leaving any stream tags encoded in the name untouched.
+func metricPathToAPI(name, sep string) string {
+	base, tags := cutMetricStreamTags(name)
+	return strings.ReplaceAll(base, ".", sep) + tags
+}
+
+// metricPathToState is the inverse of metricPathToAPI.
+func metricPathToState(name, sep string) string {
+	base, tags := cutMetricStreamTags(name)
+	return strings.ReplaceAll(base, sep, ".") + tags
+}
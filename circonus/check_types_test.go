@@ -0,0 +1,33 @@
+package circonus
+
+import (
+	"testing"
+)
+
+func Test_CheckTypeRegistry(t *testing.T) {
+	r := resourceCheck()
+
+	for _, ct := range registeredCheckTypes() {
+		s, found := r.Schema[string(ct.attr)]
+		if !found {
+			t.Errorf("check type %q missing from circonus_check schema", ct.attr)
+			continue
+		}
+
+		if s.Description == "" {
+			t.Errorf("check type %q has no description", ct.attr)
+		}
+
+		if ct.apiType == "" {
+			continue
+		}
+
+		if got, found := checkTypeForAPIType(string(ct.apiType)); !found || got != ct {
+			t.Errorf("check type %q not found by API type %q", ct.attr, ct.apiType)
+		}
+	}
+
+	if _, found := checkTypeForAPIType("selfcheck"); found {
+		t.Errorf("unexpected check type for %q", "selfcheck")
+	}
+}
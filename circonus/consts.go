@@ -30,6 +30,18 @@ const (
 	defaultCheckConsulHTTPAddr = "http://consul.service.consul"
 	defaultCheckConsulPort     = "8500"
 
+	checkElasticsearchEndpointClusterHealth = "cluster_health"
+	checkElasticsearchEndpointCustom        = "custom"
+	checkElasticsearchEndpointIndexStats    = "index_stats"
+	checkElasticsearchEndpointNodeStats     = "node_stats"
+	defaultCheckElasticsearchEndpoint       = checkElasticsearchEndpointNodeStats
+
+	defaultCheckLDAPAuthType = "simple"
+	defaultCheckLDAPPort     = 389
+
+	defaultCheckMongoDBCommand = "serverStatus"
+	defaultCheckMongoDBPort    = 27017
+
 	defaultCheckJSONMethod  = "GET"
 	defaultCheckJSONPort    = "443"
 	defaultCheckJSONVersion = "1.1"
@@ -47,6 +59,8 @@ const (
 
 	defaultCheckHTTPTrapAsync = false
 
+	defaultCheckPrometheusConvertHistograms = true
+
 	defaultCheckCloudWatchVersion = "2010-08-01"
 
 	defaultCheckSSH2Port          = "22"
@@ -72,7 +86,7 @@ const (
 	// defaultRuleSetWindowFunc = "average"
 	// ruleSetAbsentMin         = "70s".
 
-	defaultWorkspaceFavourite = false
+	defaultWorksheetFavorite = false
 )
 
 // Consts and their close relative, Go pseudo-consts.
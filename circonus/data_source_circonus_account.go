@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"context"
+	"net/http"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -229,7 +230,7 @@ func dataSourceCirconusAccount() *schema.Resource {
 
 // dataSourceCirconusAccountRead - map account object from API to schema.ResourceData.
 func dataSourceCirconusAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*providerContext).client
+	ctxt := meta.(*providerContext)
 	var diags diag.Diagnostics
 
 	var cid string
@@ -243,7 +244,12 @@ func dataSourceCirconusAccountRead(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	acct, err := client.FetchAccount(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, dataSourceTypeAccount, cid)
+	var acct *api.Account
+	err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		acct, err = client.FetchAccount(api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"context"
+	"net/http"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -158,16 +159,21 @@ func dataSourceCirconusCollector() *schema.Resource {
 }
 
 func dataSourceCirconusCollectorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*providerContext).client
+	ctxt := meta.(*providerContext)
 	var diags diag.Diagnostics
 
 	cid := d.Id()
 	if cidRaw, ok := d.GetOk(collectorIDAttr); ok {
 		cid = cidRaw.(string)
 	}
-	broker, err := client.FetchBroker(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, dataSourceTypeCollector, cid)
+	var broker *api.Broker
+	err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		broker, err = client.FetchBroker(api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
-		diag.FromErr(err)
+		return diag.FromErr(err)
 	}
 
 	d.SetId(broker.CID)
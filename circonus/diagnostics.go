@@ -0,0 +1,116 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// attributeError is a configuration error caused by a single attribute.
+// configDiag attaches its path to the diagnostic so Terraform can point at the
+// offending block.
+type attributeError struct {
+	path cty.Path
+	err  error
+}
+
+// attrErrorf returns an attributeError for the attribute at path.  Elements of
+// a TypeSet are addressed by their index in the set's list, which is always 0
+// for the single element check type blocks.
+func attrErrorf(path cty.Path, format string, args ...interface{}) error {
+	return &attributeError{path: path, err: fmt.Errorf(format, args...)}
+}
+
+func (e *attributeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attributeError) Unwrap() error {
+	return e.err
+}
+
+// attrPath builds a cty.Path from schema attribute names and list indexes.
+func attrPath(steps ...interface{}) cty.Path {
+	p := make(cty.Path, 0, len(steps))
+	for _, step := range steps {
+		switch v := step.(type) {
+		case schemaAttr:
+			p = p.GetAttr(string(v))
+		case string:
+			p = p.GetAttr(v)
+		case int:
+			p = p.IndexInt(v)
+		default:
+			panic(fmt.Sprintf("PROVIDER BUG: unsupported attribute path step %T", step))
+		}
+	}
+
+	return p
+}
+
+// configDiag converts an error found while parsing or validating a resource's
+// configuration into diagnostics.  If an attributeError is anywhere in the
+// error chain its path is attached to the diagnostic.
+func configDiag(err error) diag.Diagnostics {
+	var attrErr *attributeError
+	if !errors.As(err, &attrErr) {
+		return diag.FromErr(err)
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: attrErr.path,
+		},
+	}
+}
+
+// planError converts err into the error a CustomizeDiff function must return
+// for Terraform to attach an attribute path to the plan diagnostic.
+func planError(err error) error {
+	var attrErr *attributeError
+	if !errors.As(err, &attrErr) {
+		return err
+	}
+
+	return attrErr.path.NewError(err)
+}
+
+// apiNotFound reports whether err is the Circonus API's response for an
+// object that does not exist.
+func apiNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), defaultCirconus404ErrorString)
+}
+
+// apiTransient reports whether err is a failure the API client gave up
+// retrying, a transport error or a 5xx or 429 response, which a later request
+// may not hit.  The client returns other error responses without retrying.
+func apiTransient(err error) bool {
+	var timeoutErr *apiTimeoutError
+	if err == nil || errors.As(err, &timeoutErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	return !strings.Contains(err.Error(), defaultCirconusResponseErrorString)
+}
+
+// notFoundDiag removes an object that has been deleted outside of Terraform
+// from the state.  This is recoverable, the next plan recreates the object, so
+// it is reported as a warning rather than an error.
+func notFoundDiag(d *schema.ResourceData, objType, cid string) diag.Diagnostics {
+	d.SetId("")
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s does not exist", objType),
+			Detail:   fmt.Sprintf("%s (%q) was not found and has been removed from the state.", objType, cid),
+		},
+	}
+}
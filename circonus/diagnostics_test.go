@@ -0,0 +1,94 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func Test_ConfigDiag(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		path cty.Path
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("boom"),
+			path: nil,
+		},
+		{
+			name: "wrapped attribute error",
+			err:  fmt.Errorf("error parsing graph schema during create: %w", attrErrorf(attrPath(graphMetricAttr, 1, graphMetricNameAttr), "missing name")),
+			path: cty.GetAttrPath(string(graphMetricAttr)).IndexInt(1).GetAttr(string(graphMetricNameAttr)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := configDiag(test.err)
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+
+			if diags[0].Severity != diag.Error {
+				t.Errorf("expected error severity, got %v", diags[0].Severity)
+			}
+
+			if diags[0].Summary != test.err.Error() {
+				t.Errorf("expected summary %q, got %q", test.err.Error(), diags[0].Summary)
+			}
+
+			if !diags[0].AttributePath.Equals(test.path) {
+				t.Errorf("expected path %#v, got %#v", test.path, diags[0].AttributePath)
+			}
+		})
+	}
+}
+
+func Test_APITransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"nil", nil, false},
+		{"retries exhausted", errors.New("- response: 503 Service Unavailable"), true},
+		{"transport", errors.New("Circonus API call - https://api.circonus.com/check/1: connection reset by peer"), true},
+		{"not found", errors.New("API response code 404: not found"), false},
+		{"bad request", errors.New("API response code 400: bad request"), false},
+		{"timeout", &apiTimeoutError{method: "GET", path: "/check/1", err: context.DeadlineExceeded}, false},
+		{"canceled", fmt.Errorf("unable to load check: %w", context.Canceled), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if transient := apiTransient(test.err); transient != test.transient {
+				t.Errorf("expected %t, got %t", test.transient, transient)
+			}
+		})
+	}
+}
+
+func Test_GraphValidateAttributePath(t *testing.T) {
+	g := newGraph()
+	g.Datapoints = []api.GraphDatapoint{
+		{Name: "ok", CheckID: 1, MetricName: "ok"},
+		{Name: "no-metric-name", CheckID: 1},
+	}
+
+	var attrErr *attributeError
+	if err := g.Validate(); !errors.As(err, &attrErr) {
+		t.Fatalf("expected attributeError, got %v", err)
+	}
+
+	want := attrPath(graphMetricAttr, 1, graphMetricNameAttr)
+	if !attrErr.path.Equals(want) {
+		t.Errorf("expected path %#v, got %#v", want, attrErr.path)
+	}
+}
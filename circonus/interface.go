@@ -1,6 +1,6 @@
 package circonus
 
-import "log"
+import "fmt"
 
 type (
 	interfaceList []interface{}
@@ -29,8 +29,9 @@ func (l interfaceList) CollectList(attrName schemaAttr) []string {
 	return stringList
 }
 
-// List returns a list of values in a Set as a string slice.
-func (l interfaceList) List() []string {
+// List returns a list of values in a Set as a string slice.  Values that
+// aren't strings, or lists of strings, are an error.
+func (l interfaceList) List() ([]string, error) {
 	stringList := make([]string, 0, len(l))
 	for _, e := range l {
 		switch e := e.(type) {
@@ -38,14 +39,17 @@ func (l interfaceList) List() []string {
 			stringList = append(stringList, e)
 		case []interface{}:
 			for _, v := range e {
-				stringList = append(stringList, v.(string))
+				s, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("unable to convert %#v to a list of strings", v)
+				}
+				stringList = append(stringList, s)
 			}
 		default:
-			log.Printf("[ERROR] PROVIDER BUG: unable to convert %#v to list", e)
-			return nil
+			return nil, fmt.Errorf("unable to convert %#v to a list of strings", e)
 		}
 	}
-	return stringList
+	return stringList, nil
 }
 
 // CollectList returns []string of values that matched the key attrName.
@@ -0,0 +1,40 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_InterfaceListList(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     interfaceList
+		expected []string
+		fail     bool
+	}{
+		{"strings", interfaceList{"a", "b"}, []string{"a", "b"}, false},
+		{"nested lists", interfaceList{"a", []interface{}{"b", "c"}}, []string{"a", "b", "c"}, false},
+		{"empty", interfaceList{}, []string{}, false},
+		{"number", interfaceList{"a", 1}, nil, true},
+		{"nested number", interfaceList{[]interface{}{"a", 1}}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, err := test.list.List()
+			if test.fail {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", l)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(l, test.expected) {
+				t.Fatalf("list = %v, expected %v", l, test.expected)
+			}
+		})
+	}
+}
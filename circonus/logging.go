@@ -0,0 +1,219 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// logSubsystemAPI is the tflog subsystem used for Circonus API traffic.  Its
+	// verbosity can be raised independently of the provider with
+	// TF_LOG_PROVIDER_CIRCONUS_API.
+	logSubsystemAPI       = "api"
+	logSubsystemAPILevels = "TF_LOG_PROVIDER_CIRCONUS_API"
+
+	// Structured log field names.
+	logFieldCID          = "circonus_cid"
+	logFieldError        = "error"
+	logFieldLatency      = "latency_ms"
+	logFieldMethod       = "http_method"
+	logFieldPath         = "http_path"
+	logFieldRequestID    = "circonus_request_id"
+	logFieldResourceType = "circonus_resource_type"
+	logFieldStatus       = "http_status"
+)
+
+// logSensitiveFieldKeys are structured log fields whose values are always
+// masked.  Keep this list in sync with the Sensitive attributes in the
+// provider and check schemas.
+var logSensitiveFieldKeys = []string{
+	providerKeyAttr,
+	"api_key",
+	"api_secret",
+	"auth_password",
+	"community",
+	"dsn",
+	"password",
+	"secret",
+	"token",
+}
+
+// logSensitiveMessageRegexps mask secrets embedded in free-form log messages,
+// notably the JSON request bodies logged by go-apiclient.
+var logSensitiveMessageRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`"(?:api_key|api_secret|auth_password|community|dsn|password|secret|reverse:secret_key)"\s*:\s*"(?:[^"\\]|\\.)*"`),
+	regexp.MustCompile(`(?i)(?:X-Circonus-Auth-Token|X-Consul-Token|Authorization)\s*[:=]\s*\S+`),
+}
+
+// apiResponseCodeRegexp extracts the HTTP status from go-apiclient errors.  The
+// client only surfaces the status of failed requests.
+var apiResponseCodeRegexp = regexp.MustCompile(`API response code (\d+):`)
+
+// maskLogContext returns a context whose root and API subsystem loggers never
+// emit the API token or check secrets.
+func maskLogContext(ctx context.Context, token string) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, logSensitiveFieldKeys...)
+	ctx = tflog.MaskMessageRegexes(ctx, logSensitiveMessageRegexps...)
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, logSensitiveMessageRegexps...)
+	ctx = tflog.NewSubsystem(ctx, logSubsystemAPI, tflog.WithLevelFromEnv(logSubsystemAPILevels), tflog.WithRootFields())
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, logSubsystemAPI, logSensitiveFieldKeys...)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, logSubsystemAPI, logSensitiveMessageRegexps...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, logSubsystemAPI, logSensitiveMessageRegexps...)
+
+	if token != "" {
+		ctx = tflog.MaskAllFieldValuesStrings(ctx, token)
+		ctx = tflog.MaskMessageStrings(ctx, token)
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystemAPI, token)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, logSubsystemAPI, token)
+	}
+
+	return ctx
+}
+
+// logContext decorates ctx with the resource type and CID of the object being
+// managed so that every line logged during a CRUD call can be attributed to it.
+func (p *providerContext) logContext(ctx context.Context, resourceType, cid string) context.Context {
+	ctx = tflog.SetField(ctx, logFieldResourceType, resourceType)
+	if cid != "" {
+		ctx = tflog.SetField(ctx, logFieldCID, cid)
+	}
+
+	return maskLogContext(ctx, p.token)
+}
+
+// apiRequest logs a single Circonus API call made by fn.  Each call is tagged
+// with its own correlation ID, method, path and latency so a slow apply can be
+// traced to the request responsible for it.
+//
+// fn is handed a client whose log lines carry the same fields as ctx.
+// go-apiclient does not accept a context, so fn runs in its own goroutine.
+// Once ctx is done the client's retries are turned off and:
+//
+//   - a POST is waited on, because the object it creates would otherwise be
+//     left behind without ever being recorded in the state.  Its result is
+//     returned as if ctx had not expired.
+//   - any other request is abandoned and the caller gets an apiTimeoutError
+//     naming it.  These requests are idempotent, so a retry converges.
+//
+// go-apiclient cannot cancel the HTTP attempt that is in flight, so an
+// abandoned request may still reach the API, and waiting on a POST lasts as
+// long as that attempt.
+func (p *providerContext) apiRequest(ctx context.Context, method, path string, fn func(client *api.API) error) error {
+	requestID, err := uuid.GenerateUUID()
+	if err != nil {
+		requestID = fmt.Sprintf("unavailable: %v", err)
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldRequestID, requestID)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldMethod, method)
+	ctx = tflog.SubsystemSetField(ctx, logSubsystemAPI, logFieldPath, path)
+
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		return &apiTimeoutError{method: method, path: path, err: err}
+	}
+
+	client, err := p.requestClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Sending Circonus API request")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(client)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		client.DisableExponentialBackoff()
+
+		if method != http.MethodPost {
+			err = &apiTimeoutError{method: method, path: path, elapsed: time.Since(start), err: ctx.Err()}
+			break
+		}
+
+		tflog.SubsystemWarn(ctx, logSubsystemAPI, "Waiting on Circonus API request past its timeout so the object it creates is not lost")
+		err = <-done
+	}
+
+	fields := map[string]interface{}{
+		logFieldLatency: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		if m := apiResponseCodeRegexp.FindStringSubmatch(err.Error()); m != nil {
+			if status, convErr := strconv.Atoi(m[1]); convErr == nil {
+				fields[logFieldStatus] = status
+			}
+		}
+		fields[logFieldError] = err.Error()
+		tflog.SubsystemWarn(ctx, logSubsystemAPI, "Circonus API request failed", fields)
+
+		return err
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystemAPI, "Received Circonus API response", fields)
+
+	return nil
+}
+
+// requestClient returns a Circonus API client for a single request.  The
+// client logs against ctx, so go-apiclient's own lines, e.g. its retries,
+// carry the request ID, method, path, resource type and CID of the request.
+func (p *providerContext) requestClient(ctx context.Context) (*api.API, error) {
+	config := p.apiConfig
+	config.Log = &apiClientLogger{ctx: ctx}
+
+	client, err := api.NewAPI(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	client.EnableExponentialBackoff()
+
+	return client, nil
+}
+
+// apiClientLogger adapts go-apiclient's Printf-style logger to tflog.
+type apiClientLogger struct {
+	ctx context.Context
+}
+
+func (l *apiClientLogger) Printf(format string, v ...interface{}) {
+	msg := strings.TrimSpace(fmt.Sprintf(format, v...))
+
+	switch {
+	case strings.HasPrefix(msg, "[ERR"):
+		tflog.SubsystemError(l.ctx, logSubsystemAPI, msg)
+	case strings.HasPrefix(msg, "[WARN"):
+		tflog.SubsystemWarn(l.ctx, logSubsystemAPI, msg)
+	default:
+		tflog.SubsystemTrace(l.ctx, logSubsystemAPI, msg)
+	}
+}
+
+// configKeys returns the sorted keys of an API check config.  It is used in
+// place of logging the config itself, whose values may hold secrets.
+func configKeys(m map[config.Key]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,28 @@
+package circonus
+
+import "testing"
+
+func Test_LogSensitiveMessageRegexps(t *testing.T) {
+	tests := []struct {
+		msg    string
+		masked bool
+	}{
+		{`{"config":{"secret":"s3cr3t","url":"https://example.com"}}`, true},
+		{`{"config":{"auth_password": "hunter2"}}`, true},
+		{`X-Circonus-Auth-Token: 8f2c0a7e-token`, true},
+		{`{"config":{"url":"https://example.com"}}`, false},
+	}
+
+	for _, test := range tests {
+		var matched bool
+		for _, re := range logSensitiveMessageRegexps {
+			if re.MatchString(test.msg) {
+				matched = true
+			}
+		}
+
+		if matched != test.masked {
+			t.Errorf("masking of %q: expected %t, got %t", test.msg, test.masked, matched)
+		}
+	}
+}
@@ -5,6 +5,8 @@ package circonus
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
@@ -15,6 +17,16 @@ import (
 type circonusMetric struct {
 	ID metricID
 	api.CheckBundleMetric
+
+	// baseName and streamTags are the metric's name and stream_tags as
+	// configured, Name has the stream tags encoded into it.
+	baseName   string
+	streamTags []string
+
+	// buckets are the bucket boundaries of a histogram block.  Circonus
+	// stores histograms in fixed log-linear bins, so they are kept in the
+	// state only and not sent to the API.
+	buckets []float64
 }
 
 func newMetric() circonusMetric {
@@ -26,21 +38,14 @@ func (m *circonusMetric) Create(d *schema.ResourceData) error {
 }
 
 func (m *circonusMetric) ParseConfig(id string, d *schema.ResourceData) error {
-	m.ID = metricID(id)
-
-	if v, found := d.GetOk(metricNameAttr); found {
-		m.Name = v.(string)
+	attrMap := make(map[string]interface{})
+	for _, attr := range []string{metricActiveAttr, metricHistogramAttr, metricNameAttr, metricStreamTagsAttr, metricTypeAttr} {
+		if v, found := d.GetOk(attr); found {
+			attrMap[attr] = v
+		}
 	}
 
-	if v, found := d.GetOk(metricActiveAttr); found {
-		m.Status = metricActiveToAPIStatus(v.(bool))
-	}
-
-	if v, found := d.GetOk(metricTypeAttr); found {
-		m.Type = v.(string)
-	}
-
-	return nil
+	return m.ParseConfigMap(id, attrMap)
 }
 
 func (m *circonusMetric) ParseConfigMap(id string, attrMap map[string]interface{}) error {
@@ -58,6 +63,55 @@ func (m *circonusMetric) ParseConfigMap(id string, attrMap map[string]interface{
 		m.Type = v.(string)
 	}
 
+	m.baseName = m.Name
+	streamTags, err := metricStreamTags(attrMap[metricStreamTagsAttr])
+	if err != nil {
+		return fmt.Errorf("metric %q: %w", m.Name, err)
+	}
+
+	m.streamTags = streamTags
+	if len(m.streamTags) > 0 {
+		if strings.Contains(m.Name, metricStreamTagsPrefix) {
+			return fmt.Errorf("metric %q already has stream tags in its %s, they can't be combined with %s", m.Name, metricNameAttr, metricStreamTagsAttr)
+		}
+
+		m.Name = encodeMetricName(m.Name, m.streamTags)
+	}
+
+	if v, found := attrMap[metricHistogramAttr]; found {
+		if l, ok := v.([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			if m.Type != metricTypeHistogram {
+				return fmt.Errorf("metric %q has %s settings but a %s of %q, %s settings require %q", m.baseName, metricHistogramAttr, metricTypeAttr, m.Type, metricHistogramAttr, metricTypeHistogram)
+			}
+
+			if err := m.parseHistogram(newInterfaceMap(l[0])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseHistogram stores the unit of a histogram block as the metric's units,
+// and its buckets, which must be in ascending order.
+func (m *circonusMetric) parseHistogram(hm interfaceMap) error {
+	if v, ok := hm[metricHistogramUnitAttr].(string); ok && v != "" {
+		unit := v
+		m.Units = &unit
+	}
+
+	l, _ := hm[metricHistogramBucketsAttr].([]interface{})
+	m.buckets = make([]float64, 0, len(l))
+	for i, v := range l {
+		bucket, _ := v.(float64)
+		if i > 0 && bucket <= m.buckets[i-1] {
+			return fmt.Errorf("metric %q %s %s must be unique and in ascending order", m.baseName, metricHistogramAttr, metricHistogramBucketsAttr)
+		}
+
+		m.buckets = append(m.buckets, bucket)
+	}
+
 	return nil
 }
 
@@ -65,7 +119,9 @@ func (m *circonusMetric) SaveState(d *schema.ResourceData) error {
 	d.SetId(string(m.ID))
 
 	_ = d.Set(metricActiveAttr, metricAPIStatusToBool(m.Status))
-	_ = d.Set(metricNameAttr, m.Name)
+	_ = d.Set(metricHistogramAttr, metricHistogramToState(m.CheckBundleMetric, metricHistogramBucketsToState(m.buckets)))
+	_ = d.Set(metricNameAttr, m.baseName)
+	_ = d.Set(metricStreamTagsAttr, metricStreamTagsToState(m.streamTags))
 	_ = d.Set(metricTypeAttr, m.Type)
 
 	return nil
@@ -78,6 +134,126 @@ func (m *circonusMetric) Update(d *schema.ResourceData) error {
 	return m.SaveState(d)
 }
 
+// metricToState returns the state of an API metric.  A metric whose name
+// matches a configured metric once stream tags are normalized keeps the
+// configured name and stream_tags, so differences in the order of the stream
+// tags don't show up as a diff, and its histogram buckets, which the API
+// doesn't store.  Other metrics have their stream tags decoded from their
+// name.
+func metricToState(m api.CheckBundleMetric, configured map[string]interfaceMap) map[string]interface{} {
+	attrs := map[string]interface{}{
+		string(metricActiveAttr): metricAPIStatusToBool(m.Status),
+		string(metricTypeAttr):   m.Type,
+	}
+
+	if cm, found := configured[canonicalMetricName(m.Name)]; found {
+		streamTags, _ := metricStreamTags(cm[metricStreamTagsAttr])
+
+		attrs[string(metricHistogramAttr)] = metricHistogramToState(m, metricHistogramBuckets(cm))
+		attrs[string(metricNameAttr)] = cm[metricNameAttr]
+		attrs[string(metricStreamTagsAttr)] = metricStreamTagsToState(streamTags)
+		return attrs
+	}
+
+	name, tags, err := decodeMetricName(m.Name)
+	if err != nil {
+		name, tags = m.Name, nil
+	}
+
+	attrs[string(metricHistogramAttr)] = metricHistogramToState(m, nil)
+	attrs[string(metricNameAttr)] = name
+	attrs[string(metricStreamTagsAttr)] = metricStreamTagsToState(tags)
+
+	return attrs
+}
+
+// metricStreamTags returns the category:value pairs of a stream_tags
+// attribute, read from the config or the state.
+func metricStreamTags(v interface{}) ([]string, error) {
+	switch tags := v.(type) {
+	case *schema.Set:
+		return interfaceList(tags.List()).List()
+	case []interface{}:
+		return interfaceList(tags).List()
+	}
+
+	return nil, nil
+}
+
+// metricStreamTagsToState returns the stream_tags attribute of tags.
+func metricStreamTagsToState(tags []string) []interface{} {
+	l := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		l = append(l, tag)
+	}
+
+	return l
+}
+
+// configuredMetrics indexes a list of metric blocks by their canonical
+// encoded name.
+func configuredMetrics(l []interface{}) map[string]interfaceMap {
+	metrics := make(map[string]interfaceMap, len(l))
+	for _, v := range l {
+		if v == nil {
+			continue
+		}
+
+		cm := newInterfaceMap(v)
+		name, _ := cm[metricNameAttr].(string)
+		tags, _ := metricStreamTags(cm[metricStreamTagsAttr])
+		metrics[canonicalMetricName(encodeMetricName(name, tags))] = cm
+	}
+
+	return metrics
+}
+
+// metricHistogramToState returns the histogram block of an API metric and its
+// buckets, or nil if the metric has no histogram settings.
+func metricHistogramToState(m api.CheckBundleMetric, buckets []interface{}) []interface{} {
+	var unit string
+	if m.Units != nil {
+		unit = *m.Units
+	}
+
+	if m.Type != metricTypeHistogram || (unit == "" && len(buckets) == 0) {
+		return nil
+	}
+
+	if buckets == nil {
+		buckets = make([]interface{}, 0)
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			string(metricHistogramBucketsAttr): buckets,
+			string(metricHistogramUnitAttr):    unit,
+		},
+	}
+}
+
+// metricHistogramBuckets returns the buckets of the histogram block of a
+// configured metric.
+func metricHistogramBuckets(m interfaceMap) []interface{} {
+	l, _ := m[metricHistogramAttr].([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	buckets, _ := newInterfaceMap(l[0])[metricHistogramBucketsAttr].([]interface{})
+	return buckets
+}
+
+// metricHistogramBucketsToState returns the buckets attribute of buckets.
+func metricHistogramBucketsToState(buckets []float64) []interface{} {
+	l := make([]interface{}, 0, len(buckets))
+	for _, bucket := range buckets {
+		l = append(l, bucket)
+	}
+
+	return l
+}
+
 func metricAPIStatusToBool(s string) bool {
 	switch s {
 	case metricStatusActive:
@@ -117,10 +293,29 @@ func metricChecksum(m interfaceMap) int {
 		fmt.Fprintf(b, "%t", v.(bool))
 	}
 
+	if v, found := m[metricHistogramAttr]; found {
+		if l, ok := v.([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			hm := newInterfaceMap(l[0])
+			if buckets, ok := hm[metricHistogramBucketsAttr].([]interface{}); ok {
+				for _, bucket := range buckets {
+					fmt.Fprint(b, strconv.FormatFloat(bucket.(float64), 'g', -1, 64))
+				}
+			}
+
+			if unit, ok := hm[metricHistogramUnitAttr].(string); ok {
+				fmt.Fprint(b, unit)
+			}
+		}
+	}
+
 	if v, found := m[metricNameAttr]; found {
 		fmt.Fprint(b, v.(string))
 	}
 
+	if tags, _ := metricStreamTags(m[metricStreamTagsAttr]); len(tags) > 0 {
+		fmt.Fprint(b, encodeMetricName("", tags))
+	}
+
 	if v, found := m[metricTypeAttr]; found {
 		fmt.Fprint(b, v.(string))
 	}
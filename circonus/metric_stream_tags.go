@@ -0,0 +1,187 @@
+package circonus
+
+// Stream tags are encoded in a metric's name: `name|ST[category:value,...]`.
+// Categories and values with characters outside of the plain set are base64
+// encoded and quoted, e.g. `b"ZW52"`.
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	metricStreamTagsPrefix = "|ST["
+	metricStreamTagsSuffix = "]"
+)
+
+var (
+	metricStreamTagCategoryRE = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+	metricStreamTagValueRE    = regexp.MustCompile(`^[A-Za-z0-9_\-.:/=@+]*$`)
+)
+
+// encodeMetricName returns name with tags, a list of category:value pairs,
+// appended as stream tags.  Tags are written sorted by category and then value
+// so the same tags always produce the same name.  A category may be repeated
+// with different values.
+func encodeMetricName(name string, tags []string) string {
+	if len(tags) == 0 {
+		return name
+	}
+
+	sorted := make([][2]string, 0, len(tags))
+	for _, tag := range tags {
+		category, value := splitMetricStreamTag(tag)
+		sorted = append(sorted, [2]string{category, value})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][0] != sorted[j][0] {
+			return sorted[i][0] < sorted[j][0]
+		}
+
+		return sorted[i][1] < sorted[j][1]
+	})
+
+	pairs := make([]string, 0, len(sorted))
+	for _, tag := range sorted {
+		pairs = append(pairs, encodeMetricStreamTagPart(tag[0], metricStreamTagCategoryRE)+":"+encodeMetricStreamTagPart(tag[1], metricStreamTagValueRE))
+	}
+
+	return name + metricStreamTagsPrefix + strings.Join(pairs, ",") + metricStreamTagsSuffix
+}
+
+// splitMetricStreamTag splits a category:value pair on the colon after its
+// category.  Values may contain colons of their own.
+func splitMetricStreamTag(tag string) (string, string) {
+	if i := strings.IndexByte(tag, ':'); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+
+	return tag, ""
+}
+
+// cutMetricStreamTags cuts the encoded stream tags off a metric name.
+func cutMetricStreamTags(name string) (string, string) {
+	if i := strings.Index(name, metricStreamTagsPrefix); i >= 0 {
+		return name[:i], name[i:]
+	}
+
+	return name, ""
+}
+
+// decodeMetricName splits a metric name into its base name and stream tags, a
+// list of category:value pairs in the order they appear in the name.
+func decodeMetricName(full string) (string, []string, error) {
+	i := strings.Index(full, metricStreamTagsPrefix)
+	if i < 0 {
+		return full, nil, nil
+	}
+
+	name := full[:i]
+	encoded := full[i+len(metricStreamTagsPrefix):]
+	if !strings.HasSuffix(encoded, metricStreamTagsSuffix) {
+		return "", nil, fmt.Errorf("metric %q has unterminated stream tags", full)
+	}
+	encoded = strings.TrimSuffix(encoded, metricStreamTagsSuffix)
+
+	tags := make([]string, 0)
+	if encoded == "" {
+		return name, tags, nil
+	}
+
+	for _, pair := range splitMetricStreamTags(encoded) {
+		rawCategory, rawValue, found := cutMetricStreamTag(pair)
+		if !found {
+			return "", nil, fmt.Errorf("metric %q has an invalid stream tag %q", full, pair)
+		}
+
+		category, err := decodeMetricStreamTagPart(rawCategory)
+		if err != nil {
+			return "", nil, fmt.Errorf("metric %q has an invalid stream tag category %q: %w", full, rawCategory, err)
+		}
+
+		value, err := decodeMetricStreamTagPart(rawValue)
+		if err != nil {
+			return "", nil, fmt.Errorf("metric %q has an invalid stream tag value %q: %w", full, rawValue, err)
+		}
+
+		tags = append(tags, category+":"+value)
+	}
+
+	return name, tags, nil
+}
+
+// canonicalMetricName returns full with its stream tags sorted and encoded
+// the way encodeMetricName writes them.  Names that can't be decoded are
+// returned unchanged.
+func canonicalMetricName(full string) string {
+	name, tags, err := decodeMetricName(full)
+	if err != nil {
+		return full
+	}
+
+	return encodeMetricName(name, tags)
+}
+
+func encodeMetricStreamTagPart(s string, plain *regexp.Regexp) string {
+	if plain.MatchString(s) {
+		return s
+	}
+
+	return `b"` + base64.StdEncoding.EncodeToString([]byte(s)) + `"`
+}
+
+func decodeMetricStreamTagPart(s string) (string, error) {
+	if !strings.HasPrefix(s, `b"`) {
+		return s, nil
+	}
+
+	if len(s) < 3 || !strings.HasSuffix(s, `"`) {
+		return "", fmt.Errorf("unterminated base64 string")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s[2 : len(s)-1])
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// splitMetricStreamTags splits encoded stream tags on the commas between
+// them, ignoring commas within quoted parts.
+func splitMetricStreamTags(s string) []string {
+	var pairs []string
+	quoted := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			pairs = append(pairs, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(pairs, s[start:])
+}
+
+// cutMetricStreamTag splits an encoded stream tag on the colon after its
+// category.
+func cutMetricStreamTag(pair string) (string, string, bool) {
+	quoted := false
+	for i, r := range pair {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			return pair[:i], pair[i+1:], i > 0
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,70 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_MetricStreamTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		decoded []string
+		encoded string
+	}{
+		{name: "cpu", encoded: "cpu"},
+		{name: "cpu", tags: []string{"env:prod"}, encoded: "cpu|ST[env:prod]"},
+		{name: "cpu", tags: []string{"host:web1", "env:prod"}, decoded: []string{"env:prod", "host:web1"}, encoded: "cpu|ST[env:prod,host:web1]"},
+		{name: "cpu", tags: []string{"env:b", "env:a"}, decoded: []string{"env:a", "env:b"}, encoded: "cpu|ST[env:a,env:b]"},
+		{name: "cpu", tags: []string{"url:https://example.com/a"}, encoded: "cpu|ST[url:https://example.com/a]"},
+		{name: "cpu", tags: []string{"env:prod,dev"}, encoded: `cpu|ST[env:b"cHJvZCxkZXY="]`},
+		{name: "cpu", tags: []string{"a b:c]"}, encoded: `cpu|ST[b"YSBi":b"Y10="]`},
+		{name: "cpu", tags: []string{"empty:"}, encoded: "cpu|ST[empty:]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			if encoded := encodeMetricName(test.name, test.tags); encoded != test.encoded {
+				t.Fatalf("encodeMetricName() = %q, expected %q", encoded, test.encoded)
+			}
+
+			name, tags, err := decodeMetricName(test.encoded)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != test.name {
+				t.Fatalf("decodeMetricName() name = %q, expected %q", name, test.name)
+			}
+			decoded := test.decoded
+			if decoded == nil {
+				decoded = test.tags
+			}
+			if len(tags) != len(decoded) || (len(tags) > 0 && !reflect.DeepEqual(tags, decoded)) {
+				t.Fatalf("decodeMetricName() tags = %v, expected %v", tags, decoded)
+			}
+		})
+	}
+}
+
+func Test_CanonicalMetricName(t *testing.T) {
+	tests := []struct {
+		name      string
+		canonical string
+	}{
+		{name: "cpu", canonical: "cpu"},
+		{name: "cpu|ST[host:web1,env:prod]", canonical: "cpu|ST[env:prod,host:web1]"},
+		{name: `cpu|ST[b"ZW52":prod]`, canonical: "cpu|ST[env:prod]"},
+		{name: "cpu|ST[env:b,host:web1,env:a]", canonical: "cpu|ST[env:a,env:b,host:web1]"},
+		{name: "cpu|ST[]", canonical: "cpu"},
+		{name: "cpu|ST[env:prod", canonical: "cpu|ST[env:prod"},
+		{name: "cpu|ST[env]", canonical: "cpu|ST[env]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if canonical := canonicalMetricName(test.name); canonical != test.canonical {
+				t.Fatalf("canonicalMetricName() = %q, expected %q", canonical, test.canonical)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package circonus
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+)
 
 func Test_MetricChecksum(t *testing.T) {
 	m := interfaceMap{
@@ -13,3 +18,211 @@ func Test_MetricChecksum(t *testing.T) {
 		t.Fatalf("Checksum mismatch")
 	}
 }
+
+func Test_MetricParseConfigMap(t *testing.T) {
+	histogram := func(unit string, buckets ...interface{}) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				string(metricHistogramBucketsAttr): buckets,
+				string(metricHistogramUnitAttr):    unit,
+			},
+		}
+	}
+
+	tests := []struct {
+		testName string
+		attrs    map[string]interface{}
+		name     string
+		units    string
+		buckets  []float64
+		fail     bool
+	}{
+		{
+			testName: "plain",
+			attrs: map[string]interface{}{
+				string(metricNameAttr): "cpu",
+				string(metricTypeAttr): "numeric",
+			},
+			name: "cpu",
+		},
+		{
+			testName: "stream tags",
+			attrs: map[string]interface{}{
+				string(metricNameAttr):       "cpu",
+				string(metricStreamTagsAttr): []interface{}{"host:web1", "env:prod"},
+				string(metricTypeAttr):       "numeric",
+			},
+			name: "cpu|ST[env:prod,host:web1]",
+		},
+		{
+			testName: "repeated stream tag category",
+			attrs: map[string]interface{}{
+				string(metricNameAttr):       "cpu",
+				string(metricStreamTagsAttr): []interface{}{"env:b", "env:a"},
+				string(metricTypeAttr):       "numeric",
+			},
+			name: "cpu|ST[env:a,env:b]",
+		},
+		{
+			testName: "stream tags in name and stream_tags",
+			attrs: map[string]interface{}{
+				string(metricNameAttr):       "cpu|ST[env:prod]",
+				string(metricStreamTagsAttr): []interface{}{"host:web1"},
+				string(metricTypeAttr):       "numeric",
+			},
+			fail: true,
+		},
+		{
+			testName: "histogram",
+			attrs: map[string]interface{}{
+				string(metricHistogramAttr): histogram("ms", 1.0, 10.0, 100.0),
+				string(metricNameAttr):      "latency",
+				string(metricTypeAttr):      "histogram",
+			},
+			name:    "latency",
+			units:   "ms",
+			buckets: []float64{1, 10, 100},
+		},
+		{
+			testName: "histogram buckets out of order",
+			attrs: map[string]interface{}{
+				string(metricHistogramAttr): histogram("ms", 10.0, 1.0),
+				string(metricNameAttr):      "latency",
+				string(metricTypeAttr):      "histogram",
+			},
+			fail: true,
+		},
+		{
+			testName: "histogram buckets repeated",
+			attrs: map[string]interface{}{
+				string(metricHistogramAttr): histogram("ms", 1.0, 1.0),
+				string(metricNameAttr):      "latency",
+				string(metricTypeAttr):      "histogram",
+			},
+			fail: true,
+		},
+		{
+			testName: "histogram settings on a numeric metric",
+			attrs: map[string]interface{}{
+				string(metricHistogramAttr): histogram("ms"),
+				string(metricNameAttr):      "latency",
+				string(metricTypeAttr):      "numeric",
+			},
+			fail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			m := newMetric()
+			m.Tags = []string{"team:ops"}
+			err := m.ParseConfigMap("id", test.attrs)
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if m.Name != test.name {
+				t.Fatalf("name = %q, expected %q", m.Name, test.name)
+			}
+
+			var units string
+			if m.Units != nil {
+				units = *m.Units
+			}
+			if units != test.units {
+				t.Fatalf("units = %q, expected %q", units, test.units)
+			}
+
+			if !reflect.DeepEqual(m.Tags, []string{"team:ops"}) {
+				t.Fatalf("tags = %v, expected the metric's tags to be kept", m.Tags)
+			}
+
+			if len(m.buckets) != len(test.buckets) || (len(m.buckets) > 0 && !reflect.DeepEqual(m.buckets, test.buckets)) {
+				t.Fatalf("buckets = %v, expected %v", m.buckets, test.buckets)
+			}
+
+			if test.units != "" {
+				state := metricHistogramToState(m.CheckBundleMetric, metricHistogramBucketsToState(m.buckets))
+				if len(state) != 1 {
+					t.Fatalf("expected a histogram block, got %v", state)
+				}
+			}
+		})
+	}
+}
+
+func Test_MetricToState(t *testing.T) {
+	configured := configuredMetrics([]interface{}{
+		map[string]interface{}{
+			string(metricNameAttr):       "cpu|ST[host:web1,env:prod]",
+			string(metricStreamTagsAttr): []interface{}{},
+		},
+		map[string]interface{}{
+			string(metricNameAttr):       "mem",
+			string(metricStreamTagsAttr): []interface{}{"host:web1", "env:prod"},
+		},
+		map[string]interface{}{
+			string(metricHistogramAttr): []interface{}{
+				map[string]interface{}{
+					string(metricHistogramBucketsAttr): []interface{}{1.0, 10.0},
+					string(metricHistogramUnitAttr):    "ms",
+				},
+			},
+			string(metricNameAttr):       "latency",
+			string(metricStreamTagsAttr): []interface{}{},
+		},
+	})
+
+	tests := []struct {
+		apiName    string
+		name       string
+		metricType string
+		streamTags []interface{}
+		histogram  []interface{}
+	}{
+		{apiName: "cpu|ST[env:prod,host:web1]", name: "cpu|ST[host:web1,env:prod]", streamTags: []interface{}{}},
+		{apiName: "mem|ST[env:prod,host:web1]", name: "mem", streamTags: []interface{}{"host:web1", "env:prod"}},
+		{apiName: "disk|ST[host:web1]", name: "disk", streamTags: []interface{}{"host:web1"}},
+		{apiName: "disk|ST[env:a,env:b]", name: "disk", streamTags: []interface{}{"env:a", "env:b"}},
+		{apiName: "disk", name: "disk", streamTags: []interface{}{}},
+		{
+			apiName:    "latency",
+			name:       "latency",
+			metricType: "histogram",
+			streamTags: []interface{}{},
+			histogram: []interface{}{
+				map[string]interface{}{
+					string(metricHistogramBucketsAttr): []interface{}{1.0, 10.0},
+					string(metricHistogramUnitAttr):    "ms",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.apiName, func(t *testing.T) {
+			m := api.CheckBundleMetric{Name: test.apiName, Type: "numeric"}
+			if test.metricType != "" {
+				units := "ms"
+				m.Type, m.Units = test.metricType, &units
+			}
+
+			attrs := metricToState(m, configured)
+			if attrs[string(metricNameAttr)] != test.name {
+				t.Fatalf("name = %q, expected %q", attrs[string(metricNameAttr)], test.name)
+			}
+			if !reflect.DeepEqual(attrs[string(metricStreamTagsAttr)], test.streamTags) {
+				t.Fatalf("stream_tags = %v, expected %v", attrs[string(metricStreamTagsAttr)], test.streamTags)
+			}
+			if histogram, _ := attrs[string(metricHistogramAttr)].([]interface{}); len(histogram) != len(test.histogram) || (len(histogram) > 0 && !reflect.DeepEqual(histogram, test.histogram)) {
+				t.Fatalf("histogram = %v, expected %v", histogram, test.histogram)
+			}
+		})
+	}
+}
@@ -3,9 +3,7 @@ package circonus
 
 import (
 	"context"
-	"log"
-	"os"
-	"strings"
+	"sync"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -14,6 +12,7 @@ import (
 
 const (
 	defaultCirconus404ErrorString        = "API response code 404:"
+	defaultCirconusResponseErrorString   = "API response code "
 	defaultCirconusAggregationWindow     = "300s"
 	defaultCirconusAlertMinEscalateAfter = "300s"
 	defaultCirconusCheckPeriodMax        = "300s"
@@ -27,6 +26,23 @@ const (
 	minSeverity                          = 0
 )
 
+// Resource and data source type names, also used to tag log output.
+const (
+	dataSourceTypeAccount     = "circonus_account"
+	dataSourceTypeCollector   = "circonus_collector"
+	resourceTypeCheck         = "circonus_check"
+	resourceTypeCheckTemplate = "circonus_check_template"
+	resourceTypeContactGroup  = "circonus_contact_group"
+	resourceTypeDashboard     = "circonus_dashboard"
+	resourceTypeGraph         = "circonus_graph"
+	resourceTypeMaintenance   = "circonus_maintenance"
+	resourceTypeMetric        = "circonus_metric"
+	resourceTypeOverlaySet    = "circonus_overlay_set"
+	resourceTypeRuleSet       = "circonus_rule_set"
+	resourceTypeRuleSetGroup  = "circonus_rule_set_group"
+	resourceTypeWorksheet     = "circonus_worksheet"
+)
+
 var providerDescription = map[string]string{
 	providerAPIURLAttr:  "URL of the Circonus API",
 	providerAutoTagAttr: "Signals that the provider should automatically add a tag to all API calls denoting that the resource was created by Terraform",
@@ -52,10 +68,19 @@ var globalAutoTag bool //nolint:unused
 type providerContext struct {
 	// Circonus API client
 	client *api.API
+	// apiConfig is the client configuration from which apiRequest creates a
+	// client, logging against the request's context, for each request.
+	apiConfig api.Config
 	// defaultTag make up the tag to be used when autoTag tags a tag.
 	defaultTag circonusTag
+	// token is the API token, kept so it can be masked in log output
+	token string
 	// autoTag, when true, automatically appends defaultCirconusTag
 	autoTag bool
+
+	// collectors caches the collectors fetched by fetchCollector.
+	collectorsMu sync.Mutex
+	collectors   map[string]*api.Broker
 }
 
 // Provider returns a terraform.ResourceProvider.
@@ -84,21 +109,22 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"circonus_account":   dataSourceCirconusAccount(),
-			"circonus_collector": dataSourceCirconusCollector(),
+			dataSourceTypeAccount:   dataSourceCirconusAccount(),
+			dataSourceTypeCollector: dataSourceCirconusCollector(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"circonus_check":          resourceCheck(),
-			"circonus_contact_group":  resourceContactGroup(),
-			"circonus_graph":          resourceGraph(),
-			"circonus_overlay_set":    resourceOverlaySet(),
-			"circonus_dashboard":      resourceDashboard(),
-			"circonus_maintenance":    resourceMaintenance(),
-			"circonus_metric":         resourceMetric(),
-			"circonus_rule_set":       resourceRuleSet(),
-			"circonus_rule_set_group": resourceRuleSetGroup(),
-			"circonus_worksheet":      resourceWorksheet(),
+			resourceTypeCheck:         resourceCheck(),
+			resourceTypeCheckTemplate: resourceCheckTemplate(),
+			resourceTypeContactGroup:  resourceContactGroup(),
+			resourceTypeGraph:         resourceGraph(),
+			resourceTypeOverlaySet:    resourceOverlaySet(),
+			resourceTypeDashboard:     resourceDashboard(),
+			resourceTypeMaintenance:   resourceMaintenance(),
+			resourceTypeMetric:        resourceMetric(),
+			resourceTypeRuleSet:       resourceRuleSet(),
+			resourceTypeRuleSetGroup:  resourceRuleSetGroup(),
+			resourceTypeWorksheet:     resourceWorksheet(),
 		},
 
 		ConfigureContextFunc: providerConfigure,
@@ -110,20 +136,16 @@ func Provider() *schema.Provider {
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	globalAutoTag = d.Get(providerAutoTagAttr).(bool)
 
-	debug := false
-	if strings.Contains("TRACE|DEBUG", os.Getenv("TF_LOG")) { //nolint:gocritic
-		debug = true
-	}
+	token := d.Get(providerKeyAttr).(string)
 
+	// go-apiclient always logs in debug mode; tflog decides what is emitted
+	// based on TF_LOG and TF_LOG_PROVIDER_CIRCONUS_API.
 	config := &api.Config{
 		URL:      d.Get(providerAPIURLAttr).(string),
-		TokenKey: d.Get(providerKeyAttr).(string),
+		TokenKey: token,
 		TokenApp: "terraform-provider-circonus",
-	}
-
-	if debug {
-		config.Debug = true
-		config.Log = log.New(log.Writer(), "", log.LstdFlags)
+		Debug:    true,
+		Log:      &apiClientLogger{ctx: maskLogContext(ctx, token)},
 	}
 
 	var diags diag.Diagnostics
@@ -137,7 +159,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 	return &providerContext{
 		client:     client,
+		apiConfig:  *config,
 		autoTag:    d.Get(providerAutoTagAttr).(bool),
 		defaultTag: defaultCirconusTag,
+		token:      token,
 	}, diags
 }
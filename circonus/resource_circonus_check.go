@@ -20,49 +20,34 @@ package circonus
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_check.* global resource attribute names.
-	checkActiveAttr       = "active"
-	checkCAQLAttr         = "caql"
-	checkCloudWatchAttr   = "cloudwatch"
-	checkCollectorAttr    = "collector"
-	checkConsulAttr       = "consul"
-	checkDNSAttr          = "dns"
-	checkExternalAttr     = "external"
-	checkHTTPAttr         = "http"
-	checkHTTPTrapAttr     = "httptrap"
-	checkICMPPingAttr     = "icmp_ping"
-	checkJMXAttr          = "jmx"
-	checkJSONAttr         = "json"
-	checkMemcachedAttr    = "memcached"
-	checkMetricAttr       = "metric"
-	checkMetricFilterAttr = "metric_filter"
-	checkMetricLimitAttr  = "metric_limit"
-	checkMySQLAttr        = "mysql"
-	checkNameAttr         = "name"
-	checkNTPAttr          = "ntp"
-	checkNotesAttr        = "notes"
-	checkPeriodAttr       = "period"
-	checkPostgreSQLAttr   = "postgresql"
-	checkPromTextAttr     = "promtext"
-	checkRedisAttr        = "redis"
-	checkSMTPAttr         = "smtp"
-	checkSNMPAttr         = "snmp"
-	checkSSH2Attr         = "ssh2"
-	checkStatsdAttr       = "statsd"
-	checkTCPAttr          = "tcp"
-	checkTagsAttr         = "tags"
-	checkTargetAttr       = "target"
-	checkTimeoutAttr      = "timeout"
-	checkTypeAttr         = "type"
+	checkActiveAttr            = "active"
+	checkCollectorAttr         = "collector"
+	checkCollectorSelectorAttr = "collector_selector"
+	checkMetricAttr            = "metric"
+	checkMetricFilterAttr      = "metric_filter"
+	checkMetricLimitAttr       = "metric_limit"
+	checkMigrateAttr           = "migrate"
+	checkNameAttr              = "name"
+	checkNotesAttr             = "notes"
+	checkPeriodAttr            = "period"
+	checkTagsAttr              = "tags"
+	checkTargetAttr            = "target"
+	checkTimeoutAttr           = "timeout"
+	checkTypeAttr              = "type"
+	checkWaitForFirstRunAttr   = "wait_for_first_run"
 
 	// circonus_check.collector.* resource attribute names.
 	checkCollectorIDAttr = "id"
@@ -75,83 +60,50 @@ const (
 
 	// Out parameters for circonus_check.
 	checkOutByCollectorAttr        = "check_by_collector"
+	checkOutCertExpiryRuleSetsAttr = "cert_expiry_rule_sets"
 	checkOutIDAttr                 = "check_id"
 	checkOutChecksAttr             = "checks"
 	checkOutCreatedAttr            = "created"
 	checkOutLastModifiedAttr       = "last_modified"
 	checkOutLastModifiedByAttr     = "last_modified_by"
+	checkOutMigrationPlanAttr      = "migration_plan"
+	checkOutPendingMigrationAttr   = "pending_migration"
 	checkOutReverseConnectURLsAttr = "reverse_connect_urls"
+	checkOutStatusAttr             = "check_status"
+	checkOutSubmissionURLAttr      = "submission_url"
 	checkOutCheckUUIDsAttr         = "uuids"
 )
 
-const (
-	// Circonus API constants from their API endpoints.
-	apiCheckTypeCAQLAttr       apiCheckType = "caql"
-	apiCheckTypeCloudWatchAttr apiCheckType = "cloudwatch"
-	apiCheckTypeConsulAttr     apiCheckType = "consul"
-	apiCheckTypeDNSAttr        apiCheckType = "dns"
-	apiCheckTypeExternalAttr   apiCheckType = "external"
-	apiCheckTypeHTTPAttr       apiCheckType = "http"
-	apiCheckTypeHTTPTrapAttr   apiCheckType = "httptrap"
-	apiCheckTypeJMXAttr        apiCheckType = "jmx"
-	apiCheckTypeMemcachedAttr  apiCheckType = "memcached"
-	apiCheckTypeICMPPingAttr   apiCheckType = "ping_icmp"
-	apiCheckTypeJSONAttr       apiCheckType = "json"
-	apiCheckTypeMySQLAttr      apiCheckType = "mysql"
-	apiCheckTypeNTPAttr        apiCheckType = "ntp"
-	apiCheckTypePostgreSQLAttr apiCheckType = "postgres"
-	apiCheckTypePromTextAttr   apiCheckType = "promtext"
-	apiCheckTypeRedisAttr      apiCheckType = "redis"
-	apiCheckTypeSMTPAttr       apiCheckType = "smtp"
-	apiCheckTypeSNMPAttr       apiCheckType = "snmp"
-	apiCheckTypeSSH2Attr       apiCheckType = "ssh2"
-	apiCheckTypeStatsdAttr     apiCheckType = "statsd"
-	apiCheckTypeTCPAttr        apiCheckType = "tcp"
-)
-
 var checkDescriptions = attrDescrs{
-	checkActiveAttr:       "If the check is activate or disabled",
-	checkCAQLAttr:         "CAQL check configuration",
-	checkCloudWatchAttr:   "CloudWatch check configuration",
-	checkCollectorAttr:    "The collector(s) that are responsible for gathering the metrics",
-	checkConsulAttr:       "Consul check configuration",
-	checkDNSAttr:          "DNS check configuration",
-	checkExternalAttr:     "External check configuration",
-	checkHTTPAttr:         "HTTP check configuration",
-	checkHTTPTrapAttr:     "HTTP Trap check configuration",
-	checkICMPPingAttr:     "ICMP ping check configuration",
-	checkJMXAttr:          "JMX check configuration",
-	checkJSONAttr:         "JSON check configuration",
-	checkMemcachedAttr:    "Memcached check configuration",
-	checkMetricAttr:       "Configuration for a stream of metrics",
-	checkMetricFilterAttr: "Allow/deny configuration for regex based metric ingestion",
-	checkMetricLimitAttr:  `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer)`,
-	checkMySQLAttr:        "MySQL check configuration",
-	checkNameAttr:         "The name of the check bundle that will be displayed in the web interface",
-	checkNTPAttr:          "NTP check configuration",
-	checkNotesAttr:        "Notes about this check bundle",
-	checkPeriodAttr:       "The period between each time the check is made",
-	checkPostgreSQLAttr:   "PostgreSQL check configuration",
-	checkPromTextAttr:     "Prometheus URL scraper check configuration",
-	checkSMTPAttr:         "SMTP check configuration",
-	checkRedisAttr:        "Redis check configuration",
-	checkSNMPAttr:         "SNMP check configuration",
-	checkSSH2Attr:         "SSH2 check configuration",
-	checkStatsdAttr:       "statsd check configuration",
-	checkTCPAttr:          "TCP check configuration",
-	checkTagsAttr:         "A list of tags assigned to the check",
-	checkTargetAttr:       "The target of the check (e.g. hostname, URL, IP, etc)",
-	checkTimeoutAttr:      "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
-	checkTypeAttr:         "The check type",
+	checkActiveAttr:            "If the check is activate or disabled",
+	checkCollectorAttr:         "The collector(s) that are responsible for gathering the metrics",
+	checkCollectorSelectorAttr: "Selects the collectors responsible for gathering the metrics through the collector search API, instead of listing them in collector",
+	checkMetricAttr:            "Configuration for a stream of metrics",
+	checkMetricFilterAttr:      "Allow/deny configuration for regex based metric ingestion",
+	checkMetricLimitAttr:       `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer)`,
+	checkMigrateAttr:           "Migrate the check's rule sets, graphs and dashboards to the new check bundle or checks when its type or collectors change, instead of replacing it",
+	checkNameAttr:              "The name of the check bundle that will be displayed in the web interface",
+	checkNotesAttr:             "Notes about this check bundle",
+	checkPeriodAttr:            "The period between each time the check is made",
+	checkTagsAttr:              "A list of tags assigned to the check",
+	checkTargetAttr:            "The target of the check (e.g. hostname, URL, IP, etc)",
+	checkTimeoutAttr:           "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
+	checkTypeAttr:              "The check type",
+	checkWaitForFirstRunAttr:   "Wait for the check to run successfully on every collector when it is created",
 
 	checkOutByCollectorAttr:        "",
+	checkOutCertExpiryRuleSetsAttr: "The rule sets managed by the check's cert_expiry block",
 	checkOutCheckUUIDsAttr:         "",
 	checkOutChecksAttr:             "",
 	checkOutCreatedAttr:            "",
 	checkOutIDAttr:                 "",
 	checkOutLastModifiedAttr:       "",
 	checkOutLastModifiedByAttr:     "",
+	checkOutMigrationPlanAttr:      "The rule sets, graphs and dashboards of the check's planned or last migration, and what it does to each",
+	checkOutPendingMigrationAttr:   "The progress of a migration an apply didn't complete, resumed by the next apply",
 	checkOutReverseConnectURLsAttr: "",
+	checkOutStatusAttr:             "The operational status of the check on each collector",
+	checkOutSubmissionURLAttr:      "",
 }
 
 var checkCollectorDescriptions = attrDescrs{
@@ -161,15 +113,221 @@ var checkCollectorDescriptions = attrDescrs{
 var (
 	checkMetricDescriptions       = metricDescriptions
 	checkMetricFilterDescriptions = attrDescrs{
-		"type":      "'allow' or 'deny'",
-		"regex":     "Regex of the filter",
-		"comment":   "Comment on this filter",
-		"tag_query": "The tag query to apply",
+		checkMetricFilterTypeAttr:     "'allow' or 'deny'",
+		checkMetricFilterRegexAttr:    "Regex of the filter",
+		checkMetricFilterCommentAttr:  "Comment on this filter",
+		checkMetricFilterTagQueryAttr: "The tag query to apply",
 	}
 )
 
 func resourceCheck() *schema.Resource {
-	return &schema.Resource{
+	checkSchema := map[schemaAttr]*schema.Schema{
+		// Out parameters
+		// _cid
+		checkOutIDAttr: {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// _brokers
+		checkOutByCollectorAttr: {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		// rule sets of the http or tcp cert_expiry block
+		checkOutCertExpiryRuleSetsAttr: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		// _check_uuids
+		checkOutCheckUUIDsAttr: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		// _checks
+		checkOutChecksAttr: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		// _created
+		checkOutCreatedAttr: {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		// _last_modified
+		checkOutLastModifiedAttr: {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		// _last_modified_by
+		checkOutLastModifiedByAttr: {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		// _reverse_connection_urls
+		checkOutReverseConnectURLsAttr: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		// the dependents of a migration
+		checkOutMigrationPlanAttr: schemaCheckMigrationPlan(),
+		// a migration left part way through
+		checkOutPendingMigrationAttr: schemaCheckPendingMigration(),
+		// _details of each check
+		checkOutStatusAttr: schemaCheckStatus(),
+		// submission_url
+		checkOutSubmissionURLAttr: {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		// brokers
+		checkCollectorAttr: {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Computed: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: convertToHelperSchema(checkCollectorDescriptions, map[schemaAttr]*schema.Schema{
+					checkCollectorIDAttr: {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateRegexp(checkCollectorIDAttr, config.BrokerCIDRegex),
+					},
+				}),
+			},
+		},
+		// resolved into brokers
+		checkCollectorSelectorAttr: schemaCheckCollectorSelector(),
+		// display_name
+		checkNameAttr: {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		// metric_filters
+		checkMetricFilterAttr: schemaCheckMetricFilter(),
+		// metric_limit
+		checkMetricLimitAttr: {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+			ValidateFunc: validateFuncs(
+				validateIntMin(checkMetricLimitAttr, -1),
+			),
+		},
+		// not stored in the API
+		checkMigrateAttr: {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		// metrics
+		checkMetricAttr: {
+			Type:     schema.TypeList,
+			Optional: true,
+			MinItems: 0,
+			Elem: &schema.Resource{
+				Schema: convertToHelperSchema(checkMetricDescriptions, map[schemaAttr]*schema.Schema{
+					metricActiveAttr: {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					metricHistogramAttr: schemaMetricHistogram(),
+					metricNameAttr: {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
+					},
+					metricStreamTagsAttr: schemaMetricStreamTags(),
+					metricTypeAttr: {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateMetricType,
+					},
+				}),
+			},
+		},
+		// notes
+		checkNotesAttr: {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Computed:  true,
+			StateFunc: suppressWhitespace,
+		},
+		// period
+		checkPeriodAttr: {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Computed:  true,
+			StateFunc: normalizeTimeDurationStringToSeconds,
+			ValidateFunc: validateFuncs(
+				validateDurationMin(checkPeriodAttr, defaultCirconusCheckPeriodMin),
+				validateDurationMax(checkPeriodAttr, defaultCirconusCheckPeriodMax),
+			),
+		},
+		// status
+		checkActiveAttr: {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		// tags
+		checkTagsAttr: tagMakeConfigSchema(checkTagsAttr),
+		// target
+		checkTargetAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateRegexp(checkTargetAttr, `.+`),
+		},
+		// timeout
+		checkTimeoutAttr: {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Computed:  true,
+			StateFunc: normalizeTimeDurationStringToSeconds,
+			ValidateFunc: validateFuncs(
+				validateDurationMin(checkTimeoutAttr, defaultCirconusTimeoutMin),
+				validateDurationMax(checkTimeoutAttr, defaultCirconusTimeoutMax),
+			),
+		},
+		// type
+		checkTypeAttr: {
+			Type:         schema.TypeString,
+			Computed:     true,
+			Optional:     true,
+			ValidateFunc: validateCheckType,
+		},
+		// only used on create
+		checkWaitForFirstRunAttr: {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+
+	// specific check types, their attributes go into the
+	// check_bundle.config attribute
+	for _, t := range registeredCheckTypes() {
+		checkSchema[t.attr] = t.schema
+	}
+
+	r := &schema.Resource{
 		CreateContext: checkCreate,
 		ReadContext:   checkRead,
 		UpdateContext: checkUpdate,
@@ -178,239 +336,59 @@ func resourceCheck() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: checkCustomizeDiff,
+		Timeouts:      resourceTimeouts(),
+		SchemaVersion: 2,
 
-		Schema: convertToHelperSchema(checkDescriptions, map[schemaAttr]*schema.Schema{
-			// Out parameters
-			// _cid
-			checkOutIDAttr: {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			// _brokers
-			checkOutByCollectorAttr: {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			// _check_uuids
-			checkOutCheckUUIDsAttr: {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			// _checks
-			checkOutChecksAttr: {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			// _created
-			checkOutCreatedAttr: {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			// _last_modified
-			checkOutLastModifiedAttr: {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			// _last_modified_by
-			checkOutLastModifiedByAttr: {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			// _reverse_connection_urls
-			checkOutReverseConnectURLsAttr: {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			// brokers
-			checkCollectorAttr: {
-				Type:     schema.TypeSet,
-				Optional: true,
-				MinItems: 1,
-				Elem: &schema.Resource{
-					Schema: convertToHelperSchema(checkCollectorDescriptions, map[schemaAttr]*schema.Schema{
-						checkCollectorIDAttr: {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateRegexp(checkCollectorIDAttr, config.BrokerCIDRegex),
-						},
-					}),
-				},
-			},
-			// display_name
-			checkNameAttr: {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			// metric_filters
-			checkMetricFilterAttr: {
-				Type:     schema.TypeList, // order matters here so use a List
-				Optional: true,
-				MinItems: 0,
-				Elem: &schema.Resource{
-					Schema: convertToHelperSchema(checkMetricFilterDescriptions, map[schemaAttr]*schema.Schema{
-						"type": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateRegexp("type", `allow|deny`),
-						},
-						"regex": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateRegexp(metricNameAttr, `.+`),
-						},
-						"comment": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validateRegexp(metricNameAttr, `.+`),
-						},
-						"tag_query": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validateRegexp(metricNameAttr, `.+`),
-						},
-					}),
-				},
-			},
-			// metric_limit
-			checkMetricLimitAttr: {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
-				ValidateFunc: validateFuncs(
-					validateIntMin(checkMetricLimitAttr, -1),
-				),
-			},
-			// metrics
-			checkMetricAttr: {
-				Type:     schema.TypeList,
-				Optional: true,
-				MinItems: 0,
-				Elem: &schema.Resource{
-					Schema: convertToHelperSchema(checkMetricDescriptions, map[schemaAttr]*schema.Schema{
-						metricActiveAttr: {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-						metricNameAttr: {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
-						},
-						metricTypeAttr: {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateMetricType,
-						},
-					}),
-				},
-			},
-			// notes
-			checkNotesAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Computed:  true,
-				StateFunc: suppressWhitespace,
-			},
-			// period
-			checkPeriodAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Computed:  true,
-				StateFunc: normalizeTimeDurationStringToSeconds,
-				ValidateFunc: validateFuncs(
-					validateDurationMin(checkPeriodAttr, defaultCirconusCheckPeriodMin),
-					validateDurationMax(checkPeriodAttr, defaultCirconusCheckPeriodMax),
-				),
-			},
-			// status
-			checkActiveAttr: {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
-			},
-			// tags
-			checkTagsAttr: tagMakeConfigSchema(checkTagsAttr),
-			// target
-			checkTargetAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Computed:     true,
-				ValidateFunc: validateRegexp(checkTargetAttr, `.+`),
-			},
-			// timeout
-			checkTimeoutAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Computed:  true,
-				StateFunc: normalizeTimeDurationStringToSeconds,
-				ValidateFunc: validateFuncs(
-					validateDurationMin(checkTimeoutAttr, defaultCirconusTimeoutMin),
-					validateDurationMax(checkTimeoutAttr, defaultCirconusTimeoutMax),
-				),
-			},
-			// type
-			checkTypeAttr: {
-				Type:         schema.TypeString,
-				Computed:     true,
-				Optional:     true,
-				ForceNew:     true,
-				ValidateFunc: validateCheckType,
-			},
-			//
-			// specific check types, their attributes go into
-			// the check_bundle.config attribute
-			//
-			checkCAQLAttr:       schemaCheckCAQL,
-			checkCloudWatchAttr: schemaCheckCloudWatch,
-			checkConsulAttr:     schemaCheckConsul,
-			checkDNSAttr:        schemaCheckDNS,
-			checkExternalAttr:   schemaCheckExternal,
-			checkHTTPAttr:       schemaCheckHTTP,
-			checkHTTPTrapAttr:   schemaCheckHTTPTrap,
-			checkICMPPingAttr:   schemaCheckICMPPing,
-			checkJMXAttr:        schemaCheckJMX,
-			checkMemcachedAttr:  schemaCheckMemcached,
-			checkMySQLAttr:      schemaCheckMySQL,
-			checkNTPAttr:        schemaCheckNTP,
-			checkJSONAttr:       schemaCheckJSON,
-			checkPostgreSQLAttr: schemaCheckPostgreSQL,
-			checkPromTextAttr:   schemaCheckPromText,
-			checkRedisAttr:      schemaCheckRedis,
-			checkSMTPAttr:       schemaCheckSMTP,
-			checkSNMPAttr:       schemaCheckSNMP,
-			checkSSH2Attr:       schemaCheckSSH2,
-			checkStatsdAttr:     schemaCheckStatsd,
-			checkTCPAttr:        schemaCheckTCP,
-		}),
+		Schema: convertToHelperSchema(checkDescriptions, checkSchema),
 	}
+
+	// Versions 1 and 2 only add attributes and turn metric_filter from a list
+	// into a set, so the current schema can decode the older states.
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: checkStateUpgradeV0,
+		},
+		{
+			Version: 1,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: checkStateUpgradeV1,
+		},
+	}
+
+	return r
 }
 
 func checkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheck, "")
 	c := newCheck()
 	if err := c.ParseConfig(d); err != nil {
-		return diag.FromErr(err)
+		return configDiag(err)
 	}
 
-	if err := c.Create(ctxt); err != nil {
-		return diag.FromErr(err)
+	if err := c.resolveCollectors(ctx, ctxt, d); err != nil {
+		return configDiag(err)
+	}
+
+	if err := c.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(err, "", schema.TimeoutCreate)
 	}
 
 	d.SetId(c.CID)
 
+	if err := syncCheckCertExpiryRuleSets(ctx, ctxt, d, &c); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get(checkWaitForFirstRunAttr).(bool) {
+		if err := waitForCheckFirstRun(ctx, ctxt, &c); err != nil {
+			return timeoutDiag(err, c.CID, schema.TimeoutCreate)
+		}
+	}
+
 	return checkRead(ctx, d, meta)
 }
 
@@ -435,23 +413,21 @@ func checkCreate(ctx context.Context, d *schema.ResourceData, meta interface{})
 // appropriate place in the statefile.
 func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
-	var diags diag.Diagnostics
 
 	cid := d.Id()
+	ctx = ctxt.logContext(ctx, resourceTypeCheck, cid)
 	var c circonusCheck
-	c, err := loadCheck(ctxt, api.CIDType(&cid))
+	c, err := loadCheck(ctx, ctxt, api.CIDType(&cid))
 	if err != nil {
-		return diag.FromErr(err)
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Check Bundle", cid)
+		}
+
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 
 	if c.CID == "" {
-		d.SetId("")
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Warning,
-			Summary:  "Check Bundle does not exist",
-			Detail:   fmt.Sprintf("Check Bundle (%q) was not found.", cid),
-		})
-		return diags
+		return notFoundDiag(d, "Check Bundle", cid)
 	}
 
 	d.SetId(c.CID)
@@ -469,38 +445,47 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		checkID = c.Checks[0]
 	}
 
+	// The check types' state functions drop the submission URL from the config,
+	// so it is loaded before they run.
+	submissionURL, err := loadCheckSubmissionURL(ctx, ctxt, &c)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to build the check's submission URL", map[string]interface{}{logFieldError: err.Error()})
+	}
+
+	configured, _ := d.Get(checkMetricAttr).([]interface{})
+	configuredMetrics := configuredMetrics(configured)
+
+	metricPathSeparator := checkMetricPathSeparator(&c, d)
 	metrics := make([]interface{}, 0)
 	for _, m := range c.Metrics {
-		metricAttrs := map[string]interface{}{
-			string(metricActiveAttr): metricAPIStatusToBool(m.Status),
-			string(metricNameAttr):   m.Name,
-			string(metricTypeAttr):   m.Type,
+		if checkCertExpiryMetricImplicit(d, m) || checkHTTPStepMetricImplicit(&c, d, m) {
+			continue
+		}
+
+		if metricPathSeparator != "" {
+			m.Name = metricPathToState(m.Name, metricPathSeparator)
 		}
 
-		metrics = append(metrics, metricAttrs)
+		metrics = append(metrics, metricToState(m, configuredMetrics))
 	}
 
-	metricFilters := make([]interface{}, 0)
-	for _, m := range c.MetricFilters {
-		metricFilterAttrs := map[string]interface{}{
-			"type":  m[0],
-			"regex": m[1],
-		}
-		if m[2] == "tags" {
-			metricFilterAttrs["tag_query"] = m[3]
-			metricFilterAttrs["comment"] = m[4]
-		} else {
-			metricFilterAttrs["tag_query"] = ""
-			metricFilterAttrs["comment"] = m[2]
-		}
+	// The status is informational, failing to load it leaves the previous
+	// check_status in place rather than failing the refresh.
+	var diags diag.Diagnostics
+	statuses, statusErr := loadCheckStatuses(ctx, ctxt, &c)
+	if statusErr != nil {
+		diags = checkStatusDiags(c.CID, statusErr)
+	}
 
-		metricFilters = append(metricFilters, metricFilterAttrs)
+	metricFilters, err := checkMetricFiltersToState(&c, d)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Write the global circonus_check parameters followed by the check
 	// type-specific parameters.
 
-	if err := d.Set(checkActiveAttr, checkAPIStatusToBool(c.Status)); err != nil {
+	if err := d.Set(checkActiveAttr, checkAPIStatusToBool(ctx, c.Status)); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -554,8 +539,18 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.FromErr(err)
 	}
 
+	// wait_for_first_run and migrate aren't stored in the API, they are kept
+	// as configured.
+	if err := d.Set(checkWaitForFirstRunAttr, d.Get(checkWaitForFirstRunAttr).(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkMigrateAttr, d.Get(checkMigrateAttr).(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Last step: parse a check_bundle's config into the statefile.
-	if err := parseCheckTypeConfig(&c, d); err != nil {
+	if err := parseCheckTypeConfig(ctx, &c, d); err != nil {
 		return diag.FromErr(err) // fmt.Errorf("Unable to parse check config: %w", err)
 	}
 
@@ -594,29 +589,179 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.FromErr(err) // fmt.Errorf("Unable to store check %q attribute: %w", checkOutReverseConnectURLsAttr, err)
 	}
 
-	return nil
+	if statusErr == nil {
+		if err := d.Set(checkOutStatusAttr, checkStatusesToState(statuses)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set(checkOutSubmissionURLAttr, submissionURL); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func checkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheck, d.Id())
 	c := newCheck()
 	if err := c.ParseConfig(d); err != nil {
+		return configDiag(err)
+	}
+
+	if err := c.resolveCollectors(ctx, ctxt, d); err != nil {
+		return configDiag(err)
+	}
+
+	// A type change only gets this far when the check is migrated, the
+	// check bundle is replaced by a new one instead of being updated.
+	replace := d.HasChange(checkTypeAttr)
+
+	// A migration an earlier apply didn't complete is resumed along with the
+	// checks this change migrates.
+	migration, err := checkPendingMigration(d)
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	c.CID = d.Id()
-	if err := c.Update(ctxt); err != nil {
-		return diag.FromErr(err) // fmt.Errorf("unable to update check %q: %w", d.Id(), err)
+	if d.Get(checkMigrateAttr).(bool) {
+		var kept []string
+		if !replace {
+			kept = c.Brokers
+		}
+
+		migration.add(checkMigrationSources(d, kept))
+	}
+
+	if len(migration.checks) > 0 {
+		skip, err := checkMigrationSkippedRuleSets(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := migration.Load(ctx, ctxt, skip); err != nil {
+			return timeoutDiag(err, d.Id(), schema.TimeoutUpdate)
+		}
 	}
 
-	return checkRead(ctx, d, meta)
+	if replace {
+		if err := c.Create(ctx, ctxt); err != nil {
+			return timeoutDiag(err, "", schema.TimeoutUpdate)
+		}
+
+		migration.bundles = append(migration.bundles, d.Id())
+		d.SetId(c.CID)
+	} else {
+		c.CID = d.Id()
+		if err := c.Update(ctx, ctxt); err != nil {
+			return timeoutDiag(err, d.Id(), schema.TimeoutUpdate) // fmt.Errorf("unable to update check %q: %w", d.Id(), err)
+		}
+	}
+
+	// The migration is kept in the state until it completes, so that the
+	// next apply resumes it if it fails.
+	if err := d.Set(checkOutPendingMigrationAttr, migration.pendingToState()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	if len(migration.checks) > 0 {
+		err := checkApplyMigration(ctx, ctxt, d, &c, migration)
+		if serr := d.Set(checkOutPendingMigrationAttr, migration.pendingToState()); serr != nil {
+			return diag.FromErr(serr)
+		}
+		if err != nil {
+			return timeoutDiag(fmt.Errorf("%w; the migration is resumed by the next apply", err), c.CID, schema.TimeoutUpdate)
+		}
+
+		diags = checkMigrationDiags(d.Id(), migration)
+		migration.complete()
+		if err := d.Set(checkOutPendingMigrationAttr, migration.pendingToState()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := syncCheckCertExpiryRuleSets(ctx, ctxt, d, &c); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = migration.deleteBundles(ctx, ctxt)
+	if serr := d.Set(checkOutPendingMigrationAttr, migration.pendingToState()); serr != nil {
+		return diag.FromErr(serr)
+	}
+	if err != nil {
+		return timeoutDiag(err, d.Id(), schema.TimeoutUpdate)
+	}
+
+	return append(diags, checkRead(ctx, d, meta)...)
+}
+
+// checkApplyMigration migrates the dependents of the checks replaced by the
+// update of c, and records what was done in migration_plan.
+func checkApplyMigration(ctx context.Context, ctxt *providerContext, d *schema.ResourceData, c *circonusCheck, migration *checkMigration) error {
+	// The check UUIDs and collectors of the new checks are only known once
+	// the bundle is reloaded.
+	updated, err := loadCheck(ctx, ctxt, api.CIDType(&c.CID))
+	if err != nil {
+		return err
+	}
+
+	targets := migration.Targets(&updated)
+	metrics := checkMigrationMetrics(c)
+	if err := migration.Apply(ctx, ctxt, targets, metrics); err != nil {
+		return err
+	}
+
+	return d.Set(checkOutMigrationPlanAttr, checkMigrationPlanToState(migration.Plan(targets, metrics)))
+}
+
+// checkMigrationDiags warns that the rule sets copied by a migration include
+// those managed by circonus_rule_set resources, which the provider can't tell
+// apart.
+func checkMigrationDiags(cid string, migration *checkMigration) diag.Diagnostics {
+	if len(migration.copies) == 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Rule sets copied by the migration",
+			Detail:   fmt.Sprintf("The migration of check %q copied rule sets to its new checks, listed in %s. Rule sets managed by a circonus_rule_set resource are moved to the new checks by their configuration, their copies duplicate them and should be deleted.", cid, checkOutMigrationPlanAttr),
+		},
+	}
 }
 
 func checkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheck, d.Id())
+
+	ruleSets, err := interfaceList(d.Get(checkOutCertExpiryRuleSetsAttr).([]interface{})).List()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := deleteCheckCertExpiryRuleSets(ctx, ctxt, ruleSets); err != nil {
+		return timeoutDiag(err, d.Id(), schema.TimeoutDelete)
+	}
 
-	if _, err := ctxt.client.Delete(d.Id()); err != nil {
-		return diag.FromErr(err) // fmt.Errorf("unable to delete check %q: %w", d.Id(), err)
+	err = ctxt.apiRequest(ctx, http.MethodDelete, d.Id(), func(client *api.API) error {
+		_, err := client.Delete(d.Id())
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(err, d.Id(), schema.TimeoutDelete) // fmt.Errorf("unable to delete check %q: %w", d.Id(), err)
+	}
+
+	// Check bundles replaced by a migration that didn't complete go with it.
+	pending, err := checkPendingMigration(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := pending.deleteBundles(ctx, ctxt); err != nil {
+		return timeoutDiag(err, d.Id(), schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -624,9 +769,205 @@ func checkDelete(ctx context.Context, d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// checkCustomizeDiff runs the check's validation rules at plan time so that
+// invalid configurations are reported by `terraform plan` instead of part way
+// through an apply.  Rules whose inputs aren't known until apply are skipped;
+// ParseConfig runs them again before the check is created or updated.
+func checkCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	known := checkPlanKnown(d)
+
+	if d.Id() != "" && checkCertExpiryRuleSetsChange(d) {
+		if err := d.SetNewComputed(checkOutCertExpiryRuleSetsAttr); err != nil {
+			return err
+		}
+	}
+
+	if err := checkCustomizeDiffCollectors(ctx, d, meta, known); err != nil {
+		return planError(err)
+	}
+
+	checkType, ok, err := checkPlanType(d, known)
+	if err != nil || !ok {
+		return planError(err)
+	}
+
+	// Push check types only run when data is submitted to them, so there is
+	// no first run to wait for.
+	if d.Get(checkWaitForFirstRunAttr).(bool) && checkTypesByAttr[schemaAttr(checkType)].submissionURL != nil {
+		return planError(attrErrorf(attrPath(checkWaitForFirstRunAttr), "%s can not be used with %s checks, which only run when data is submitted to them", checkWaitForFirstRunAttr, checkType))
+	}
+
+	inputs := []string{checkMetricAttr, checkMetricFilterAttr, checkPeriodAttr, checkTimeoutAttr, checkType}
+	inputs = append(inputs, checkTypesByAttr[schemaAttr(checkType)].planInputs...)
+
+	if !known(inputs...) {
+		tflog.Debug(ctx, "Skipping plan time check validation, configuration contains unknown values")
+		return checkCustomizeDiffMigration(ctx, d, meta, checkTypesByAttr[schemaAttr(checkType)].apiType, nil)
+	}
+
+	c := newCheck()
+	if err := c.ParseConfig(d); err != nil {
+		return planError(err)
+	}
+
+	return checkCustomizeDiffMigration(ctx, d, meta, apiCheckType(c.Type), &c)
+}
+
+// checkCustomizeDiffMigration plans a change of the check's type, which
+// replaces its check bundle, and the migration of the checks it replaces,
+// listing the dependents it affects in migration_plan.  newType is empty when
+// the planned type isn't known, and c is nil when the check couldn't be
+// parsed.
+func checkCustomizeDiffMigration(ctx context.Context, d *schema.ResourceDiff, meta interface{}, newType apiCheckType, c *circonusCheck) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	// A migration an earlier apply didn't complete is resumed by the next
+	// one, even without changes to the check.
+	pending, err := checkPendingMigration(d)
+	if err != nil {
+		return err
+	}
+	if len(pending.checks) > 0 || len(pending.bundles) > 0 {
+		for _, attr := range []string{checkOutMigrationPlanAttr, checkOutPendingMigrationAttr} {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	o, n := d.GetChange(checkTypeAttr)
+	oldType, _ := o.(string)
+	if d.HasChange(checkTypeAttr) {
+		newType = apiCheckType(n.(string))
+	}
+
+	replace := oldType != "" && newType != "" && oldType != string(newType)
+	if replace && !d.HasChange(checkTypeAttr) {
+		if err := d.SetNew(checkTypeAttr, string(newType)); err != nil {
+			return err
+		}
+	}
+
+	migrate := d.Get(checkMigrateAttr).(bool)
+	if replace && !migrate {
+		return d.ForceNew(checkTypeAttr)
+	}
+
+	if !migrate || (!replace && !d.NewValueKnown(checkCollectorAttr)) {
+		return nil
+	}
+
+	var kept []string
+	if !replace {
+		kept = checkCollectorIDs(d.Get(checkCollectorAttr))
+	}
+
+	checks, _, _ := checkMigrationSources(d, kept)
+	if len(checks) == 0 {
+		return nil
+	}
+
+	if replace {
+		for _, attr := range []string{checkOutByCollectorAttr, checkOutCertExpiryRuleSetsAttr, checkOutChecksAttr, checkOutCheckUUIDsAttr, checkOutCreatedAttr, checkOutIDAttr, checkOutLastModifiedAttr, checkOutLastModifiedByAttr, checkOutStatusAttr} {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.SetNewComputed(checkOutPendingMigrationAttr); err != nil {
+		return err
+	}
+
+	// Which dependents are migrated depends on the metrics and collectors of
+	// the planned check, so the plan is only listed when they are known.  A
+	// migration resumed from an earlier apply is listed once it is applied.
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt.client == nil || c == nil || !d.NewValueKnown(checkCollectorAttr) || len(pending.checks) > 0 || len(pending.bundles) > 0 {
+		return d.SetNewComputed(checkOutMigrationPlanAttr)
+	}
+
+	skip, err := checkMigrationSkippedRuleSets(d)
+	if err != nil {
+		return err
+	}
+
+	// Nothing is pending, the migration only holds the checks this change
+	// replaces.
+	migration := pending
+	migration.add(checkMigrationSources(d, kept))
+	if err := migration.Load(ctx, ctxt, skip); err != nil {
+		return fmt.Errorf("unable to plan the migration of check %s: %w", d.Id(), err)
+	}
+
+	targets := migration.PlannedTargets(checkCollectorIDs(d.Get(checkCollectorAttr)))
+	plan := migration.Plan(targets, checkMigrationMetrics(c))
+
+	return d.SetNew(checkOutMigrationPlanAttr, checkMigrationPlanToState(plan))
+}
+
+// checkPlanKnown returns a function reporting whether the configured values
+// of attrs are known at plan time.
+func checkPlanKnown(d *schema.ResourceDiff) func(attrs ...string) bool {
+	rawConfig := d.GetRawConfig()
+
+	return func(attrs ...string) bool {
+		// Without a raw config, such as when the diff isn't coming from
+		// Terraform, every value read through d is treated as known.
+		if rawConfig.IsNull() {
+			return true
+		}
+
+		if !rawConfig.IsKnown() {
+			return false
+		}
+
+		for _, attr := range attrs {
+			if !rawConfig.GetAttr(attr).IsWhollyKnown() {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// checkPlanType returns the check type block configured in d.  It fails when
+// more or fewer than one is configured, and returns false without an error
+// when the check type isn't known yet.
+func checkPlanType(d checkConfigReader, known func(attrs ...string) bool) (string, bool, error) {
+	checkTypes := make([]string, 0, 1)
+	for _, checkType := range checkTypeAttrs() {
+		if _, found := d.GetOk(checkType); found {
+			checkTypes = append(checkTypes, checkType)
+		}
+	}
+
+	switch {
+	case len(checkTypes) > 1:
+		return "", false, attrErrorf(attrPath(checkTypes[1]), "only one check type may be configured, found: %s", strings.Join(checkTypes, ", "))
+	case len(checkTypes) == 0:
+		if !known(checkTypeAttrs()...) {
+			return "", false, nil
+		}
+
+		return "", false, attrErrorf(attrPath(), "one check type must be configured, one of: %s", strings.Join(checkTypeAttrs(), ", "))
+	}
+
+	return checkTypes[0], true, nil
+}
+
+// checkConfigReader is satisfied by both *schema.ResourceData and
+// *schema.ResourceDiff so a check can be parsed at both apply and plan time.
+type checkConfigReader interface {
+	GetOk(key string) (interface{}, bool)
+}
+
 // ParseConfig reads Terraform config data and stores the information into a
 // Circonus CheckBundle object.
-func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
+func (c *circonusCheck) ParseConfig(d checkConfigReader) error {
 	if v, found := d.GetOk(checkActiveAttr); found {
 		c.Status = checkActiveToAPIStatus(v.(bool))
 	}
@@ -686,7 +1027,7 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 
 			m := newMetric()
 			if err := m.ParseConfigMap(id, metricAttrs); err != nil {
-				return fmt.Errorf("unable to parse config: %w", err)
+				return attrErrorf(attrPath(checkMetricAttr), "%v", err)
 			}
 
 			c.Metrics = append(c.Metrics, m.CheckBundleMetric)
@@ -696,28 +1037,8 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 	}
 
 	if v, found := d.GetOk(checkMetricFilterAttr); found {
-		metricFilterList := v.([]interface{})
-		c.MetricFilters = make([][]string, 0, len(metricFilterList))
-
-		for _, metricFilterListRaw := range metricFilterList {
-			metricFilterAttrs := metricFilterListRaw.(map[string]interface{})
-
-			m := make([]string, 0, 3)
-			if av, found := metricFilterAttrs["type"]; found {
-				m = append(m, av.(string))
-			}
-			if av, found := metricFilterAttrs["regex"]; found {
-				m = append(m, av.(string))
-			}
-			if av, found := metricFilterAttrs["tag_query"]; found {
-				m = append(m, "tags")
-				m = append(m, av.(string))
-			}
-
-			if av, found := metricFilterAttrs["comment"]; found {
-				m = append(m, av.(string))
-			}
-			c.MetricFilters = append(c.MetricFilters, m)
+		if err := checkMetricFiltersToAPI(c, v.(*schema.Set).List()); err != nil {
+			return err
 		}
 	}
 
@@ -744,6 +1065,12 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		return fmt.Errorf("unable to parse check type: %w", err)
 	}
 
+	if sep := checkMetricPathSeparator(c, d); sep != "" {
+		for i := range c.Metrics {
+			c.Metrics[i].Name = metricPathToAPI(c.Metrics[i].Name, sep)
+		}
+	}
+
 	if err := c.Fixup(); err != nil {
 		return err
 	}
@@ -757,44 +1084,20 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 
 // checkConfigToAPI parses the Terraform config into the respective per-check
 // type api.Config attributes.
-func checkConfigToAPI(c *circonusCheck, d *schema.ResourceData) error {
-	checkTypeParseMap := map[string]func(*circonusCheck, interfaceList) error{
-		checkCAQLAttr:       checkConfigToAPICAQL,
-		checkCloudWatchAttr: checkConfigToAPICloudWatch,
-		checkConsulAttr:     checkConfigToAPIConsul,
-		checkDNSAttr:        checkConfigToAPIDNS,
-		checkExternalAttr:   checkConfigToAPIExternal,
-		checkHTTPAttr:       checkConfigToAPIHTTP,
-		checkHTTPTrapAttr:   checkConfigToAPIHTTPTrap,
-		checkICMPPingAttr:   checkConfigToAPIICMPPing,
-		checkJMXAttr:        checkConfigToAPIJMX,
-		checkMemcachedAttr:  checkConfigToAPIMemcached,
-		checkJSONAttr:       checkConfigToAPIJSON,
-		checkMySQLAttr:      checkConfigToAPIMySQL,
-		checkNTPAttr:        checkConfigToAPINTP,
-		checkPostgreSQLAttr: checkConfigToAPIPostgreSQL,
-		checkPromTextAttr:   checkConfigToAPIPromText,
-		checkRedisAttr:      checkConfigToAPIRedis,
-		checkSMTPAttr:       checkConfigToAPISMTP,
-		checkSNMPAttr:       checkConfigToAPISNMP,
-		checkSSH2Attr:       checkConfigToAPISSH2,
-		checkStatsdAttr:     checkConfigToAPIStatsd,
-		checkTCPAttr:        checkConfigToAPITCP,
-	}
-
-	for checkType, fn := range checkTypeParseMap {
-		if listRaw, found := d.GetOk(checkType); found {
+func checkConfigToAPI(c *circonusCheck, d checkConfigReader) error {
+	for _, t := range registeredCheckTypes() {
+		if listRaw, found := d.GetOk(string(t.attr)); found {
 			switch u := listRaw.(type) {
 			case []interface{}:
-				if err := fn(c, u); err != nil {
-					return fmt.Errorf("Unable to parse type %q: %w", checkType, err)
+				if err := t.toAPI(c, u); err != nil {
+					return fmt.Errorf("Unable to parse type %q: %w", t.attr, err)
 				}
 			case *schema.Set:
-				if err := fn(c, u.List()); err != nil {
-					return fmt.Errorf("Unable to parse type %q: %w", checkType, err)
+				if err := t.toAPI(c, u.List()); err != nil {
+					return fmt.Errorf("Unable to parse type %q: %w", t.attr, err)
 				}
 			default:
-				return fmt.Errorf("PROVIDER BUG: unsupported check type interface: %q", checkType)
+				return fmt.Errorf("PROVIDER BUG: unsupported check type interface: %q", t.attr)
 			}
 		}
 	}
@@ -804,40 +1107,56 @@ func checkConfigToAPI(c *circonusCheck, d *schema.ResourceData) error {
 
 // parseCheckTypeConfig parses an API Config object and stores the result in the
 // statefile.
-func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
-	checkTypeConfigHandlers := map[apiCheckType]func(*circonusCheck, *schema.ResourceData) error{
-		apiCheckTypeCAQLAttr:       checkAPIToStateCAQL,
-		apiCheckTypeCloudWatchAttr: checkAPIToStateCloudWatch,
-		apiCheckTypeConsulAttr:     checkAPIToStateConsul,
-		apiCheckTypeDNSAttr:        checkAPIToStateDNS,
-		apiCheckTypeExternalAttr:   checkAPIToStateExternal,
-		apiCheckTypeHTTPAttr:       checkAPIToStateHTTP,
-		apiCheckTypeHTTPTrapAttr:   checkAPIToStateHTTPTrap,
-		apiCheckTypeICMPPingAttr:   checkAPIToStateICMPPing,
-		apiCheckTypeJMXAttr:        checkAPIToStateJMX,
-		apiCheckTypeMemcachedAttr:  checkAPIToStateMemcached,
-		apiCheckTypeJSONAttr:       checkAPIToStateJSON,
-		apiCheckTypeMySQLAttr:      checkAPIToStateMySQL,
-		apiCheckTypeNTPAttr:        checkAPIToStateNTP,
-		apiCheckTypePostgreSQLAttr: checkAPIToStatePostgreSQL,
-		apiCheckTypePromTextAttr:   checkAPIToStatePromText,
-		apiCheckTypeRedisAttr:      checkAPIToStateRedis,
-		apiCheckTypeSMTPAttr:       checkAPIToStateSMTP,
-		apiCheckTypeSNMPAttr:       checkAPIToStateSNMP,
-		apiCheckTypeSSH2Attr:       checkAPIToStateSSH2,
-		apiCheckTypeStatsdAttr:     checkAPIToStateStatsd,
-		apiCheckTypeTCPAttr:        checkAPIToStateTCP,
-	}
-
-	var checkType apiCheckType = apiCheckType(c.Type)
-	fn, ok := checkTypeConfigHandlers[checkType]
-	if !ok {
-		return fmt.Errorf("check type %q not supported", c.Type)
-	}
-
-	if err := fn(c, d); err != nil {
+func parseCheckTypeConfig(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	// A check managed through a raw block stays in the raw block even if the
+	// provider has a dedicated block for its type, and check types without a
+	// dedicated block fall back to a raw block so they can still be imported.
+	fn := checkAPIToStateRaw
+	if t, ok := checkTypeForAPIType(c.Type); ok {
+		fn = t.toState
+	}
+
+	if _, found := d.GetOk(checkRawAttr); found {
+		fn = checkAPIToStateRaw
+	}
+
+	if err := fn(ctx, c, d); err != nil {
 		return fmt.Errorf("unable to parse the API config for %q: %w", c.Type, err)
 	}
 
 	return nil
 }
+
+// schemaMetricFilter returns the schema of a list of metric filters.  It is used
+// by check types that filter metrics when they are collected.
+func schemaMetricFilter() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList, // order matters here so use a List
+		Optional: true,
+		MinItems: 0,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(checkMetricFilterDescriptions, map[schemaAttr]*schema.Schema{
+				checkMetricFilterTypeAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateRegexp(checkMetricFilterTypeAttr, `allow|deny`),
+				},
+				checkMetricFilterRegexAttr: {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateRegexp(metricNameAttr, `.+`),
+				},
+				checkMetricFilterCommentAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(metricNameAttr, `.+`),
+				},
+				checkMetricFilterTagQueryAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(metricNameAttr, `.+`),
+				},
+			}),
+		},
+	}
+}
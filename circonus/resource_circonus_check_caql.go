@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,10 @@ import (
 )
 
 const (
+	// circonus_check.caql check type.
+	checkCAQLAttr                     = "caql"
+	apiCheckTypeCAQLAttr apiCheckType = "caql"
+
 	// circonus_check.caql.* resource attribute names.
 	checkCAQLQueryAttr = "query"
 )
@@ -19,6 +24,17 @@ var checkCAQLDescriptions = attrDescrs{
 	checkCAQLQueryAttr: "The query definition",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeCAQLAttr,
+		attr:        checkCAQLAttr,
+		description: "CAQL check configuration",
+		schema:      schemaCheckCAQL,
+		toAPI:       checkConfigToAPICAQL,
+		toState:     checkAPIToStateCAQL,
+	})
+}
+
 var schemaCheckCAQL = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -38,7 +54,7 @@ var schemaCheckCAQL = &schema.Schema{
 
 // checkAPIToStateCAQL reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateCAQL(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateCAQL(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	caqlConfig := make(map[string]interface{}, len(c.Config))
 
 	caqlConfig[string(checkCAQLQueryAttr)] = c.Config[config.Query]
@@ -71,7 +87,7 @@ func hashCheckCAQL(v interface{}) int {
 }
 
 func checkConfigToAPICAQL(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeCAQL)
+	c.Type = string(apiCheckTypeCAQLAttr)
 	c.Target = defaultCheckCAQLTarget
 
 	// Iterate over all `caql` attributes, even though we have a max of 1 in the
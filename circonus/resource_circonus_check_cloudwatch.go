@@ -2,37 +2,93 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.cloudwatch check type.
+	checkCloudWatchAttr                     = "cloudwatch"
+	apiCheckTypeCloudWatchAttr apiCheckType = "cloudwatch"
+
 	// circonus_check.cloudwatch.* resource attribute names.
-	checkCloudWatchAPIKeyAttr      = "api_key"
-	checkCloudWatchAPISecretAttr   = "api_secret"
+	checkCloudWatchAPIKeyAttr     = "api_key"
+	checkCloudWatchAPISecretAttr  = "api_secret"
+	checkCloudWatchDimensionsAttr = "dimensions"
+	checkCloudWatchExternalIDAttr = "external_id"
+	checkCloudWatchMetricAttr     = "metric"
+	checkCloudWatchNamespaceAttr  = "namespace"
+	checkCloudWatchQueryAttr      = "query"
+	checkCloudWatchRegionAttr     = "region"
+	checkCloudWatchRoleARNAttr    = "role_arn"
+	checkCloudWatchURLAttr        = "url"
+	checkCloudWatchVersionAttr    = "version"
+
+	// checkCloudWatchDimmensionsAttr is the misspelled name used for
+	// dimensions before schema version 1.  It is accepted, with a deprecation
+	// warning, until it is removed in the next major release.
 	checkCloudWatchDimmensionsAttr = "dimmensions"
-	checkCloudWatchMetricAttr      = "metric"
-	checkCloudWatchNamespaceAttr   = "namespace"
-	checkCloudWatchURLAttr         = "url"
-	checkCloudWatchVersionAttr     = "version"
+
+	// Broker config keys for the role assumed and the region queried.
+	apiCloudWatchExternalID = config.Key("external_id")
+	apiCloudWatchRegion     = config.Key("region")
+	apiCloudWatchRoleARN    = config.Key("role_arn")
+
+	// Each query block is stored in the broker config under keys prefixed with
+	// namespace_<n>: namespace_<n> holds the namespace, namespace_<n>_metrics
+	// the comma separated metrics and namespace_<n>_dim_<name> the dimensions.
+	apiCloudWatchQueryPrefix        = "namespace_"
+	apiCloudWatchQueryMetricsSuffix = "_metrics"
+	apiCloudWatchQueryDimSuffix     = "_dim_"
+
+	// checkCloudWatchRegionURLFmt is the CloudWatch endpoint of a region.
+	checkCloudWatchRegionURLFmt = "https://monitoring.%s.amazonaws.com"
 )
 
+var checkCloudWatchQueryKeyRegexp = regexp.MustCompile(`^namespace_(\d+)(?:(_metrics)|_dim_(.+))?$`)
+
+var checkCloudWatchQueryDescriptions = attrDescrs{
+	checkCloudWatchDimensionsAttr: "The dimensions to query for the metrics",
+	checkCloudWatchMetricAttr:     "One or more CloudWatch Metric attributes",
+	checkCloudWatchNamespaceAttr:  "The namespace to pull telemetry from",
+}
+
 var checkCloudWatchDescriptions = attrDescrs{
 	checkCloudWatchAPIKeyAttr:      "The AWS API Key",
 	checkCloudWatchAPISecretAttr:   "The AWS API Secret",
-	checkCloudWatchDimmensionsAttr: "The dimensions to query for the metric",
+	checkCloudWatchDimensionsAttr:  "The dimensions to query for the metric",
+	checkCloudWatchDimmensionsAttr: "Deprecated spelling of dimensions",
+	checkCloudWatchExternalIDAttr:  "The external ID required to assume role_arn",
 	checkCloudWatchMetricAttr:      "One or more CloudWatch Metric attributes",
 	checkCloudWatchNamespaceAttr:   "The namespace to pull telemetry from",
+	checkCloudWatchQueryAttr:       "An additional namespace, dimensions and metrics to query",
+	checkCloudWatchRegionAttr:      "The AWS region to query, used instead of url",
+	checkCloudWatchRoleARNAttr:     "The ARN of an IAM role to assume, for example in another AWS account",
 	checkCloudWatchURLAttr:         "The URL including schema and hostname for the Cloudwatch monitoring server. This value will be used to specify the region - for example, to pull from us-east-1, the URL would be https://monitoring.us-east-1.amazonaws.com.",
 	checkCloudWatchVersionAttr:     "The version of the Cloudwatch API to use.",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeCloudWatchAttr,
+		attr:        checkCloudWatchAttr,
+		description: "CloudWatch check configuration",
+		schema:      schemaCheckCloudWatch,
+		toAPI:       checkConfigToAPICloudWatch,
+		toState:     checkAPIToStateCloudWatch,
+		validate:    validateCheckCloudWatch,
+	})
+}
+
 var schemaCheckCloudWatch = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -43,27 +99,40 @@ var schemaCheckCloudWatch = &schema.Schema{
 		Schema: convertToHelperSchema(checkCloudWatchDescriptions, map[schemaAttr]*schema.Schema{
 			checkCloudWatchAPIKeyAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkCloudWatchAPIKeyAttr, `[\S]+`),
 				DefaultFunc:  schema.EnvDefaultFunc("AWS_ACCESS_KEY_ID", ""),
 			},
 			checkCloudWatchAPISecretAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkCloudWatchAPISecretAttr, `[\S]+`),
 				DefaultFunc:  schema.EnvDefaultFunc("AWS_SECRET_ACCESS_KEY", ""),
 			},
+			checkCloudWatchDimensionsAttr: {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         schema.TypeString,
+				ValidateFunc: validateCheckCloudWatchDimensions,
+			},
 			checkCloudWatchDimmensionsAttr: {
 				Type:         schema.TypeMap,
-				Required:     true,
+				Optional:     true,
 				Elem:         schema.TypeString,
-				ValidateFunc: validateCheckCloudWatchDimmensions,
+				ValidateFunc: validateCheckCloudWatchDimensions,
+				Deprecated:   fmt.Sprintf("use %s instead", checkCloudWatchDimensionsAttr),
+			},
+			checkCloudWatchExternalIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkCloudWatchExternalIDAttr, `^[\w+=,.@:/-]{2,}$`),
 			},
 			checkCloudWatchMetricAttr: {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
 				MinItems: 1,
 				Set:      schema.HashString,
 				Elem: &schema.Schema{
@@ -73,12 +142,51 @@ var schemaCheckCloudWatch = &schema.Schema{
 			},
 			checkCloudWatchNamespaceAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validateRegexp(checkCloudWatchNamespaceAttr, `.+`),
 			},
+			checkCloudWatchQueryAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkCloudWatchQueryDescriptions, map[schemaAttr]*schema.Schema{
+						checkCloudWatchDimensionsAttr: {
+							Type:         schema.TypeMap,
+							Required:     true,
+							Elem:         schema.TypeString,
+							ValidateFunc: validateCheckCloudWatchDimensions,
+						},
+						checkCloudWatchMetricAttr: {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateRegexp(checkCloudWatchMetricAttr, `^([\S]+)$`),
+							},
+						},
+						checkCloudWatchNamespaceAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkCloudWatchNamespaceAttr, `.+`),
+						},
+					}),
+				},
+			},
+			checkCloudWatchRegionAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkCloudWatchRegionAttr, `^[a-z]{2}(?:-gov|-iso[a-z]*)?-[a-z]+-\d+$`),
+			},
+			checkCloudWatchRoleARNAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkCloudWatchRoleARNAttr, `^arn:aws[\w-]*:iam::\d{12}:role/.+$`),
+			},
 			checkCloudWatchURLAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validateHTTPURL(checkCloudWatchURLAttr, urlIsAbs),
 			},
 			checkCloudWatchVersionAttr: {
@@ -93,7 +201,7 @@ var schemaCheckCloudWatch = &schema.Schema{
 
 // checkAPIToStateCloudWatch reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateCloudWatch(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	cloudwatchConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -113,30 +221,61 @@ func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.APIKey, checkCloudWatchAPIKeyAttr)
 	saveStringConfigToState(config.APISecret, checkCloudWatchAPISecretAttr)
 
-	dimmensions := make(map[string]interface{}, len(c.Config))
-	dimmensionPrefixLen := len(config.DimPrefix)
+	saveStringConfigToState(apiCloudWatchExternalID, checkCloudWatchExternalIDAttr)
+
+	dimensions := make(map[string]interface{}, len(c.Config))
+	dimensionPrefixLen := len(config.DimPrefix)
 	for k, v := range c.Config {
-		if len(k) <= dimmensionPrefixLen {
+		if len(k) <= dimensionPrefixLen {
 			continue
 		}
 
-		if strings.Compare(string(k[:dimmensionPrefixLen]), string(config.DimPrefix)) == 0 {
-			key := k[dimmensionPrefixLen:]
-			dimmensions[string(key)] = v
+		if strings.Compare(string(k[:dimensionPrefixLen]), string(config.DimPrefix)) == 0 {
+			key := k[dimensionPrefixLen:]
+			dimensions[string(key)] = v
 		}
 		delete(swamp, k)
 	}
-	cloudwatchConfig[string(checkCloudWatchDimmensionsAttr)] = dimmensions
+
+	// Keep the dimensions under whichever name is already in use so that
+	// configurations still using the deprecated name don't show a diff.
+	dimensionsAttr := checkCloudWatchDimensionsAttr
+	if cloudwatchUsesDeprecatedDimensions(d) {
+		dimensionsAttr = checkCloudWatchDimmensionsAttr
+	}
+	cloudwatchConfig[string(dimensionsAttr)] = dimensions
 
 	metricSet := schema.NewSet(schema.HashString, nil)
-	metricList := strings.Split(c.Config[config.CloudwatchMetrics], ",")
-	for _, m := range metricList {
-		metricSet.Add(m)
+	if metrics, ok := c.Config[config.CloudwatchMetrics]; ok && metrics != "" {
+		for _, m := range strings.Split(metrics, ",") {
+			metricSet.Add(m)
+		}
 	}
 	cloudwatchConfig[string(checkCloudWatchMetricAttr)] = metricSet
+	delete(swamp, config.CloudwatchMetrics)
 
 	saveStringConfigToState(config.Namespace, checkCloudWatchNamespaceAttr)
-	saveStringConfigToState(config.URL, checkCloudWatchURLAttr)
+
+	queries, err := checkCloudWatchQueriesToState(c.Config)
+	if err != nil {
+		return err
+	}
+	cloudwatchConfig[string(checkCloudWatchQueryAttr)] = queries
+	for k := range swamp {
+		if checkCloudWatchQueryKeyRegexp.MatchString(string(k)) {
+			delete(swamp, k)
+		}
+	}
+
+	// The URL is derived from the region when one is set.
+	if _, ok := c.Config[apiCloudWatchRegion]; ok {
+		saveStringConfigToState(apiCloudWatchRegion, checkCloudWatchRegionAttr)
+		delete(swamp, config.URL)
+	} else {
+		saveStringConfigToState(config.URL, checkCloudWatchURLAttr)
+	}
+
+	saveStringConfigToState(apiCloudWatchRoleARN, checkCloudWatchRoleARNAttr)
 	saveStringConfigToState(config.Version, checkCloudWatchVersionAttr)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
@@ -150,7 +289,7 @@ func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -178,20 +317,25 @@ func hashCheckCloudWatch(v interface{}) int {
 	writeString(checkCloudWatchAPIKeyAttr)
 	writeString(checkCloudWatchAPISecretAttr)
 
-	if dimmensionsRaw, ok := m[string(checkCloudWatchDimmensionsAttr)]; ok {
-		dimmensionMap := dimmensionsRaw.(map[string]interface{})
-		dimmensions := make([]string, 0, len(dimmensionMap))
-		for k := range dimmensionMap {
-			dimmensions = append(dimmensions, k)
+	// Both spellings of dimensions hash identically so that renaming the
+	// attribute doesn't replace the set element.
+	dimensions := make([]string, 0)
+	for _, attrName := range []schemaAttr{checkCloudWatchDimensionsAttr, checkCloudWatchDimmensionsAttr} {
+		if dimensionsRaw, ok := m[string(attrName)]; ok && dimensionsRaw != nil {
+			for k := range dimensionsRaw.(map[string]interface{}) {
+				dimensions = append(dimensions, k)
+			}
 		}
+	}
 
-		sort.Strings(dimmensions)
-		for i := range dimmensions {
-			fmt.Fprint(b, dimmensions[i])
-		}
+	sort.Strings(dimensions)
+	for i := range dimensions {
+		fmt.Fprint(b, dimensions[i])
 	}
 
-	if metricsRaw, ok := m[string(checkCloudWatchMetricAttr)]; ok {
+	writeString(checkCloudWatchExternalIDAttr)
+
+	if metricsRaw, ok := m[string(checkCloudWatchMetricAttr)]; ok && metricsRaw != nil {
 		metricListRaw := flattenSet(metricsRaw.(*schema.Set))
 		for i := range metricListRaw {
 			if metricListRaw[i] == nil {
@@ -202,6 +346,15 @@ func hashCheckCloudWatch(v interface{}) int {
 	}
 
 	writeString(checkCloudWatchNamespaceAttr)
+
+	if queriesRaw, ok := m[string(checkCloudWatchQueryAttr)]; ok && queriesRaw != nil {
+		for _, queryRaw := range queriesRaw.([]interface{}) {
+			hashCheckCloudWatchQuery(b, newInterfaceMap(queryRaw))
+		}
+	}
+
+	writeString(checkCloudWatchRegionAttr)
+	writeString(checkCloudWatchRoleARNAttr)
 	writeString(checkCloudWatchURLAttr)
 	writeString(checkCloudWatchVersionAttr)
 
@@ -209,43 +362,196 @@ func hashCheckCloudWatch(v interface{}) int {
 	return hashcode.String(s)
 }
 
-func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nolint:unparam
+// hashCheckCloudWatchQuery writes a single query block to the hash buffer.
+func hashCheckCloudWatchQuery(b *bytes.Buffer, q interfaceMap) {
+	dimensions := q.CollectMap(checkCloudWatchDimensionsAttr)
+	dimKeys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		dimKeys = append(dimKeys, k)
+	}
+	sort.Strings(dimKeys)
+	for _, k := range dimKeys {
+		fmt.Fprint(b, k, "=", dimensions[k])
+	}
+
+	if metricsRaw, ok := q[checkCloudWatchMetricAttr]; ok && metricsRaw != nil {
+		metrics := metricsRaw.(*schema.Set).List()
+		metricList := make([]string, 0, len(metrics))
+		for _, m := range metrics {
+			metricList = append(metricList, m.(string))
+		}
+		sort.Strings(metricList)
+		fmt.Fprint(b, strings.Join(metricList, ","))
+	}
+
+	if v, ok := q[checkCloudWatchNamespaceAttr]; ok && v != nil {
+		fmt.Fprint(b, strings.TrimSpace(v.(string)))
+	}
+}
+
+// checkCloudWatchQueriesToState rebuilds the query blocks from the indexed
+// namespace_<n> keys in the broker config, ordered by index.
+func checkCloudWatchQueriesToState(cfg map[config.Key]string) ([]interface{}, error) {
+	queriesByIndex := make(map[int]map[string]interface{})
+	query := func(idx int) map[string]interface{} {
+		q, ok := queriesByIndex[idx]
+		if !ok {
+			q = map[string]interface{}{
+				string(checkCloudWatchDimensionsAttr): make(map[string]interface{}),
+				string(checkCloudWatchMetricAttr):     schema.NewSet(schema.HashString, nil),
+			}
+			queriesByIndex[idx] = q
+		}
+		return q
+	}
+
+	for k, v := range cfg {
+		matches := checkCloudWatchQueryKeyRegexp.FindStringSubmatch(string(k))
+		if matches == nil {
+			continue
+		}
+
+		idx, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s config key %q: %w", checkCloudWatchQueryAttr, k, err)
+		}
+
+		q := query(idx)
+		switch {
+		case matches[2] != "":
+			for _, m := range strings.Split(v, ",") {
+				if m != "" {
+					q[string(checkCloudWatchMetricAttr)].(*schema.Set).Add(m)
+				}
+			}
+		case matches[3] != "":
+			q[string(checkCloudWatchDimensionsAttr)].(map[string]interface{})[matches[3]] = v
+		default:
+			q[string(checkCloudWatchNamespaceAttr)] = v
+		}
+	}
+
+	indexes := make([]int, 0, len(queriesByIndex))
+	for idx := range queriesByIndex {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	queries := make([]interface{}, 0, len(indexes))
+	for _, idx := range indexes {
+		queries = append(queries, queriesByIndex[idx])
+	}
+
+	return queries, nil
+}
+
+// cloudwatchUsesDeprecatedDimensions reports whether the cloudwatch block in
+// the state stores its dimensions under the pre-version 1 attribute name.
+func cloudwatchUsesDeprecatedDimensions(d *schema.ResourceData) bool {
+	v, ok := d.GetOk(checkCloudWatchAttr)
+	if !ok {
+		return false
+	}
+
+	for _, mapRaw := range v.(*schema.Set).List() {
+		if len(newInterfaceMap(mapRaw).CollectMap(checkCloudWatchDimmensionsAttr)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeCloudWatchAttr)
 
 	// Iterate over all `cloudwatch` attributes, even though we have a max of 1 in the
 	// schema.
-	for _, mapRaw := range l {
+	for i, mapRaw := range l {
 		cloudwatchConfig := newInterfaceMap(mapRaw)
 
-		if v, found := cloudwatchConfig[checkCloudWatchAPIKeyAttr]; found {
-			c.Config[config.APIKey] = v.(string)
+		apiKey, _ := cloudwatchConfig[checkCloudWatchAPIKeyAttr].(string)
+		apiSecret, _ := cloudwatchConfig[checkCloudWatchAPISecretAttr].(string)
+		roleARN, _ := cloudwatchConfig[checkCloudWatchRoleARNAttr].(string)
+		externalID, _ := cloudwatchConfig[checkCloudWatchExternalIDAttr].(string)
+		switch {
+		case (apiKey == "") != (apiSecret == ""):
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s and %s must be set together", checkCloudWatchAPIKeyAttr, checkCloudWatchAPISecretAttr)
+		case apiKey == "" && roleARN == "":
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s and %s or %s is required", checkCloudWatchAPIKeyAttr, checkCloudWatchAPISecretAttr, checkCloudWatchRoleARNAttr)
+		case externalID != "" && roleARN == "":
+			return attrErrorf(attrPath(checkCloudWatchAttr, i, checkCloudWatchExternalIDAttr), "%s requires %s", checkCloudWatchExternalIDAttr, checkCloudWatchRoleARNAttr)
+		}
+
+		if apiKey != "" {
+			c.Config[config.APIKey] = apiKey
+			c.Config[config.APISecret] = apiSecret
+		}
+
+		if roleARN != "" {
+			c.Config[apiCloudWatchRoleARN] = roleARN
+		}
+
+		if externalID != "" {
+			c.Config[apiCloudWatchExternalID] = externalID
 		}
 
-		if v, found := cloudwatchConfig[checkCloudWatchAPISecretAttr]; found {
-			c.Config[config.APISecret] = v.(string)
+		namespace, _ := cloudwatchConfig[checkCloudWatchNamespaceAttr].(string)
+		metrics := checkCloudWatchMetrics(cloudwatchConfig)
+		queries, _ := cloudwatchConfig[checkCloudWatchQueryAttr].([]interface{})
+
+		dimensions := cloudwatchConfig.CollectMap(checkCloudWatchDimensionsAttr)
+		deprecatedDimensions := cloudwatchConfig.CollectMap(checkCloudWatchDimmensionsAttr)
+		switch {
+		case len(dimensions) > 0 && len(deprecatedDimensions) > 0:
+			return attrErrorf(attrPath(checkCloudWatchAttr), "only one of %s or %s may be set", checkCloudWatchDimensionsAttr, checkCloudWatchDimmensionsAttr)
+		case len(dimensions) == 0:
+			dimensions = deprecatedDimensions
 		}
 
-		for k, v := range cloudwatchConfig.CollectMap(checkCloudWatchDimmensionsAttr) {
-			dimKey := config.DimPrefix + config.Key(k)
-			c.Config[dimKey] = v
+		switch {
+		case namespace == "" && len(queries) == 0:
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s or at least one %s is required", checkCloudWatchNamespaceAttr, checkCloudWatchQueryAttr)
+		case namespace == "" && (len(dimensions) > 0 || metrics != ""):
+			return attrErrorf(attrPath(checkCloudWatchAttr, i, checkCloudWatchNamespaceAttr), "%s is required when %s or %s is set", checkCloudWatchNamespaceAttr, checkCloudWatchDimensionsAttr, checkCloudWatchMetricAttr)
+		case namespace != "" && len(dimensions) == 0:
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s is required", checkCloudWatchDimensionsAttr)
+		case namespace != "" && metrics == "":
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s is required", checkCloudWatchMetricAttr)
 		}
 
-		if v, found := cloudwatchConfig[checkCloudWatchMetricAttr]; found {
-			metricsRaw := v.(*schema.Set).List()
-			metrics := make([]string, 0, len(metricsRaw))
-			for _, m := range metricsRaw {
-				metrics = append(metrics, m.(string))
+		if namespace != "" {
+			c.Config[config.Namespace] = namespace
+			c.Config[config.CloudwatchMetrics] = metrics
+			for k, v := range dimensions {
+				dimKey := config.DimPrefix + config.Key(k)
+				c.Config[dimKey] = v
 			}
-			sort.Strings(metrics)
-			c.Config[config.CloudwatchMetrics] = strings.Join(metrics, ",")
 		}
 
-		if v, found := cloudwatchConfig[checkCloudWatchNamespaceAttr]; found {
-			c.Config[config.Namespace] = v.(string)
+		for i, queryRaw := range queries {
+			query := newInterfaceMap(queryRaw)
+			prefix := fmt.Sprintf("%s%d", apiCloudWatchQueryPrefix, i)
+
+			c.Config[config.Key(prefix)] = query[checkCloudWatchNamespaceAttr].(string)
+			c.Config[config.Key(prefix+apiCloudWatchQueryMetricsSuffix)] = checkCloudWatchMetrics(query)
+			for k, v := range query.CollectMap(checkCloudWatchDimensionsAttr) {
+				c.Config[config.Key(prefix+apiCloudWatchQueryDimSuffix+k)] = v
+			}
 		}
 
-		if v, found := cloudwatchConfig[checkCloudWatchURLAttr]; found {
-			c.Config[config.URL] = v.(string)
+		url, _ := cloudwatchConfig[checkCloudWatchURLAttr].(string)
+		region, _ := cloudwatchConfig[checkCloudWatchRegionAttr].(string)
+		switch {
+		case url != "" && region != "":
+			return attrErrorf(attrPath(checkCloudWatchAttr), "only one of %s or %s may be set", checkCloudWatchURLAttr, checkCloudWatchRegionAttr)
+		case url == "" && region == "":
+			return attrErrorf(attrPath(checkCloudWatchAttr), "%s or %s is required", checkCloudWatchURLAttr, checkCloudWatchRegionAttr)
+		case region != "":
+			c.Config[apiCloudWatchRegion] = region
+			c.Config[config.URL] = fmt.Sprintf(checkCloudWatchRegionURLFmt, region)
+		default:
+			c.Config[config.URL] = url
 		}
 
 		if v, found := cloudwatchConfig[checkCloudWatchVersionAttr]; found {
@@ -255,3 +561,30 @@ func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nol
 
 	return nil
 }
+
+// checkCloudWatchMetrics returns the sorted, comma separated metric names of
+// a cloudwatch or query block.
+func checkCloudWatchMetrics(m interfaceMap) string {
+	v, found := m[checkCloudWatchMetricAttr]
+	if !found || v == nil {
+		return ""
+	}
+
+	metricsRaw := v.(*schema.Set).List()
+	metrics := make([]string, 0, len(metricsRaw))
+	for _, m := range metricsRaw {
+		metrics = append(metrics, m.(string))
+	}
+	sort.Strings(metrics)
+
+	return strings.Join(metrics, ",")
+}
+
+// validateCheckCloudWatch checks rules specific to cloudwatch checks.
+func validateCheckCloudWatch(c *circonusCheck) error {
+	if !(c.Period == 60 || c.Period == 300) {
+		return attrErrorf(attrPath(checkPeriodAttr), "Period must be either 1m or 5m for a %s check", apiCheckTypeCloudWatchAttr)
+	}
+
+	return nil
+}
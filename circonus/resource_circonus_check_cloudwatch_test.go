@@ -1,12 +1,17 @@
 package circonus
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckCloudWatch_basic(t *testing.T) {
@@ -38,8 +43,8 @@ func TestAccCirconusCheckCloudWatch_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "collector.#", "1"),
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "collector.0.id", testAccBroker1),
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.#", "1"),
-					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.dimmensions.%", "1"),
-					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.dimmensions.DBInstanceIdentifier", "atlas-production"),
+					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.dimensions.%", "1"),
+					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.dimensions.DBInstanceIdentifier", "atlas-production"),
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.metric.#", "17"),
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.namespace", "AWS/RDS"),
 					resource.TestCheckResourceAttr("circonus_check.rds_metrics", "cloudwatch.0.version", "2010-08-01"),
@@ -154,7 +159,7 @@ resource "circonus_check" "rds_metrics" {
   target = "atlas-production.us-east-1.rds._aws"
 
   cloudwatch {
-    dimmensions = {
+    dimensions = {
       DBInstanceIdentifier = "atlas-production",
     }
 
@@ -270,3 +275,258 @@ resource "circonus_check" "rds_metrics" {
   tags = "${var.cloudwatch_rds_tags}"
 }
 `
+
+func TestAccCirconusCheckCloudWatch_roleQueries(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: EC2 and EBS Metrics via CloudWatch - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckCloudWatchRoleQueriesConfigFmt,
+					checkName,
+					testAccBroker1,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.external_id", "circonus-monitoring"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.0.namespace", "AWS/EC2"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.0.dimensions.InstanceId", "i-0123456789abcdef0"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.0.metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.1.namespace", "AWS/EBS"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.1.dimensions.VolumeId", "vol-0123456789abcdef0"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.query.1.metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.region", "us-west-2"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.role_arn", "arn:aws:iam::123456789012:role/circonus-cloudwatch"),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "cloudwatch.0.url", ""),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.ec2_metrics", "type", "cloudwatch"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.ec2_metrics",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckCloudWatchRoleQueriesConfigFmt = `
+resource "circonus_check" "ec2_metrics" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  target = "i-0123456789abcdef0.us-west-2.ec2._aws"
+
+  cloudwatch {
+    api_key = ""
+    api_secret = ""
+    external_id = "circonus-monitoring"
+    region = "us-west-2"
+    role_arn = "arn:aws:iam::123456789012:role/circonus-cloudwatch"
+
+    query {
+      namespace = "AWS/EC2"
+      dimensions = {
+        InstanceId = "i-0123456789abcdef0",
+      }
+      metric = ["CPUUtilization", "NetworkIn"]
+    }
+
+    query {
+      namespace = "AWS/EBS"
+      dimensions = {
+        VolumeId = "vol-0123456789abcdef0",
+      }
+      metric = ["VolumeReadOps"]
+    }
+  }
+
+  metric {
+    name = "CPUUtilization"
+    type = "numeric"
+  }
+
+  metric {
+    name = "NetworkIn"
+    type = "numeric"
+  }
+
+  metric {
+    name = "VolumeReadOps"
+    type = "numeric"
+  }
+
+  tags = [ "source:cloudwatch", "lifecycle:unittest" ]
+}
+`
+
+func Test_CheckConfigToAPICloudWatch(t *testing.T) {
+	metrics := func(names ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, names)
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[config.Key]string
+		fail     bool
+		path     cty.Path
+	}{
+		{
+			name: "keys and url",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):     "key",
+				string(checkCloudWatchAPISecretAttr):  "secret",
+				string(checkCloudWatchDimensionsAttr): map[string]interface{}{"DBInstanceIdentifier": "atlas"},
+				string(checkCloudWatchMetricAttr):     metrics("ReadIOPS", "CPUUtilization"),
+				string(checkCloudWatchNamespaceAttr):  "AWS/RDS",
+				string(checkCloudWatchURLAttr):        "https://monitoring.us-east-1.amazonaws.com",
+			},
+			expected: map[config.Key]string{
+				config.APIKey:              "key",
+				config.APISecret:           "secret",
+				"dim_DBInstanceIdentifier": "atlas",
+				config.CloudwatchMetrics:   "CPUUtilization,ReadIOPS",
+				config.Namespace:           "AWS/RDS",
+				config.URL:                 "https://monitoring.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name: "role, region and queries",
+			config: map[string]interface{}{
+				string(checkCloudWatchExternalIDAttr): "circonus",
+				string(checkCloudWatchQueryAttr): []interface{}{
+					map[string]interface{}{
+						string(checkCloudWatchDimensionsAttr): map[string]interface{}{"InstanceId": "i-1"},
+						string(checkCloudWatchMetricAttr):     metrics("NetworkIn", "CPUUtilization"),
+						string(checkCloudWatchNamespaceAttr):  "AWS/EC2",
+					},
+					map[string]interface{}{
+						string(checkCloudWatchDimensionsAttr): map[string]interface{}{"VolumeId": "vol-1"},
+						string(checkCloudWatchMetricAttr):     metrics("VolumeReadOps"),
+						string(checkCloudWatchNamespaceAttr):  "AWS/EBS",
+					},
+				},
+				string(checkCloudWatchRegionAttr):  "eu-west-1",
+				string(checkCloudWatchRoleARNAttr): "arn:aws:iam::123456789012:role/circonus",
+			},
+			expected: map[config.Key]string{
+				apiCloudWatchExternalID:      "circonus",
+				"namespace_0":                "AWS/EC2",
+				"namespace_0_dim_InstanceId": "i-1",
+				"namespace_0_metrics":        "CPUUtilization,NetworkIn",
+				"namespace_1":                "AWS/EBS",
+				"namespace_1_dim_VolumeId":   "vol-1",
+				"namespace_1_metrics":        "VolumeReadOps",
+				apiCloudWatchRegion:          "eu-west-1",
+				apiCloudWatchRoleARN:         "arn:aws:iam::123456789012:role/circonus",
+				config.URL:                   "https://monitoring.eu-west-1.amazonaws.com",
+			},
+		},
+		{
+			name: "no credentials",
+			config: map[string]interface{}{
+				string(checkCloudWatchDimensionsAttr): map[string]interface{}{"DBInstanceIdentifier": "atlas"},
+				string(checkCloudWatchMetricAttr):     metrics("ReadIOPS"),
+				string(checkCloudWatchNamespaceAttr):  "AWS/RDS",
+				string(checkCloudWatchRegionAttr):     "us-east-1",
+			},
+			fail: true,
+		},
+		{
+			name: "external id without role",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):     "key",
+				string(checkCloudWatchAPISecretAttr):  "secret",
+				string(checkCloudWatchDimensionsAttr): map[string]interface{}{"DBInstanceIdentifier": "atlas"},
+				string(checkCloudWatchExternalIDAttr): "circonus",
+				string(checkCloudWatchMetricAttr):     metrics("ReadIOPS"),
+				string(checkCloudWatchNamespaceAttr):  "AWS/RDS",
+				string(checkCloudWatchRegionAttr):     "us-east-1",
+			},
+			fail: true,
+			path: attrPath(checkCloudWatchAttr, 0, checkCloudWatchExternalIDAttr),
+		},
+		{
+			name: "url and region",
+			config: map[string]interface{}{
+				string(checkCloudWatchDimensionsAttr): map[string]interface{}{"DBInstanceIdentifier": "atlas"},
+				string(checkCloudWatchMetricAttr):     metrics("ReadIOPS"),
+				string(checkCloudWatchNamespaceAttr):  "AWS/RDS",
+				string(checkCloudWatchRegionAttr):     "us-east-1",
+				string(checkCloudWatchRoleARNAttr):    "arn:aws:iam::123456789012:role/circonus",
+				string(checkCloudWatchURLAttr):        "https://monitoring.us-east-1.amazonaws.com",
+			},
+			fail: true,
+		},
+		{
+			name: "no namespace or query",
+			config: map[string]interface{}{
+				string(checkCloudWatchRegionAttr):  "us-east-1",
+				string(checkCloudWatchRoleARNAttr): "arn:aws:iam::123456789012:role/circonus",
+			},
+			fail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPICloudWatch(&c, interfaceList{test.config})
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				var attrErr *attributeError
+				if test.path != nil && (!errors.As(err, &attrErr) || !attrErr.path.Equals(test.path)) {
+					t.Errorf("expected an error at %#v, got %v", test.path, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Config) != len(test.expected) {
+				t.Fatalf("expected %d config keys, got %#v", len(test.expected), c.Config)
+			}
+
+			for k, v := range test.expected {
+				if c.Config[k] != v {
+					t.Errorf("config key %q: expected %q, got %q", k, v, c.Config[k])
+				}
+			}
+
+			// The queries must survive a round trip through the broker config.
+			queries, err := checkCloudWatchQueriesToState(c.Config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			configQueries, _ := test.config[string(checkCloudWatchQueryAttr)].([]interface{})
+			if len(queries) != len(configQueries) {
+				t.Fatalf("expected %d queries, got %d", len(configQueries), len(queries))
+			}
+			for i := range queries {
+				var want, got bytes.Buffer
+				hashCheckCloudWatchQuery(&want, newInterfaceMap(configQueries[i]))
+				hashCheckCloudWatchQuery(&got, newInterfaceMap(queries[i]))
+				if want.String() != got.String() {
+					t.Errorf("query %d: expected %q, got %q", i, want.String(), got.String())
+				}
+			}
+		})
+	}
+}
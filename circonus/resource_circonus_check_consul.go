@@ -1,6 +1,7 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/url"
@@ -12,6 +13,10 @@ import (
 )
 
 const (
+	// circonus_check.consul check type.
+	checkConsulAttr                     = "consul"
+	apiCheckTypeConsulAttr apiCheckType = "consul"
+
 	// circonus_check.consul.* resource attribute names.
 	checkConsulACLTokenAttr             = "acl_token"
 	checkConsulAllowStaleAttr           = "allow_stale"
@@ -50,6 +55,18 @@ var checkConsulDescriptions = attrDescrs{
 
 var consulHealthCheckRE = regexp.MustCompile(fmt.Sprintf(`^%s/(%s|%s|%s)/(.+)`, checkConsulV1Prefix, checkConsulV1NodePrefix, checkConsulV1ServicePrefix, checkConsulV1StatePrefix))
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeConsulAttr,
+		attr:        checkConsulAttr,
+		description: "Consul check configuration",
+		schema:      schemaCheckConsul,
+		toAPI:       checkConfigToAPIConsul,
+		toState:     checkAPIToStateConsul,
+		validate:    validateCheckConsul,
+	})
+}
+
 var schemaCheckConsul = &schema.Schema{
 	Type:     schema.TypeList,
 	Optional: true,
@@ -160,7 +177,7 @@ var schemaCheckConsul = &schema.Schema{
 
 // checkAPIToStateConsul reads the Config data out of circonusCheck.CheckBundle into
 // the statefile.
-func checkAPIToStateConsul(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateConsul(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	consulConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -304,7 +321,7 @@ func checkAPIToStateConsul(c *circonusCheck, d *schema.ResourceData) error {
 }
 
 func checkConfigToAPIConsul(c *circonusCheck, l interfaceList) error {
-	c.Type = string(apiCheckTypeConsul)
+	c.Type = string(apiCheckTypeConsulAttr)
 
 	// Iterate over all `consul` attributes, even though we have a max of 1 in the
 	// schema.
@@ -407,3 +424,12 @@ func checkConfigToAPIConsul(c *circonusCheck, l interfaceList) error {
 
 	return nil
 }
+
+// validateCheckConsul checks rules specific to consul checks.
+func validateCheckConsul(c *circonusCheck) error {
+	if v, found := c.Config[config.URL]; !found || v == "" {
+		return attrErrorf(attrPath(checkConsulAttr), "%s must have at least one check mode set: %s, %s, or %s must be set", checkConsulAttr, checkConsulServiceAttr, checkConsulNodeAttr, checkConsulStateAttr)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package circonus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func Test_CheckCustomizeDiff(t *testing.T) {
+	metric := []interface{}{
+		map[string]interface{}{"name": "foo", "type": "numeric"},
+	}
+	jsonBlock := []interface{}{
+		map[string]interface{}{"url": "https://api.example.com/stats"},
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		errMsg string
+		path   cty.Path
+	}{
+		{
+			name: "valid",
+			config: map[string]interface{}{
+				"name":   "test",
+				"json":   jsonBlock,
+				"metric": metric,
+			},
+		},
+		{
+			name: "no check type",
+			config: map[string]interface{}{
+				"name":   "test",
+				"metric": metric,
+			},
+			errMsg: "one check type must be configured",
+			path:   cty.Path{},
+		},
+		{
+			name: "two check types",
+			config: map[string]interface{}{
+				"name":      "test",
+				"json":      jsonBlock,
+				"icmp_ping": []interface{}{map[string]interface{}{}},
+				"metric":    metric,
+			},
+			errMsg: "only one check type may be configured",
+			path:   attrPath(checkJSONAttr),
+		},
+		{
+			name: "metric and metric_filter",
+			config: map[string]interface{}{
+				"name":   "test",
+				"json":   jsonBlock,
+				"metric": metric,
+				"metric_filter": []interface{}{
+					map[string]interface{}{"type": "allow", "regex": ".*"},
+				},
+			},
+			errMsg: "Metrics and MetricFilters both have entries",
+			path:   attrPath(checkMetricFilterAttr),
+		},
+		{
+			name: "duplicate metric_filter name",
+			config: map[string]interface{}{
+				"name": "test",
+				"json": jsonBlock,
+				"metric_filter": []interface{}{
+					map[string]interface{}{"name": "all", "priority": 10, "type": "allow", "regex": "^cpu"},
+					map[string]interface{}{"name": "all", "priority": 20, "type": "deny", "regex": ".*"},
+				},
+			},
+			errMsg: "duplicate metric_filter name \"all\"",
+			path:   attrPath(checkMetricFilterAttr),
+		},
+		{
+			name: "timeout exceeds period",
+			config: map[string]interface{}{
+				"name":    "test",
+				"json":    jsonBlock,
+				"metric":  metric,
+				"period":  "30s",
+				"timeout": "45s",
+			},
+			errMsg: "can not exceed period",
+			path:   attrPath(checkTimeoutAttr),
+		},
+		{
+			name: "wait_for_first_run on a push check",
+			config: map[string]interface{}{
+				"name":               "test",
+				"httptrap":           []interface{}{map[string]interface{}{"secret": "12345"}},
+				"metric":             metric,
+				"wait_for_first_run": true,
+			},
+			errMsg: "can not be used with httptrap checks",
+			path:   attrPath(checkWaitForFirstRunAttr),
+		},
+		{
+			name: "collector and collector_selector",
+			config: map[string]interface{}{
+				"name":      "test",
+				"json":      jsonBlock,
+				"metric":    metric,
+				"collector": []interface{}{map[string]interface{}{"id": "/broker/1"}},
+				"collector_selector": []interface{}{
+					map[string]interface{}{"type": "circonus"},
+				},
+			},
+			errMsg: "only one of collector or collector_selector may be set",
+			path:   attrPath(checkCollectorSelectorAttr),
+		},
+	}
+
+	r := resourceCheck()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(test.config), &providerContext{})
+			if test.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error containing %q", test.errMsg)
+			}
+
+			if !strings.Contains(err.Error(), test.errMsg) {
+				t.Errorf("expected error containing %q, got %q", test.errMsg, err)
+			}
+
+			pathErr, ok := err.(cty.PathError)
+			if !ok {
+				t.Fatalf("expected cty.PathError, got %T", err)
+			}
+
+			if !pathErr.Path.Equals(test.path) {
+				t.Errorf("expected path %#v, got %#v", test.path, pathErr.Path)
+			}
+		})
+	}
+}
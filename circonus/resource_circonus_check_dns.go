@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,10 @@ import (
 )
 
 const (
+	// circonus_check.dns check type.
+	checkDNSAttr                     = "dns"
+	apiCheckTypeDNSAttr apiCheckType = "dns"
+
 	// circonus_check.dns.* resource attribute names.
 	checkDNSCTypeAttr      = "ctype"
 	checkDNSNameserverAttr = "nameserver"
@@ -25,6 +30,17 @@ var checkDNSDescriptions = attrDescrs{
 	checkDNSRTypeAttr:      "The DNS resource record type of the query. If the name of the check is in-addr.arpa, the default is PTR, otherwise it is A.",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeDNSAttr,
+		attr:        checkDNSAttr,
+		description: "DNS check configuration",
+		schema:      schemaCheckDNS,
+		toAPI:       checkConfigToAPIDNS,
+		toState:     checkAPIToStateDNS,
+	})
+}
+
 var schemaCheckDNS = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -76,7 +92,7 @@ var schemaCheckDNS = &schema.Schema{
 
 // checkAPIToStateDNS reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateDNS(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateDNS(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	dnsConfig := make(map[string]interface{}, len(c.Config))
 
 	if ctype, ok := c.Config[config.CType]; ok {
@@ -124,7 +140,7 @@ func hashCheckDNS(v interface{}) int {
 }
 
 func checkConfigToAPIDNS(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeDNS)
+	c.Type = string(apiCheckTypeDNSAttr)
 
 	mapRaw := l[0]
 	dnsConfig := newInterfaceMap(mapRaw)
@@ -0,0 +1,308 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.elasticsearch check type.
+	checkElasticsearchAttr                     = "elasticsearch"
+	apiCheckTypeElasticsearchAttr apiCheckType = "elasticsearch"
+
+	// circonus_check.elasticsearch.* resource attribute names.
+	checkElasticsearchAuthPasswordAttr = "auth_password"
+	checkElasticsearchAuthUserAttr     = "auth_user"
+	checkElasticsearchCAChainAttr      = "ca_chain"
+	checkElasticsearchCertFileAttr     = "certificate_file"
+	checkElasticsearchCiphersAttr      = "ciphers"
+	checkElasticsearchEndpointAttr     = "endpoint"
+	checkElasticsearchKeyFileAttr      = "key_file"
+	checkElasticsearchURLAttr          = "url"
+)
+
+var checkElasticsearchDescriptions = attrDescrs{
+	checkElasticsearchAuthPasswordAttr: "The password used for HTTP basic authentication",
+	checkElasticsearchAuthUserAttr:     "The user name used for HTTP basic authentication",
+	checkElasticsearchCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
+	checkElasticsearchCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS checks)",
+	checkElasticsearchCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for HTTPS checks)",
+	checkElasticsearchEndpointAttr:     "The Elasticsearch API to collect metrics from: cluster_health, node_stats, index_stats, or custom to use url unmodified",
+	checkElasticsearchKeyFileAttr:      "A path to a file containing key to be used in conjunction with the client certificate (for TLS checks)",
+	checkElasticsearchURLAttr:          "The base URL of the Elasticsearch cluster",
+}
+
+// checkElasticsearchEndpointPaths are the URL paths, relative to the cluster's
+// base URL, of each of the endpoint attribute's values.
+var checkElasticsearchEndpointPaths = map[string]string{
+	checkElasticsearchEndpointClusterHealth: "/_cluster/health",
+	checkElasticsearchEndpointIndexStats:    "/_stats",
+	checkElasticsearchEndpointNodeStats:     "/_nodes/_local/stats",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeElasticsearchAttr,
+		attr:        checkElasticsearchAttr,
+		description: "Elasticsearch check configuration",
+		schema:      schemaCheckElasticsearch,
+		toAPI:       checkConfigToAPIElasticsearch,
+		toState:     checkAPIToStateElasticsearch,
+	})
+}
+
+var schemaCheckElasticsearch = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      checkElasticsearchConfigChecksum,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkElasticsearchDescriptions, map[schemaAttr]*schema.Schema{
+			checkElasticsearchAuthPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkElasticsearchAuthPasswordAttr, `^.*`),
+			},
+			checkElasticsearchAuthUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkElasticsearchAuthUserAttr, `[^:]+`),
+			},
+			checkElasticsearchCAChainAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkElasticsearchCAChainAttr, `.+`),
+			},
+			checkElasticsearchCertFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkElasticsearchCertFileAttr, `.+`),
+			},
+			checkElasticsearchCiphersAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkElasticsearchCiphersAttr, `.+`),
+			},
+			checkElasticsearchEndpointAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultCheckElasticsearchEndpoint,
+				ValidateFunc: validateStringIn(checkElasticsearchEndpointAttr, validStringValues{
+					checkElasticsearchEndpointClusterHealth,
+					checkElasticsearchEndpointCustom,
+					checkElasticsearchEndpointIndexStats,
+					checkElasticsearchEndpointNodeStats,
+				}),
+			},
+			checkElasticsearchKeyFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkElasticsearchKeyFileAttr, `.+`),
+			},
+			checkElasticsearchURLAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validateFuncs(
+					validateHTTPURL(checkElasticsearchURLAttr, urlIsAbs),
+				),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateElasticsearch reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStateElasticsearch(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	esConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, s := range c.Config {
+		swamp[k] = s
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			esConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.AuthPassword, checkElasticsearchAuthPasswordAttr)
+	saveStringConfigToState(config.AuthUser, checkElasticsearchAuthUserAttr)
+	saveStringConfigToState(config.CAChain, checkElasticsearchCAChainAttr)
+	saveStringConfigToState(config.CertFile, checkElasticsearchCertFileAttr)
+	saveStringConfigToState(config.Ciphers, checkElasticsearchCiphersAttr)
+	saveStringConfigToState(config.KeyFile, checkElasticsearchKeyFileAttr)
+
+	// auth_method and port are derived from auth_user and url.
+	delete(swamp, config.AuthMethod)
+	delete(swamp, config.Port)
+
+	baseURL, endpoint := checkElasticsearchSplitURL(c.Config[config.URL])
+	if checkElasticsearchStateEndpoint(d) == checkElasticsearchEndpointCustom {
+		baseURL, endpoint = c.Config[config.URL], checkElasticsearchEndpointCustom
+	}
+	esConfig[string(checkElasticsearchURLAttr)] = baseURL
+	esConfig[string(checkElasticsearchEndpointAttr)] = endpoint
+	delete(swamp, config.URL)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkElasticsearchAttr, schema.NewSet(checkElasticsearchConfigChecksum, []interface{}{esConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkElasticsearchAttr, err)
+	}
+
+	return nil
+}
+
+// checkElasticsearchSplitURL splits a check's URL into the cluster's base URL
+// and the endpoint it collects from.  URLs that don't end with one of the
+// endpoint paths are custom.
+func checkElasticsearchSplitURL(checkURL string) (string, string) {
+	u, err := url.Parse(checkURL)
+	if err != nil {
+		return checkURL, checkElasticsearchEndpointCustom
+	}
+
+	for endpoint, path := range checkElasticsearchEndpointPaths {
+		if strings.HasSuffix(u.Path, path) && u.RawQuery == "" {
+			u.Path = strings.TrimSuffix(u.Path, path)
+			return u.String(), endpoint
+		}
+	}
+
+	return checkURL, checkElasticsearchEndpointCustom
+}
+
+// checkElasticsearchStateEndpoint returns the endpoint currently in the state,
+// if any.
+func checkElasticsearchStateEndpoint(d *schema.ResourceData) string {
+	v, ok := d.GetOk(checkElasticsearchAttr)
+	if !ok {
+		return ""
+	}
+
+	for _, mapRaw := range v.(*schema.Set).List() {
+		if endpoint, found := newInterfaceMap(mapRaw)[checkElasticsearchEndpointAttr]; found {
+			return endpoint.(string)
+		}
+	}
+
+	return ""
+}
+
+// checkElasticsearchConfigChecksum creates a stable hash of the normalized
+// values found in a user's Terraform config.
+func checkElasticsearchConfigChecksum(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkElasticsearchAuthPasswordAttr)
+	writeString(checkElasticsearchAuthUserAttr)
+	writeString(checkElasticsearchCAChainAttr)
+	writeString(checkElasticsearchCertFileAttr)
+	writeString(checkElasticsearchCiphersAttr)
+	writeString(checkElasticsearchEndpointAttr)
+	writeString(checkElasticsearchKeyFileAttr)
+
+	// The endpoint path is appended to the base URL with a single slash, so
+	// the URL is read back without the slash it may be configured with.
+	if v, ok := m[string(checkElasticsearchURLAttr)].(string); ok && v != "" {
+		if endpoint, _ := m[string(checkElasticsearchEndpointAttr)].(string); endpoint != checkElasticsearchEndpointCustom {
+			v = strings.TrimSuffix(strings.TrimSpace(v), "/")
+		}
+		fmt.Fprint(b, strings.TrimSpace(v))
+	}
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIElasticsearch(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeElasticsearchAttr)
+
+	// Iterate over all `elasticsearch` attributes, even though we have a max of
+	// 1 in the schema.
+	for _, mapRaw := range l {
+		esConfig := newInterfaceMap(mapRaw)
+
+		if v, found := esConfig[checkElasticsearchAuthUserAttr]; found && v.(string) != "" {
+			c.Config[config.AuthMethod] = "Basic"
+			c.Config[config.AuthUser] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchAuthPasswordAttr]; found {
+			c.Config[config.AuthPassword] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchCAChainAttr]; found {
+			c.Config[config.CAChain] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchCertFileAttr]; found {
+			c.Config[config.CertFile] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchCiphersAttr]; found {
+			c.Config[config.Ciphers] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchKeyFileAttr]; found {
+			c.Config[config.KeyFile] = v.(string)
+		}
+
+		if v, found := esConfig[checkElasticsearchURLAttr]; found {
+			checkURL := v.(string)
+			endpoint, _ := esConfig[checkElasticsearchEndpointAttr].(string)
+			if path, ok := checkElasticsearchEndpointPaths[endpoint]; ok {
+				checkURL = strings.TrimSuffix(checkURL, "/") + path
+			}
+			c.Config[config.URL] = checkURL
+
+			u, _ := url.Parse(checkURL)
+			hostInfo := strings.SplitN(u.Host, ":", 2)
+			if len(c.Target) == 0 {
+				c.Target = hostInfo[0]
+			}
+
+			if len(hostInfo) > 1 {
+				c.Config[config.Port] = hostInfo[1]
+			}
+		}
+	}
+
+	return nil
+}
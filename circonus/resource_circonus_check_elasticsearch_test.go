@@ -0,0 +1,135 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckElasticsearch_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Elasticsearch cluster health - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckElasticsearchConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.es_health", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "collector.0.id", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.0.url", "https://es.example.com:9200"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.0.endpoint", "cluster_health"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.0.auth_user", "monitor"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.0.auth_password", "secret"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "elasticsearch.0.ca_chain", "/etc/ssl/es-ca.pem"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "target", "es.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.es_health", "type", "elasticsearch"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.es_health",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+func Test_CheckElasticsearchSplitURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		baseURL  string
+		endpoint string
+	}{
+		{"https://es.example.com:9200/_cluster/health", "https://es.example.com:9200", checkElasticsearchEndpointClusterHealth},
+		{"https://es.example.com:9200/_nodes/_local/stats", "https://es.example.com:9200", checkElasticsearchEndpointNodeStats},
+		{"https://proxy.example.com/es/_stats", "https://proxy.example.com/es", checkElasticsearchEndpointIndexStats},
+		{"https://es.example.com:9200/_cat/indices", "https://es.example.com:9200/_cat/indices", checkElasticsearchEndpointCustom},
+		{"https://es.example.com:9200/_stats?level=shards", "https://es.example.com:9200/_stats?level=shards", checkElasticsearchEndpointCustom},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			baseURL, endpoint := checkElasticsearchSplitURL(test.url)
+			if baseURL != test.baseURL || endpoint != test.endpoint {
+				t.Errorf("expected (%q, %q), got (%q, %q)", test.baseURL, test.endpoint, baseURL, endpoint)
+			}
+		})
+	}
+}
+
+func Test_CheckElasticsearchURLTrailingSlash(t *testing.T) {
+	configured := map[string]interface{}{
+		string(checkElasticsearchEndpointAttr): checkElasticsearchEndpointNodeStats,
+		string(checkElasticsearchURLAttr):      "https://es.example.com:9200/",
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIElasticsearch(&c, interfaceList{configured}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	baseURL, endpoint := checkElasticsearchSplitURL(c.Config[config.URL])
+	read := map[string]interface{}{
+		string(checkElasticsearchEndpointAttr): endpoint,
+		string(checkElasticsearchURLAttr):      baseURL,
+	}
+
+	if checkElasticsearchConfigChecksum(configured) != checkElasticsearchConfigChecksum(read) {
+		t.Errorf("expected %q to read back as the configured %q", baseURL, configured[string(checkElasticsearchURLAttr)])
+	}
+
+	custom := map[string]interface{}{
+		string(checkElasticsearchEndpointAttr): checkElasticsearchEndpointCustom,
+		string(checkElasticsearchURLAttr):      "https://es.example.com:9200/_cat/indices/",
+	}
+	trimmed := map[string]interface{}{
+		string(checkElasticsearchEndpointAttr): checkElasticsearchEndpointCustom,
+		string(checkElasticsearchURLAttr):      "https://es.example.com:9200/_cat/indices",
+	}
+	if checkElasticsearchConfigChecksum(custom) == checkElasticsearchConfigChecksum(trimmed) {
+		t.Error("expected the slash of a custom URL to be kept")
+	}
+}
+
+const testAccCirconusCheckElasticsearchConfigFmt = `
+resource "circonus_check" "es_health" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  elasticsearch {
+    url           = "https://es.example.com:9200"
+    endpoint      = "cluster_health"
+    auth_user     = "monitor"
+    auth_password = "secret"
+    ca_chain      = "/etc/ssl/es-ca.pem"
+  }
+
+  metric {
+    name = "status"
+    type = "text"
+  }
+
+  metric {
+    name = "number_of_nodes"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
@@ -1,6 +1,7 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,10 @@ import (
 )
 
 const (
+	// circonus_check.external check type.
+	checkExternalAttr                     = "external"
+	apiCheckTypeExternalAttr apiCheckType = "external"
+
 	// circonus_check.http.* resource attribute names.
 	checkCommandAttr       = "command"
 	checkOutputExtractAttr = "output_extract"
@@ -41,6 +46,17 @@ var checkExternalDescriptions = attrDescrs{
 	checkExternalEnvAttr:   "The map of environment vars",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeExternalAttr,
+		attr:        checkExternalAttr,
+		description: "External check configuration",
+		schema:      schemaCheckExternal,
+		toAPI:       checkConfigToAPIExternal,
+		toState:     checkAPIToStateExternal,
+	})
+}
+
 var schemaCheckExternal = &schema.Schema{
 	Type:     schema.TypeList,
 	Optional: true,
@@ -108,7 +124,7 @@ var schemaCheckExternal = &schema.Schema{
 
 // checkAPIToStateExternal reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateExternal(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateExternal(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	externalConfig := make(map[string]interface{}, len(c.Config))
 	envs := make(map[string]interface{})
 
@@ -179,7 +195,7 @@ func checkAPIToStateExternal(c *circonusCheck, d *schema.ResourceData) error {
 }
 
 func checkConfigToAPIExternal(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeExternal)
+	c.Type = string(apiCheckTypeExternalAttr)
 
 	// Iterate over all `http` attributes, even though we have a max of 1 in the
 	// schema.
@@ -2,8 +2,9 @@ package circonus
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
 	"sort"
 	"strconv"
@@ -11,10 +12,15 @@ import (
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.http check type.
+	checkHTTPAttr                     = "http"
+	apiCheckTypeHTTPAttr apiCheckType = "http"
+
 	// circonus_check.http.* resource attribute names.
 	checkHTTPAuthMethodAttr   = "auth_method"
 	checkHTTPAuthPasswordAttr = "auth_password"
@@ -53,6 +59,23 @@ var checkHTTPDescriptions = attrDescrs{
 	checkHTTPURLAttr:          "The URL to use as the target of the check",
 	checkHTTPVersionAttr:      "Sets the HTTP version for the check to use",
 	checkHTTPRedirectsAttr:    "The maximum number of Location header redirects to follow.",
+	checkHTTPStepAttr:         "An ordered list of requests making up a multi-step HTTP transaction, used instead of url",
+	checkTLSCertExpiryAttr:    checkTLSDescriptions[checkTLSCertExpiryAttr],
+	checkTLSExpectedNamesAttr: checkTLSDescriptions[checkTLSExpectedNamesAttr],
+	checkTLSMinVersionAttr:    checkTLSDescriptions[checkTLSMinVersionAttr],
+	checkTLSSNIAttr:           checkTLSDescriptions[checkTLSSNIAttr],
+	checkTLSVerifyModeAttr:    checkTLSDescriptions[checkTLSVerifyModeAttr],
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeHTTPAttr,
+		attr:        checkHTTPAttr,
+		description: "HTTP check configuration",
+		schema:      schemaCheckHTTP,
+		toAPI:       checkConfigToAPIHTTP,
+		toState:     checkAPIToStateHTTP,
+	})
 }
 
 var schemaCheckHTTP = &schema.Schema{
@@ -62,7 +85,7 @@ var schemaCheckHTTP = &schema.Schema{
 	MinItems: 1,
 	Set:      hashCheckHTTP,
 	Elem: &schema.Resource{
-		Schema: convertToHelperSchema(checkHTTPDescriptions, map[schemaAttr]*schema.Schema{
+		Schema: convertToHelperSchema(checkHTTPDescriptions, addCheckTLSSchema(map[schemaAttr]*schema.Schema{
 			checkHTTPAuthMethodAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -139,9 +162,10 @@ var schemaCheckHTTP = &schema.Schema{
 					validateIntMin(checkHTTPReadLimitAttr, 0),
 				),
 			},
+			checkHTTPStepAttr: schemaCheckHTTPStep,
 			checkHTTPURLAttr: {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				ValidateFunc: validateFuncs(
 					validateHTTPURL(checkHTTPURLAttr, urlIsAbs),
 				),
@@ -158,13 +182,13 @@ var schemaCheckHTTP = &schema.Schema{
 				Default:      defaultCheckHTTPRedirects,
 				ValidateFunc: validateRegexp(checkHTTPRedirectsAttr, `^[0-9]+$`),
 			},
-		}),
+		})),
 	},
 }
 
 // checkAPIToStateHTTP reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateHTTP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	httpConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -185,7 +209,7 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 		if v, ok := c.Config[apiKey]; ok {
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 
@@ -224,10 +248,22 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.Method, checkHTTPMethodAttr)
 	saveStringConfigToState(config.Payload, checkHTTPPayloadAttr)
 	saveIntConfigToState(config.ReadLimit, checkHTTPReadLimitAttr)
+
+	if v, ok := c.Config[apiHTTPSteps]; ok {
+		steps, err := checkHTTPStepsToState(v)
+		if err != nil {
+			return err
+		}
+		httpConfig[string(checkHTTPStepAttr)] = steps
+	}
+	delete(swamp, apiHTTPSteps)
+
 	saveStringConfigToState(config.URL, checkHTTPURLAttr)
 	saveStringConfigToState(config.HTTPVersion, checkHTTPVersionAttr)
 	saveStringConfigToState(config.Redirects, checkHTTPRedirectsAttr)
 
+	checkTLSAPIToState(c, d, checkHTTPAttr, swamp, httpConfig)
+
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
 		config.SubmissionURL:    {},
@@ -298,16 +334,25 @@ func hashCheckHTTP(v interface{}) int {
 	writeString(checkHTTPMethodAttr)
 	writeString(checkHTTPPayloadAttr)
 	writeInt(checkHTTPReadLimitAttr)
+
+	if stepsRaw, ok := m[string(checkHTTPStepAttr)].([]interface{}); ok && len(stepsRaw) > 0 {
+		if buf, err := json.Marshal(checkHTTPStepsFromList(stepsRaw)); err == nil {
+			b.Write(buf)
+		}
+	}
+
 	writeString(checkHTTPURLAttr)
 	writeString(checkHTTPVersionAttr)
 	writeString(checkHTTPRedirectsAttr)
 
+	hashCheckTLS(b, m)
+
 	s := b.String()
 	return hashcode.String(s)
 }
 
 func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
-	c.Type = string(apiCheckTypeHTTP)
+	c.Type = string(apiCheckTypeHTTPAttr)
 
 	// Iterate over all `http` attributes, even though we have a max of 1 in the
 	// schema.
@@ -327,9 +372,11 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 	var httpConfig interfaceMap
 
 	for _, mapRaw := range l {
-		if v, found := newInterfaceMap(mapRaw)[checkHTTPURLAttr]; found &&
-			v.(string) != "" {
-			httpConfig = newInterfaceMap(mapRaw)
+		m := newInterfaceMap(mapRaw)
+		u, _ := m[checkHTTPURLAttr].(string)
+		steps, _ := m[checkHTTPStepAttr].([]interface{})
+		if u != "" || len(steps) > 0 {
+			httpConfig = m
 
 			break
 		}
@@ -339,6 +386,21 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 		return fmt.Errorf("http config url not set, or http config not found")
 	}
 
+	if stepsRaw, _ := httpConfig[checkHTTPStepAttr].([]interface{}); len(stepsRaw) > 0 {
+		if u, _ := httpConfig[checkHTTPURLAttr].(string); u != "" {
+			return attrErrorf(attrPath(checkHTTPAttr), "only one of %s or %s may be set", checkHTTPURLAttr, checkHTTPStepAttr)
+		}
+
+		steps := checkHTTPStepsFromList(stepsRaw)
+		if err := checkHTTPStepsToAPI(c, steps); err != nil {
+			return err
+		}
+
+		if u, err := url.Parse(steps[0].URL); err == nil && len(c.Target) == 0 {
+			c.Target = u.Hostname()
+		}
+	}
+
 	if v, found := httpConfig[checkHTTPAuthMethodAttr]; found {
 		c.Config[config.AuthMethod] = v.(string)
 	}
@@ -396,7 +458,7 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 		c.Config[config.ReadLimit] = fmt.Sprintf("%d", v.(int))
 	}
 
-	if v, found := httpConfig[checkHTTPURLAttr]; found {
+	if v, found := httpConfig[checkHTTPURLAttr]; found && v.(string) != "" {
 		c.Config[config.URL] = v.(string)
 
 		u, _ := url.Parse(v.(string))
@@ -418,5 +480,9 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 		c.Config[config.Redirects] = v.(string)
 	}
 
-	return nil
+	if u, _ := httpConfig[checkHTTPURLAttr].(string); strings.HasPrefix(u, "http://") && checkTLSSet(httpConfig) {
+		return attrErrorf(attrPath(checkHTTPAttr), "TLS settings require an https %s", checkHTTPURLAttr)
+	}
+
+	return checkTLSConfigToAPI(c, checkHTTPAttr, httpConfig)
 }
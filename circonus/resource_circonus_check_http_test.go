@@ -188,3 +188,146 @@ resource "circonus_check" "jezebel" {
   tags = "${var.http_check_tags}"
 }
 `
+
+func TestAccCirconusCheckHTTP_steps(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: login flow - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPStepsConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.login", "http.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.0.name", "login"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.0.method", "POST"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.0.capture.0.name", "token"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.0.capture.0.json_path", "$.access_token"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.1.name", "profile"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.1.headers.Authorization", "Bearer {{token}}"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.1.json_assert.0.path", "$.user.active"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http.0.step.1.json_assert.0.regexp", "^true$"),
+					resource.TestCheckResourceAttr("circonus_check.login", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.login", "metric.0.name", "login`code"),
+					resource.TestCheckResourceAttr("circonus_check.login", "target", "api.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.login", "type", "http"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.login",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckHTTPStepsConfigFmt = `
+resource "circonus_check" "login" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  http {
+    step {
+      name = "login"
+      method = "POST"
+      url = "https://api.example.com/login"
+      headers = {
+        Content-Type = "application/json",
+      }
+      payload = "{\"user\":\"monitor\",\"password\":\"secret\"}"
+      code = "^200$"
+
+      capture {
+        name = "token"
+        json_path = "$.access_token"
+      }
+    }
+
+    step {
+      name = "profile"
+      url = "https://api.example.com/me"
+      headers = {
+        Authorization = "Bearer {{token}}",
+      }
+
+      json_assert {
+        path = "$.user.active"
+        regexp = "^true$"
+      }
+    }
+  }
+
+  metric {
+    name = "login` + "`" + `code"
+    type = "text"
+  }
+
+  tags = [ "lifecycle:unittest" ]
+}
+`
+
+func TestAccCirconusCheckHTTP_migrateFromICMPPing(t *testing.T) {
+	checkName := fmt.Sprintf("Migrated check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPMigrateConfigFmt, checkName, testAccBroker1, `
+  icmp_ping {
+    count = 5
+  }
+`, "average"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.migrated", "type", "ping_icmp"),
+					resource.TestCheckResourceAttr("circonus_check.migrated", "migrate", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPMigrateConfigFmt, checkName, testAccBroker1, `
+  http {
+    url = "https://www.circonus.com/"
+  }
+`, "duration"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.migrated", "type", "http"),
+					resource.TestCheckResourceAttr("circonus_check.migrated", "checks.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.migrated", "migration_plan.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckHTTPMigrateConfigFmt = `
+resource "circonus_check" "migrated" {
+  active = true
+  name = "%s"
+  period = "60s"
+  migrate = true
+  target = "www.circonus.com"
+
+  collector {
+    id = "%s"
+  }
+%s
+  metric {
+    name = "%s"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
@@ -2,24 +2,44 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.httptrap check type.
+	checkHTTPTrapAttr                     = "httptrap"
+	apiCheckTypeHTTPTrapAttr apiCheckType = "httptrap"
+
 	// circonus_check.httptrap.* resource attribute names.
-	checkHTTPTrapAsyncMetricsAttr = "async_metrics"
-	checkHTTPTrapSecretAttr       = "secret"
+	checkHTTPTrapAsyncMetricsAttr   = "async_metrics"
+	checkHTTPTrapGenerateSecretAttr = "generate_secret"
+	checkHTTPTrapSecretAttr         = "secret"
 )
 
 var checkHTTPTrapDescriptions = attrDescrs{
-	checkHTTPTrapAsyncMetricsAttr: "Specify whether httptrap metrics are logged immediately or held until the status message is emitted",
-	checkHTTPTrapSecretAttr:       "",
+	checkHTTPTrapAsyncMetricsAttr:   "Specify whether httptrap metrics are logged immediately or held until the status message is emitted",
+	checkHTTPTrapGenerateSecretAttr: "Generate a random secret when secret is not set",
+	checkHTTPTrapSecretAttr:         "The secret metrics must be submitted with",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeHTTPTrapAttr,
+		attr:        checkHTTPTrapAttr,
+		description: "HTTP Trap check configuration",
+		schema:      schemaCheckHTTPTrap,
+		toAPI:       checkConfigToAPIHTTPTrap,
+		toState:     checkAPIToStateHTTPTrap,
+
+		submissionURL: collectorModuleSubmissionURL(string(apiCheckTypeHTTPTrapAttr)),
+	})
 }
 
 var schemaCheckHTTPTrap = &schema.Schema{
@@ -35,11 +55,20 @@ var schemaCheckHTTPTrap = &schema.Schema{
 				Optional: true,
 				Default:  defaultCheckHTTPTrapAsync,
 			},
+			checkHTTPTrapGenerateSecretAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			checkHTTPTrapSecretAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkHTTPTrapSecretAttr, `^[a-zA-Z0-9_]+$`),
+				// A generated secret is kept once it has been stored.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return new == "" && old != "" && checkHTTPTrapGeneratesSecret(d)
+				},
 			},
 		}),
 	},
@@ -47,7 +76,7 @@ var schemaCheckHTTPTrap = &schema.Schema{
 
 // checkAPIToStateHTTPTrap reads the Config data out of circonusCheck.CheckBundle into
 // the statefile.
-func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateHTTPTrap(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	httpTrapConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -64,7 +93,7 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 			case "false", "off":
 				httpTrapConfig[string(attrName)] = false
 			default:
-				log.Printf("PROVIDER BUG: unsupported value %q returned in key %q", s, apiKey)
+				tflog.Error(ctx, "PROVIDER BUG: unsupported value returned in API config", map[string]interface{}{"config_key": string(apiKey), "value": s})
 			}
 		}
 
@@ -82,6 +111,9 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 	saveBoolConfigToState(config.AsyncMetrics, checkHTTPTrapAsyncMetricsAttr)
 	saveStringConfigToState(config.Secret, checkHTTPTrapSecretAttr)
 
+	// generate_secret only exists in the configuration, keep its value.
+	httpTrapConfig[string(checkHTTPTrapGenerateSecretAttr)] = checkHTTPTrapGeneratesSecret(d)
+
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
 		config.SubmissionURL:    {},
@@ -93,7 +125,7 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -125,13 +157,19 @@ func hashCheckHTTPTrap(v interface{}) int {
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
 	writeBool(checkHTTPTrapAsyncMetricsAttr)
-	writeString(checkHTTPTrapSecretAttr)
+	writeBool(checkHTTPTrapGenerateSecretAttr)
+
+	// A generated secret is not part of the configuration, so it must not
+	// change the hash.
+	if v, ok := m[string(checkHTTPTrapGenerateSecretAttr)]; !ok || !v.(bool) {
+		writeString(checkHTTPTrapSecretAttr)
+	}
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeHTTPTrapAttr)
 
 	// Iterate over all `httptrap` attributes, even though we have a max of 1 in the
@@ -149,7 +187,33 @@ func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error { //nolin
 		if v, found := httpTrapConfig[checkHTTPTrapSecretAttr]; found {
 			c.Config[config.Secret] = v.(string)
 		}
+
+		if v, found := httpTrapConfig[checkHTTPTrapGenerateSecretAttr]; found && v.(bool) && c.Config[config.Secret] == "" {
+			secret, err := newCheckSecret()
+			if err != nil {
+				return err
+			}
+			c.Config[config.Secret] = secret
+		}
 	}
 
 	return nil
 }
+
+// checkHTTPTrapGeneratesSecret reports whether the httptrap block has
+// generate_secret enabled.
+func checkHTTPTrapGeneratesSecret(d *schema.ResourceData) bool {
+	v, found := d.GetOk(checkHTTPTrapAttr)
+	if !found {
+		return false
+	}
+
+	for _, mapRaw := range v.(*schema.Set).List() {
+		httpTrapConfig := newInterfaceMap(mapRaw)
+		if generate, found := httpTrapConfig[checkHTTPTrapGenerateSecretAttr]; found && generate.(bool) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -102,3 +103,156 @@ resource "circonus_check" "consul" {
   target = "${var.consul_hostname}"
 }
 `
+
+func TestAccCirconusCheckHTTPTrap_generateSecret(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: generated secret httptrap check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPTrapGenerateSecretConfigFmt,
+					checkName,
+					testAccBroker3,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.app", "httptrap.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.app", "httptrap.0.generate_secret", "true"),
+					resource.TestMatchResourceAttr("circonus_check.app", "httptrap.0.secret", regexp.MustCompile(`^[0-9a-f]{32}$`)),
+					resource.TestMatchResourceAttr("circonus_check.app", "submission_url", regexp.MustCompile(`^https://.+/module/httptrap/.+$`)),
+					resource.TestCheckResourceAttr("circonus_check.app", "type", "httptrap"),
+				),
+			},
+			{
+				// A second plan must not replace the generated secret.
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPTrapGenerateSecretConfigFmt,
+					checkName,
+					testAccBroker3,
+				),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckHTTPTrapGenerateSecretConfigFmt = `
+resource "circonus_check" "app" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  httptrap {
+    generate_secret = true
+  }
+
+  metric_filter {
+    type    = "allow"
+    regex   = ".*"
+    comment = "Allow all submitted metrics"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "app.example.com"
+}
+`
+
+func TestAccCirconusCheckHTTPTrap_streamTags(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: stream tags httptrap check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPTrapStreamTagsConfigFmt,
+					checkName,
+					testAccBroker3,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.#", "3"),
+
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.0.name", "requests"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.0.stream_tags.#", "3"),
+					resource.TestCheckTypeSetElemAttr("circonus_check.app", "metric.0.stream_tags.*", "env:prod"),
+					resource.TestCheckTypeSetElemAttr("circonus_check.app", "metric.0.stream_tags.*", "service:api,web"),
+					resource.TestCheckTypeSetElemAttr("circonus_check.app", "metric.0.stream_tags.*", "service:worker"),
+
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.1.name", "errors|ST[service:api,env:prod]"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.1.stream_tags.#", "0"),
+
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.name", "latency"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.type", "histogram"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.histogram.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.histogram.0.buckets.#", "3"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.histogram.0.buckets.2", "100"),
+					resource.TestCheckResourceAttr("circonus_check.app", "metric.2.histogram.0.unit", "ms"),
+				),
+			},
+			{
+				// Stream tags written in a different order must not cause a diff.
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPTrapStreamTagsConfigFmt,
+					checkName,
+					testAccBroker3,
+				),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "circonus_check.app",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"metric.1.name",
+					"metric.1.stream_tags",
+					"metric.2.histogram.0.buckets",
+					"timeouts",
+				},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckHTTPTrapStreamTagsConfigFmt = `
+resource "circonus_check" "app" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  httptrap {
+    secret = "12345"
+  }
+
+  metric {
+    name = "requests"
+    type = "numeric"
+    stream_tags = [ "service:worker", "service:api,web", "env:prod" ]
+  }
+
+  metric {
+    name = "errors|ST[service:api,env:prod]"
+    type = "numeric"
+  }
+
+  metric {
+    name = "latency"
+    type = "histogram"
+    histogram {
+      unit    = "ms"
+      buckets = [ 1, 10, 100 ]
+    }
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "app.example.com"
+}
+`
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -12,6 +13,10 @@ import (
 )
 
 const (
+	// circonus_check.icmp_ping check type.
+	checkICMPPingAttr                     = "icmp_ping"
+	apiCheckTypeICMPPingAttr apiCheckType = "ping_icmp"
+
 	// circonus_check.icmp_ping.* resource attribute names.
 	checkICMPPingAvailabilityAttr = "availability"
 	checkICMPPingCountAttr        = "count"
@@ -24,6 +29,17 @@ var checkICMPPingDescriptions = attrDescrs{
 	checkICMPPingIntervalAttr:     "The number of milliseconds between ICMP requests.",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeICMPPingAttr,
+		attr:        checkICMPPingAttr,
+		description: "ICMP ping check configuration",
+		schema:      schemaCheckICMPPing,
+		toAPI:       checkConfigToAPIICMPPing,
+		toState:     checkAPIToStateICMPPing,
+	})
+}
+
 var schemaCheckICMPPing = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -65,7 +81,7 @@ var schemaCheckICMPPing = &schema.Schema{
 
 // checkAPIToStateICMPPing reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateICMPPing(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateICMPPing(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	icmpPingConfig := make(map[string]interface{}, len(c.Config))
 
 	availNeeded, err := strconv.ParseFloat(c.Config[config.AvailNeeded], 64)
@@ -130,7 +146,7 @@ func hashCheckICMPPing(v interface{}) int {
 }
 
 func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeICMPPing)
+	c.Type = string(apiCheckTypeICMPPingAttr)
 
 	// Iterate over all `icmp_ping` attributes, even though we have a max of 1 in
 	// the schema.
@@ -68,6 +68,56 @@ func TestAccCirconusCheckICMPPing_basic(t *testing.T) {
 	})
 }
 
+func TestAccCirconusCheckICMPPing_collectorSelector(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - selected collectors - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckICMPPingSelectorConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "collector_selector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "collector_selector.0.type", "circonus"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "collector_selector.0.min_count", "2"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "collector.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "check_by_collector.%", "2"),
+				),
+			},
+			{
+				// The collectors selected must not change from one plan to the next.
+				Config:   fmt.Sprintf(testAccCirconusCheckICMPPingSelectorConfigFmt, checkName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccCirconusCheckICMPPing_waitForFirstRun(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - wait for first run - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckICMPPingWaitConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "wait_for_first_run", "true"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "check_status.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "check_status.0.collector", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "check_status.0.active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "check_status.0.error", ""),
+					resource.TestMatchResourceAttr("circonus_check.loopback_latency", "check_status.0.last_run", regexp.MustCompile(`^[1-9][0-9]*$`)),
+				),
+			},
+		},
+	})
+}
+
 const testAccCirconusCheckICMPPingConfigFmt = `
 variable "test_tags" {
   type = list(string)
@@ -121,3 +171,57 @@ resource "circonus_check" "loopback_latency" {
   target = "api.circonus.com"
 }
 `
+
+const testAccCirconusCheckICMPPingSelectorConfigFmt = `
+resource "circonus_check" "loopback_latency" {
+  active = true
+  name = "%s"
+  period = "300s"
+  target = "api.circonus.com"
+
+  collector_selector {
+    type = "circonus"
+    min_count = 2
+  }
+
+  icmp_ping {
+    availability = "100.0"
+    count = 5
+    interval = "500ms"
+  }
+
+  metric {
+    name = "available"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
+
+const testAccCirconusCheckICMPPingWaitConfigFmt = `
+resource "circonus_check" "loopback_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+  target = "api.circonus.com"
+  wait_for_first_run = true
+
+  collector {
+    id = "%s"
+  }
+
+  icmp_ping {
+    availability = "100.0"
+    count = 5
+    interval = "500ms"
+  }
+
+  metric {
+    name = "available"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
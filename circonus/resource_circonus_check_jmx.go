@@ -2,18 +2,23 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.jmx check type.
+	checkJMXAttr                     = "jmx"
+	apiCheckTypeJMXAttr apiCheckType = "jmx"
+
 	// circonus_check.jmx.* resource attribute names.
 	checkJMXMBeanDomainsAttr    = "mbean_domains"
 	checkJMXMBeanPropertiesAttr = "mbean_properties"
@@ -34,6 +39,17 @@ var checkJMXDescriptions = attrDescrs{
 	checkJMXUsernameAttr:        "JMX username",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeJMXAttr,
+		attr:        checkJMXAttr,
+		description: "JMX check configuration",
+		schema:      schemaCheckJMX,
+		toAPI:       checkConfigToAPIJMX,
+		toState:     checkAPIToStateJMX,
+	})
+}
+
 var schemaCheckJMX = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -104,7 +120,7 @@ var schemaCheckJMX = &schema.Schema{
 
 // checkAPIToStateJMX reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateJMX(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateJMX(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	jmxConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -117,7 +133,7 @@ func checkAPIToStateJMX(c *circonusCheck, d *schema.ResourceData) error {
 		if v, ok := c.Config[apiKey]; ok {
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			jmxConfig[string(attrName)] = int(i)
@@ -143,10 +159,9 @@ func checkAPIToStateJMX(c *circonusCheck, d *schema.ResourceData) error {
 	l := make([]interface{}, 0, 3)
 	// deal with config.MBeanDomains into a list
 	if v, ok := c.Config[config.MbeanDomains]; ok {
-		log.Printf("Domains: %s", v)
+		tflog.Trace(ctx, "Parsing JMX MBean domains", map[string]interface{}{"mbean_domains": v})
 		ll := strings.Split(v, " ")
 		for _, i := range ll {
-			log.Printf("piece: %s", i)
 			l = append(l, i)
 		}
 
@@ -182,7 +197,7 @@ func checkAPIToStateJMX(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -246,7 +261,7 @@ func hashCheckJMX(v interface{}) int {
 }
 
 func checkConfigToAPIJMX(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeJMX)
+	c.Type = string(apiCheckTypeJMXAttr)
 
 	// Iterate over all `tcp` attributes, even though we have a max of 1 in the
 	// schema.
@@ -2,8 +2,9 @@ package circonus
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
 	"sort"
 	"strconv"
@@ -11,10 +12,15 @@ import (
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.json check type.
+	checkJSONAttr                     = "json"
+	apiCheckTypeJSONAttr apiCheckType = "json"
+
 	// circonus_check.json.* resource attribute names.
 	checkJSONAuthMethodAttr   = "auth_method"
 	checkJSONAuthPasswordAttr = "auth_password"
@@ -22,6 +28,7 @@ const (
 	checkJSONCAChainAttr      = "ca_chain"
 	checkJSONCertFileAttr     = "certificate_file"
 	checkJSONCiphersAttr      = "ciphers"
+	checkJSONExtractAttr      = "extract"
 	checkJSONHeadersAttr      = "headers"
 	checkJSONKeyFileAttr      = "key_file"
 	checkJSONMethodAttr       = "method"
@@ -30,8 +37,30 @@ const (
 	checkJSONReadLimitAttr    = "read_limit"
 	checkJSONURLAttr          = "url"
 	checkJSONVersionAttr      = "version"
+
+	// circonus_check.json.extract.* resource attribute names.
+	checkJSONExtractNameAttr = "name"
+	checkJSONExtractPathAttr = "path"
+	checkJSONExtractTypeAttr = "type"
+
+	// The json module options holding the JSON encoded extract and
+	// json_assert blocks.  The module has no documented option for JSONPath
+	// selection, so these names are the provider's and assume a broker that
+	// reads them; see the json section of the check docs.  When extracts are
+	// set only the values they select become metrics, instead of every key of
+	// the response.
+	apiJSONExtract = config.Key("json_extract")
+	apiJSONAsserts = config.Key("json_asserts")
+
+	defaultCheckJSONExtractType = "numeric"
 )
 
+var validCheckJSONExtractTypes = validStringValues{
+	`histogram`,
+	`numeric`,
+	`text`,
+}
+
 var checkJSONDescriptions = attrDescrs{
 	checkJSONAuthMethodAttr:   "The HTTP Authentication method",
 	checkJSONAuthPasswordAttr: "The HTTP Authentication user password",
@@ -39,6 +68,8 @@ var checkJSONDescriptions = attrDescrs{
 	checkJSONCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
 	checkJSONCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS-enabled checks)",
 	checkJSONCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for HTTPS checks)",
+	checkJSONExtractAttr:      "A JSONPath expression selecting a value to collect as a metric.  When set, only the values selected become metrics",
+	checkJSONAssertAttr:       "A JSONPath expression that must be found in the response, otherwise the check is marked bad",
 	checkJSONHeadersAttr:      "Map of HTTP Headers to send along with HTTP Requests",
 	checkJSONKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks)",
 	checkJSONMethodAttr:       "The HTTP method to use",
@@ -49,6 +80,30 @@ var checkJSONDescriptions = attrDescrs{
 	checkJSONVersionAttr:      "Sets the HTTP version for the check to use",
 }
 
+var checkJSONExtractDescriptions = attrDescrs{
+	checkJSONExtractNameAttr: "The name of the metric",
+	checkJSONExtractPathAttr: "The JSONPath expression selecting the value, e.g. $.stats.requests",
+	checkJSONExtractTypeAttr: "The type of the metric: numeric, text or histogram",
+}
+
+// checkJSONExtract is a value selected from the response to become a metric.
+type checkJSONExtract struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeJSONAttr,
+		attr:        checkJSONAttr,
+		description: "JSON check configuration",
+		schema:      schemaCheckJSON,
+		toAPI:       checkConfigToAPIJSON,
+		toState:     checkAPIToStateJSON,
+	})
+}
+
 var schemaCheckJSON = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -88,6 +143,31 @@ var schemaCheckJSON = &schema.Schema{
 				Optional:     true,
 				ValidateFunc: validateRegexp(checkJSONCiphersAttr, `.+`),
 			},
+			checkJSONExtractAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkJSONExtractDescriptions, map[schemaAttr]*schema.Schema{
+						checkJSONExtractNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkJSONExtractNameAttr, `^\S+$`),
+						},
+						checkJSONExtractPathAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateJSONPath(checkJSONExtractPathAttr),
+						},
+						checkJSONExtractTypeAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultCheckJSONExtractType,
+							ValidateFunc: validateStringIn(checkJSONExtractTypeAttr, validCheckJSONExtractTypes),
+						},
+					}),
+				},
+			},
+			checkJSONAssertAttr: schemaCheckJSONAssert(),
 			checkJSONHeadersAttr: {
 				Type:         schema.TypeMap,
 				Elem:         schema.TypeString,
@@ -145,7 +225,7 @@ var schemaCheckJSON = &schema.Schema{
 
 // checkAPIToStateJSON reads the Config data out of circonusCheck.CheckBundle into
 // the statefile.
-func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateJSON(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	jsonConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -166,7 +246,7 @@ func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
 		if s, ok := c.Config[apiKey]; ok && s != "0" {
 			i, err := strconv.ParseInt(s, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			jsonConfig[string(attrName)] = int(i)
@@ -182,6 +262,24 @@ func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.CertFile, checkJSONCertFileAttr)
 	saveStringConfigToState(config.Ciphers, checkJSONCiphersAttr)
 
+	if v, ok := c.Config[apiJSONExtract]; ok {
+		var extracts []checkJSONExtract
+		if err := json.Unmarshal([]byte(v), &extracts); err != nil {
+			return fmt.Errorf("unable to decode %s config %q: %w", apiJSONExtract, v, err)
+		}
+		jsonConfig[string(checkJSONExtractAttr)] = checkJSONExtractsToList(extracts)
+	}
+	delete(swamp, apiJSONExtract)
+
+	if v, ok := c.Config[apiJSONAsserts]; ok {
+		var asserts []checkJSONPathAssert
+		if err := json.Unmarshal([]byte(v), &asserts); err != nil {
+			return fmt.Errorf("unable to decode %s config %q: %w", apiJSONAsserts, v, err)
+		}
+		jsonConfig[string(checkJSONAssertAttr)] = checkJSONAssertsToList(asserts)
+	}
+	delete(swamp, apiJSONAsserts)
+
 	headers := make(map[string]interface{}, len(c.Config))
 	headerPrefixLen := len(config.HeaderPrefix)
 	for k, v := range c.Config {
@@ -255,6 +353,18 @@ func checkJSONConfigChecksum(v interface{}) int {
 	writeString(checkJSONCertFileAttr)
 	writeString(checkJSONCiphersAttr)
 
+	if extractsRaw, ok := m[string(checkJSONExtractAttr)].([]interface{}); ok && len(extractsRaw) > 0 {
+		if buf, err := json.Marshal(checkJSONExtractsFromList(extractsRaw)); err == nil {
+			b.Write(buf)
+		}
+	}
+
+	if assertsRaw, ok := m[string(checkJSONAssertAttr)].([]interface{}); ok && len(assertsRaw) > 0 {
+		if buf, err := json.Marshal(checkJSONAssertsFromList(assertsRaw)); err == nil {
+			b.Write(buf)
+		}
+	}
+
 	if headersRaw, ok := m[string(checkJSONHeadersAttr)]; ok {
 		headerMap := headersRaw.(map[string]interface{})
 		headers := make([]string, 0, len(headerMap))
@@ -281,8 +391,8 @@ func checkJSONConfigChecksum(v interface{}) int {
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeJSON)
+func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeJSONAttr)
 
 	// Iterate over all `json` attributes, even though we have a max of 1 in the
 	// schema.
@@ -313,6 +423,39 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 			c.Config[config.Ciphers] = v.(string)
 		}
 
+		if extractsRaw, _ := jsonConfig[checkJSONExtractAttr].([]interface{}); len(extractsRaw) > 0 {
+			extracts := checkJSONExtractsFromList(extractsRaw)
+			names := make(map[string]struct{}, len(extracts))
+			for _, e := range extracts {
+				if err := parseJSONPath(e.Path); err != nil {
+					return attrErrorf(attrPath(checkJSONAttr), "%v", err)
+				}
+				if _, found := names[e.Name]; found {
+					return attrErrorf(attrPath(checkJSONAttr), "duplicate %s name %q", checkJSONExtractAttr, e.Name)
+				}
+				names[e.Name] = struct{}{}
+			}
+
+			buf, err := json.Marshal(extracts)
+			if err != nil {
+				return fmt.Errorf("unable to encode %s: %w", checkJSONExtractAttr, err)
+			}
+			c.Config[apiJSONExtract] = string(buf)
+		}
+
+		if assertsRaw, _ := jsonConfig[checkJSONAssertAttr].([]interface{}); len(assertsRaw) > 0 {
+			asserts := checkJSONAssertsFromList(assertsRaw)
+			if err := validateJSONPathAsserts(attrPath(checkJSONAttr), asserts); err != nil {
+				return err
+			}
+
+			buf, err := json.Marshal(asserts)
+			if err != nil {
+				return fmt.Errorf("unable to encode %s: %w", checkJSONAssertAttr, err)
+			}
+			c.Config[apiJSONAsserts] = string(buf)
+		}
+
 		for k, v := range jsonConfig.CollectMap(checkJSONHeadersAttr) {
 			h := config.HeaderPrefix + config.Key(k)
 			c.Config[h] = v
@@ -365,3 +508,32 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 
 	return nil
 }
+
+// checkJSONExtractsFromList converts a list of extract blocks.
+func checkJSONExtractsFromList(l []interface{}) []checkJSONExtract {
+	extracts := make([]checkJSONExtract, 0, len(l))
+	for _, extractRaw := range l {
+		extractConfig := newInterfaceMap(extractRaw)
+		var e checkJSONExtract
+		e.Name, _ = extractConfig[checkJSONExtractNameAttr].(string)
+		e.Path, _ = extractConfig[checkJSONExtractPathAttr].(string)
+		e.Type, _ = extractConfig[checkJSONExtractTypeAttr].(string)
+		extracts = append(extracts, e)
+	}
+
+	return extracts
+}
+
+// checkJSONExtractsToList is the inverse of checkJSONExtractsFromList.
+func checkJSONExtractsToList(extracts []checkJSONExtract) []interface{} {
+	l := make([]interface{}, 0, len(extracts))
+	for _, e := range extracts {
+		l = append(l, map[string]interface{}{
+			string(checkJSONExtractNameAttr): e.Name,
+			string(checkJSONExtractPathAttr): e.Path,
+			string(checkJSONExtractTypeAttr): e.Type,
+		})
+	}
+
+	return l
+}
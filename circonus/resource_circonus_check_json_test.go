@@ -82,6 +82,109 @@ func TestAccCirconusCheckJSON_basic(t *testing.T) {
 	})
 }
 
+func TestAccCirconusCheckJSON_extract(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckJSONConfigExtract, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.0.name", "limit"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.0.path", "$._usage[0]._limit"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.0.type", "numeric"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.1.name", "description"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extract.1.type", "text"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.json_assert.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.json_assert.0.path", "$._cid"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.json_assert.0.regexp", "^/account/"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.json_assert.1.path", "$._usage"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.json_assert.1.regexp", ""),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.usage",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+func Test_CheckConfigToAPIJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		extract  []interface{}
+		asserts  []interface{}
+		expected map[config.Key]string
+		fail     bool
+	}{
+		{
+			name: "extract and assert",
+			extract: []interface{}{
+				map[string]interface{}{"name": "requests", "path": "$.stats.requests", "type": "numeric"},
+				map[string]interface{}{"name": "version", "path": "$.version", "type": "text"},
+			},
+			asserts: []interface{}{
+				map[string]interface{}{"path": "$.status", "regexp": "^ok$"},
+			},
+			expected: map[config.Key]string{
+				apiJSONExtract: `[{"name":"requests","path":"$.stats.requests","type":"numeric"},{"name":"version","path":"$.version","type":"text"}]`,
+				apiJSONAsserts: `[{"path":"$.status","regexp":"^ok$"}]`,
+			},
+		},
+		{
+			name: "duplicate extract name",
+			extract: []interface{}{
+				map[string]interface{}{"name": "requests", "path": "$.a", "type": "numeric"},
+				map[string]interface{}{"name": "requests", "path": "$.b", "type": "numeric"},
+			},
+			fail: true,
+		},
+		{
+			name:    "invalid extract path",
+			extract: []interface{}{map[string]interface{}{"name": "requests", "path": "stats.requests", "type": "numeric"}},
+			fail:    true,
+		},
+		{
+			name:    "invalid assert regexp",
+			asserts: []interface{}{map[string]interface{}{"path": "$.status", "regexp": "(ok"}},
+			fail:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPIJSON(&c, interfaceList{map[string]interface{}{
+				string(checkJSONExtractAttr): test.extract,
+				string(checkJSONAssertAttr):  test.asserts,
+				string(checkJSONURLAttr):     "https://api.example.com/stats",
+			}})
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for k, v := range test.expected {
+				if c.Config[k] != v {
+					t.Errorf("config key %q: expected %q, got %q", k, v, c.Config[k])
+				}
+			}
+		})
+	}
+}
+
 const testAccCirconusCheckJSONConfig1 = `
 
 resource "circonus_metric" "limit" {
@@ -178,3 +281,51 @@ resource "circonus_check" "usage" {
   tags = [ "source:circonus", "lifecycle:unittest" ]
 }
 `
+
+const testAccCirconusCheckJSONConfigExtract = `
+resource "circonus_check" "usage" {
+  active = true
+  name = "Terraform test: api.circonus.com extracted usage check"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  json {
+    url = "https://api.circonus.com/account/current"
+    headers = {
+      Accept                = "application/json",
+      X-Circonus-App-Name   = "TerraformCheck",
+      X-Circonus-Auth-Token = "<env 'CIRCONUS_API_TOKEN'>",
+    }
+
+    extract {
+      name = "limit"
+      path = "$._usage[0]._limit"
+    }
+
+    extract {
+      name = "description"
+      path = "$.description"
+      type = "text"
+    }
+
+    json_assert {
+      path   = "$._cid"
+      regexp = "^/account/"
+    }
+
+    json_assert {
+      path = "$._usage"
+    }
+  }
+
+  metric {
+    name = "limit"
+    type = "numeric"
+  }
+
+  tags = [ "source:circonus", "lifecycle:unittest" ]
+}
+`
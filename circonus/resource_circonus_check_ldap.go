@@ -0,0 +1,313 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.ldap check type.
+	checkLDAPAttr                     = "ldap"
+	apiCheckTypeLDAPAttr apiCheckType = "ldap"
+
+	// circonus_check.ldap.* resource attribute names.
+	checkLDAPAuthTypeAttr   = "auth_type"
+	checkLDAPBindDNAttr     = "bind_dn"
+	checkLDAPCAChainAttr    = "ca_chain"
+	checkLDAPCertFileAttr   = "certificate_file"
+	checkLDAPCiphersAttr    = "ciphers"
+	checkLDAPFilterAttr     = "filter"
+	checkLDAPKeyFileAttr    = "key_file"
+	checkLDAPPasswordAttr   = "password"
+	checkLDAPPortAttr       = "port"
+	checkLDAPSearchBaseAttr = "search_base"
+	checkLDAPStartTLSAttr   = "starttls"
+	checkLDAPTLSAttr        = "tls"
+
+	// apiLDAPFilter is the broker's ldap module option for the search filter.
+	apiLDAPFilter = config.Key("filter")
+)
+
+var checkLDAPDescriptions = attrDescrs{
+	checkLDAPAuthTypeAttr:   "The LDAP bind authentication type",
+	checkLDAPBindDNAttr:     "The distinguished name to bind as",
+	checkLDAPCAChainAttr:    "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
+	checkLDAPCertFileAttr:   "A path to a file containing the client certificate that will be presented to the remote server (for TLS checks)",
+	checkLDAPCiphersAttr:    "A list of ciphers to be used when establishing a TLS connection",
+	checkLDAPFilterAttr:     "The LDAP search filter",
+	checkLDAPKeyFileAttr:    "A path to a file containing key to be used in conjunction with the client certificate (for TLS checks)",
+	checkLDAPPasswordAttr:   "The password used to bind",
+	checkLDAPPortAttr:       "The port the LDAP server is listening on",
+	checkLDAPSearchBaseAttr: "The distinguished name to search from",
+	checkLDAPStartTLSAttr:   "Upgrade the connection to TLS with STARTTLS",
+	checkLDAPTLSAttr:        "Connect using TLS (LDAPS)",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeLDAPAttr,
+		attr:        checkLDAPAttr,
+		description: "LDAP check configuration",
+		schema:      schemaCheckLDAP,
+		toAPI:       checkConfigToAPILDAP,
+		toState:     checkAPIToStateLDAP,
+		validate:    validateCheckLDAP,
+	})
+}
+
+var schemaCheckLDAP = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckLDAP,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkLDAPDescriptions, map[schemaAttr]*schema.Schema{
+			checkLDAPAuthTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultCheckLDAPAuthType,
+				ValidateFunc: validateRegexp(checkLDAPAuthTypeAttr, `^\S+$`),
+			},
+			checkLDAPBindDNAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPBindDNAttr, `.+`),
+			},
+			checkLDAPCAChainAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPCAChainAttr, `.+`),
+			},
+			checkLDAPCertFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPCertFileAttr, `.+`),
+			},
+			checkLDAPCiphersAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPCiphersAttr, `.+`),
+			},
+			checkLDAPFilterAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPFilterAttr, `^\(.+\)$`),
+			},
+			checkLDAPKeyFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPKeyFileAttr, `.+`),
+			},
+			checkLDAPPasswordAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			checkLDAPPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  defaultCheckLDAPPort,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkLDAPPortAttr, 0),
+					validateIntMax(checkLDAPPortAttr, 65535),
+				),
+			},
+			checkLDAPSearchBaseAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPSearchBaseAttr, `.+`),
+			},
+			checkLDAPStartTLSAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkLDAPTLSAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		}),
+	},
+}
+
+// checkAPIToStateLDAP reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateLDAP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	ldapConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveBoolConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok {
+			b, err := strconv.ParseBool(strings.ToLower(s))
+			if err != nil {
+				tflog.Error(ctx, "PROVIDER BUG: unsupported boolean in API config", map[string]interface{}{"config_key": string(apiKey), "value": s})
+				return
+			}
+			ldapConfig[string(attrName)] = b
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveIntConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
+				return
+			}
+			ldapConfig[string(attrName)] = int(i)
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			ldapConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.AuthType, checkLDAPAuthTypeAttr)
+	saveStringConfigToState(config.SecurityPrincipal, checkLDAPBindDNAttr)
+	saveStringConfigToState(config.CAChain, checkLDAPCAChainAttr)
+	saveStringConfigToState(config.CertFile, checkLDAPCertFileAttr)
+	saveStringConfigToState(config.Ciphers, checkLDAPCiphersAttr)
+	saveStringConfigToState(apiLDAPFilter, checkLDAPFilterAttr)
+	saveStringConfigToState(config.KeyFile, checkLDAPKeyFileAttr)
+	saveStringConfigToState(config.Password, checkLDAPPasswordAttr)
+	saveIntConfigToState(config.Port, checkLDAPPortAttr)
+	saveStringConfigToState(config.DN, checkLDAPSearchBaseAttr)
+	saveBoolConfigToState(config.StartTLS, checkLDAPStartTLSAttr)
+	saveBoolConfigToState(config.UseSSL, checkLDAPTLSAttr)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkLDAPAttr, schema.NewSet(hashCheckLDAP, []interface{}{ldapConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkLDAPAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckLDAP creates a stable hash of the normalized values.
+func hashCheckLDAP(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkLDAPAuthTypeAttr)
+	writeString(checkLDAPBindDNAttr)
+	writeString(checkLDAPCAChainAttr)
+	writeString(checkLDAPCertFileAttr)
+	writeString(checkLDAPCiphersAttr)
+	writeString(checkLDAPFilterAttr)
+	writeString(checkLDAPKeyFileAttr)
+	writeString(checkLDAPPasswordAttr)
+	writeInt(checkLDAPPortAttr)
+	writeString(checkLDAPSearchBaseAttr)
+	writeBool(checkLDAPStartTLSAttr)
+	writeBool(checkLDAPTLSAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPILDAP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeLDAPAttr)
+
+	// Iterate over all `ldap` attributes, even though we have a max of 1 in the
+	// schema.
+	for _, mapRaw := range l {
+		ldapConfig := newInterfaceMap(mapRaw)
+
+		setString := func(apiKey config.Key, attrName schemaAttr) {
+			if v, found := ldapConfig[string(attrName)]; found && v.(string) != "" {
+				c.Config[apiKey] = v.(string)
+			}
+		}
+
+		setString(config.AuthType, checkLDAPAuthTypeAttr)
+		setString(config.SecurityPrincipal, checkLDAPBindDNAttr)
+		setString(config.CAChain, checkLDAPCAChainAttr)
+		setString(config.CertFile, checkLDAPCertFileAttr)
+		setString(config.Ciphers, checkLDAPCiphersAttr)
+		setString(apiLDAPFilter, checkLDAPFilterAttr)
+		setString(config.KeyFile, checkLDAPKeyFileAttr)
+		setString(config.Password, checkLDAPPasswordAttr)
+		setString(config.DN, checkLDAPSearchBaseAttr)
+
+		if v, found := ldapConfig[checkLDAPPortAttr]; found {
+			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
+		}
+
+		if v, found := ldapConfig[checkLDAPStartTLSAttr]; found {
+			c.Config[config.StartTLS] = fmt.Sprintf("%t", v.(bool))
+		}
+
+		if v, found := ldapConfig[checkLDAPTLSAttr]; found {
+			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+		}
+	}
+
+	return nil
+}
+
+// validateCheckLDAP checks rules specific to ldap checks.
+func validateCheckLDAP(c *circonusCheck) error {
+	if c.Config[config.StartTLS] == "true" && c.Config[config.UseSSL] == "true" {
+		return attrErrorf(attrPath(checkLDAPAttr), "only one of %s or %s may be enabled", checkLDAPStartTLSAttr, checkLDAPTLSAttr)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckLDAP_basic(t *testing.T) {
+	checkName := fmt.Sprintf("LDAP bind - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckLDAPConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.ldap", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "collector.0.id", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.bind_dn", "cn=monitor,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.password", "secret"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.search_base", "dc=example,dc=com"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.filter", "(uid=monitor)"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.port", "636"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.tls", "true"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "ldap.0.starttls", "false"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "target", "ldap.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.ldap", "type", "ldap"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.ldap",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckLDAPConfigFmt = `
+resource "circonus_check" "ldap" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  ldap {
+    bind_dn     = "cn=monitor,dc=example,dc=com"
+    password    = "secret"
+    search_base = "dc=example,dc=com"
+    filter      = "(uid=monitor)"
+    port        = 636
+    tls         = true
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "ldap.example.com"
+}
+`
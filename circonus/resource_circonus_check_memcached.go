@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 
@@ -11,6 +12,10 @@ import (
 )
 
 const (
+	// circonus_check.memcached check type.
+	checkMemcachedAttr                     = "memcached"
+	apiCheckTypeMemcachedAttr apiCheckType = "memcached"
+
 	// circonus_check.memcached.* resource attribute names.
 	checkMemcachedPortAttr = "port"
 )
@@ -19,6 +24,17 @@ var checkMemcachedDescriptions = attrDescrs{
 	checkMemcachedPortAttr: `The port the memcached instance is listenening on, default 11211`,
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeMemcachedAttr,
+		attr:        checkMemcachedAttr,
+		description: "Memcached check configuration",
+		schema:      schemaCheckMemcached,
+		toAPI:       checkConfigToAPIMemcached,
+		toState:     checkAPIToStateMemcached,
+	})
+}
+
 var schemaCheckMemcached = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -42,7 +58,7 @@ var schemaCheckMemcached = &schema.Schema{
 
 // checkAPIToStateMemcached reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateMemcached(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateMemcached(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	memcachedConfig := make(map[string]interface{}, len(c.Config))
 
 	port, err := strconv.ParseInt(c.Config[config.Port], 10, 64)
@@ -80,7 +96,7 @@ func hashCheckMemcached(v interface{}) int {
 }
 
 func checkConfigToAPIMemcached(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeMemcached)
+	c.Type = string(apiCheckTypeMemcachedAttr)
 
 	// Iterate over all `memcached` attributes, even though we have a max of 1 in
 	// the schema.
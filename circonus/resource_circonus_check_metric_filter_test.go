@@ -44,12 +44,24 @@ func TestAccCirconusCheckMetricFilter_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.metric_filter", "period", "300s"),
 					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.#", "3"),
 
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.0.type", "allow"),
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.0.regex", "available"),
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.1.type", "allow"),
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.1.regex", "average"),
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.2.type", "deny"),
-					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.2.regex", ".*"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.metric_filter", "metric_filter.*", map[string]string{
+						"name":     "available",
+						"priority": "10",
+						"type":     "allow",
+						"regex":    "available",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.metric_filter", "metric_filter.*", map[string]string{
+						"name":     "average",
+						"priority": "20",
+						"type":     "allow",
+						"regex":    "average",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.metric_filter", "metric_filter.*", map[string]string{
+						"name":     "deny_all",
+						"priority": "100",
+						"type":     "deny",
+						"regex":    ".*",
+					}),
 
 					resource.TestCheckResourceAttr("circonus_check.metric_filter", "tags.#", "2"),
 					resource.TestCheckResourceAttr("circonus_check.metric_filter", "tags.0", "author:terraform"),
@@ -58,6 +70,30 @@ func TestAccCirconusCheckMetricFilter_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.metric_filter", "type", "ping_icmp"),
 				),
 			},
+			{
+				// A filter inserted ahead of the others only adds that filter.
+				Config: fmt.Sprintf(testAccCirconusCheckMetricFilterInsertConfigFmt,
+					checkName,
+					testAccBroker1,
+					testAccBroker2,
+					target,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.metric_filter", "metric_filter.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.metric_filter", "metric_filter.*", map[string]string{
+						"name":     "maximum",
+						"priority": "5",
+						"type":     "allow",
+						"regex":    "maximum",
+					}),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.metric_filter",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
 		},
 	})
 }
@@ -87,18 +123,85 @@ resource "circonus_check" "metric_filter" {
   }
 
   metric_filter {
+    name = "available"
+    priority = 10
+    type = "allow"
+    regex = "available"
+    comment = "Allow available percentage"
+  }
+
+  metric_filter {
+    name = "average"
+    priority = 20
+    type = "allow"
+    regex = "average"
+    comment = "Allow average latency"
+  }
+
+  metric_filter {
+    name = "deny_all"
+    priority = 100
+    type = "deny"
+    regex = ".*"
+    comment = "Deny everything else"
+  }
+
+  tags = "${var.test_tags}"
+  target = "%s"
+}
+`
+
+const testAccCirconusCheckMetricFilterInsertConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "metric_filter" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "%s"
+  }
+
+  collector {
+    id = "%s"
+  }
+
+  icmp_ping {
+    availability = "100.0"
+    count = 5
+    interval = "500ms"
+  }
+
+  metric_filter {
+    name = "maximum"
+    priority = 5
+    type = "allow"
+    regex = "maximum"
+    comment = "Allow maximum latency"
+  }
+
+  metric_filter {
+    name = "available"
+    priority = 10
     type = "allow"
     regex = "available"
     comment = "Allow available percentage"
   }
 
   metric_filter {
+    name = "average"
+    priority = 20
     type = "allow"
     regex = "average"
     comment = "Allow average latency"
   }
 
   metric_filter {
+    name = "deny_all"
+    priority = 100
     type = "deny"
     regex = ".*"
     comment = "Deny everything else"
@@ -0,0 +1,244 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.mongodb check type.
+	checkMongoDBAttr                     = "mongodb"
+	apiCheckTypeMongoDBAttr apiCheckType = "mongodb"
+
+	// circonus_check.mongodb.* resource attribute names.
+	checkMongoDBAuthDatabaseAttr = "auth_database"
+	checkMongoDBCommandAttr      = "command"
+	checkMongoDBHostAttr         = "host"
+	checkMongoDBNameAttr         = "name"
+	checkMongoDBPasswordAttr     = checkDatabasePasswordAttr
+	checkMongoDBPortAttr         = "port"
+	checkMongoDBTLSAttr          = checkDatabaseTLSAttr
+	checkMongoDBUserAttr         = "user"
+
+	// apiMongoDBAuthDatabase is the broker's mongodb module option for the
+	// database the credentials are authenticated against.
+	apiMongoDBAuthDatabase = config.Key("auth_dbname")
+
+	// apiMongoDBMetricPathSeparator is what the broker joins the keys of the
+	// nested documents a command returns with in the names of its metrics.
+	apiMongoDBMetricPathSeparator = "`"
+)
+
+var checkMongoDBDescriptions = attrDescrs{
+	checkMongoDBAuthDatabaseAttr: "The database the credentials are authenticated against",
+	checkMongoDBCommandAttr:      "The database command to run, e.g. serverStatus or replSetGetStatus",
+	checkMongoDBHostAttr:         "The hostname to connect to",
+	checkMongoDBNameAttr:         "The database to run the command against",
+	checkMongoDBPasswordAttr:     "The password to use",
+	checkMongoDBPortAttr:         "The TCP port number to use to connect on",
+	checkMongoDBTLSAttr:          "Connect using TLS",
+	checkMongoDBUserAttr:         "The username to connect as",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeMongoDBAttr,
+		attr:        checkMongoDBAttr,
+		description: "MongoDB check configuration",
+		schema:      schemaCheckMongoDB,
+		toAPI:       checkConfigToAPIMongoDB,
+		toState:     checkAPIToStateMongoDB,
+		validate:    validateCheckDatabaseTLS(checkMongoDBAttr),
+
+		metricPathSeparator: apiMongoDBMetricPathSeparator,
+	})
+}
+
+var schemaCheckMongoDB = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckMongoDB,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkMongoDBDescriptions, map[schemaAttr]*schema.Schema{
+			checkMongoDBAuthDatabaseAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMongoDBAuthDatabaseAttr, `^[\S]+$`),
+			},
+			checkMongoDBCommandAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultCheckMongoDBCommand,
+				ValidateFunc: validateRegexp(checkMongoDBCommandAttr, `^\w+$`),
+			},
+			checkMongoDBHostAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkMongoDBHostAttr, `^[\S]+$`),
+			},
+			checkMongoDBNameAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMongoDBNameAttr, `^[\S]+$`),
+			},
+			checkMongoDBPasswordAttr: schemaCheckDatabasePassword(),
+			checkMongoDBPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  defaultCheckMongoDBPort,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkMongoDBPortAttr, 1),
+					validateIntMax(checkMongoDBPortAttr, 65535),
+				),
+			},
+			checkMongoDBTLSAttr: schemaCheckDatabaseTLS(),
+			checkMongoDBUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMongoDBUserAttr, `.+`),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateMongoDB reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateMongoDB(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	mongodbConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveIntConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
+				return
+			}
+			mongodbConfig[string(attrName)] = int(i)
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			mongodbConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(apiMongoDBAuthDatabase, checkMongoDBAuthDatabaseAttr)
+	saveStringConfigToState(config.Command, checkMongoDBCommandAttr)
+	mongodbConfig[string(checkMongoDBHostAttr)] = c.Target
+	saveStringConfigToState(config.DBName, checkMongoDBNameAttr)
+	saveIntConfigToState(config.Port, checkMongoDBPortAttr)
+	saveStringConfigToState(config.Username, checkMongoDBUserAttr)
+	checkDatabaseAPIToState(c, mongodbConfig, swamp)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkMongoDBAttr, schema.NewSet(hashCheckMongoDB, []interface{}{mongodbConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkMongoDBAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckMongoDB creates a stable hash of the normalized values.
+func hashCheckMongoDB(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkMongoDBAuthDatabaseAttr)
+	writeString(checkMongoDBCommandAttr)
+	writeString(checkMongoDBHostAttr)
+	writeString(checkMongoDBNameAttr)
+	writeInt(checkMongoDBPortAttr)
+	writeString(checkMongoDBUserAttr)
+	hashCheckDatabase(b, m)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIMongoDB(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeMongoDBAttr)
+
+	// Iterate over all `mongodb` attributes, even though we have a max of 1 in
+	// the schema.
+	for _, mapRaw := range l {
+		mongodbConfig := newInterfaceMap(mapRaw)
+
+		if v, found := mongodbConfig[checkMongoDBAuthDatabaseAttr]; found && v.(string) != "" {
+			c.Config[apiMongoDBAuthDatabase] = v.(string)
+		}
+
+		if v, found := mongodbConfig[checkMongoDBCommandAttr]; found {
+			c.Config[config.Command] = v.(string)
+		}
+
+		if v, found := mongodbConfig[checkMongoDBHostAttr]; found {
+			c.Target = v.(string)
+		}
+
+		if v, found := mongodbConfig[checkMongoDBNameAttr]; found && v.(string) != "" {
+			c.Config[config.DBName] = v.(string)
+		}
+
+		if v, found := mongodbConfig[checkMongoDBPortAttr]; found {
+			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
+		}
+
+		if v, found := mongodbConfig[checkMongoDBUserAttr]; found && v.(string) != "" {
+			c.Config[config.Username] = v.(string)
+		}
+
+		checkDatabaseConfigToAPI(c, mongodbConfig)
+	}
+
+	return nil
+}
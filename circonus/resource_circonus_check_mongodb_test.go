@@ -0,0 +1,157 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_CheckMongoDBMetricPaths(t *testing.T) {
+	metrics := []interface{}{
+		map[string]interface{}{"name": "uptime", "type": "numeric"},
+		map[string]interface{}{"name": "opcounters.insert", "type": "numeric"},
+		map[string]interface{}{"name": "repl.hosts.0", "type": "text", "stream_tags": []interface{}{"host:mongo1.example.com"}},
+	}
+
+	tests := []struct {
+		name     string
+		block    string
+		attrs    map[string]interface{}
+		apiNames []string
+	}{
+		{
+			name:     "mongodb block",
+			block:    "mongodb",
+			attrs:    map[string]interface{}{"host": "mongo.example.com"},
+			apiNames: []string{"uptime", "opcounters`insert", "repl`hosts`0|ST[host:mongo1.example.com]"},
+		},
+		{
+			name:     "raw block",
+			block:    "raw",
+			attrs:    map[string]interface{}{"type": "mongodb", "config": map[string]interface{}{"port": "27017"}},
+			apiNames: []string{"uptime", "opcounters.insert", "repl.hosts.0|ST[host:mongo1.example.com]"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+				"name":      "mongodb",
+				"collector": []interface{}{map[string]interface{}{"id": "/broker/1"}},
+				"metric":    metrics,
+				"target":    "mongo.example.com",
+				test.block:  []interface{}{test.attrs},
+			})
+
+			c := newCheck()
+			if err := c.ParseConfig(d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Metrics) != len(test.apiNames) {
+				t.Fatalf("expected %d metrics, got %d", len(test.apiNames), len(c.Metrics))
+			}
+
+			sep := checkMetricPathSeparator(&c, d)
+			for i, apiName := range test.apiNames {
+				if c.Metrics[i].Name != apiName {
+					t.Errorf("expected metric %d to be named %q, got %q", i, apiName, c.Metrics[i].Name)
+				}
+
+				if sep == "" {
+					continue
+				}
+
+				// The broker's names read back as the configured dotted paths.
+				name := metricPathToState(c.Metrics[i].Name, sep)
+				if want := metricPathToAPI(name, sep); want != apiName {
+					t.Errorf("expected %q to read back as a path that maps to itself, got %q", apiName, want)
+				}
+
+				configured := configuredMetrics(metrics)
+				if _, found := configured[canonicalMetricName(name)]; !found {
+					t.Errorf("expected %q to read back as a configured metric, got %q", apiName, name)
+				}
+			}
+		})
+	}
+}
+
+func TestAccCirconusCheckMongoDB_basic(t *testing.T) {
+	checkName := fmt.Sprintf("MongoDB serverStatus - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckMongoDBConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "collector.0.id", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.auth_database", "admin"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.command", "serverStatus"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.host", "mongo.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.password", "secret"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.port", "27017"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.tls.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.tls.0.verify_mode", "verify-ca"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "mongodb.0.user", "monitor"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "metric.0.name", "opcounters.insert"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "metric.1.name", "repl.setName"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "target", "mongo.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.mongodb", "type", "mongodb"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.mongodb",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckMongoDBConfigFmt = `
+resource "circonus_check" "mongodb" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  mongodb {
+    host          = "mongo.example.com"
+    user          = "monitor"
+    password      = "secret"
+    auth_database = "admin"
+
+    tls {
+      verify_mode = "verify-ca"
+    }
+  }
+
+  metric {
+    name = "opcounters.insert"
+    type = "numeric"
+  }
+
+  metric {
+    name = "repl.setName"
+    type = "text"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,14 +12,36 @@ import (
 )
 
 const (
+	// circonus_check.mysql check type.
+	checkMySQLAttr                     = "mysql"
+	apiCheckTypeMySQLAttr apiCheckType = "mysql"
+
 	// circonus_check.mysql.* resource attribute names.
-	checkMySQLDSNAttr   = "dsn"
-	checkMySQLQueryAttr = "query"
+	checkMySQLDSNAttr        = "dsn"
+	checkMySQLNamedQueryAttr = checkDatabaseNamedQueryAttr
+	checkMySQLPasswordAttr   = checkDatabasePasswordAttr
+	checkMySQLQueryAttr      = "query"
+	checkMySQLTLSAttr        = checkDatabaseTLSAttr
 )
 
 var checkMySQLDescriptions = attrDescrs{
-	checkMySQLDSNAttr:   "The connect DSN for the MySQL instance",
-	checkMySQLQueryAttr: "The SQL to use as the query",
+	checkMySQLDSNAttr:        "The connect DSN for the MySQL instance",
+	checkMySQLNamedQueryAttr: "A named query to run, used instead of query to run several queries",
+	checkMySQLPasswordAttr:   "The password to connect with, kept out of the DSN",
+	checkMySQLQueryAttr:      "The SQL to use as the query",
+	checkMySQLTLSAttr:        "Connect using TLS",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeMySQLAttr,
+		attr:        checkMySQLAttr,
+		description: "MySQL check configuration",
+		schema:      schemaCheckMySQL,
+		toAPI:       checkConfigToAPIMySQL,
+		toState:     checkAPIToStateMySQL,
+		validate:    validateCheckSQL(checkMySQLAttr, checkMySQLQueryAttr),
+	})
 }
 
 var schemaCheckMySQL = &schema.Schema{
@@ -32,25 +55,57 @@ var schemaCheckMySQL = &schema.Schema{
 			checkMySQLDSNAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkMySQLDSNAttr, `^.+$`),
 			},
+			checkMySQLNamedQueryAttr: schemaCheckDatabaseNamedQuery(),
+			checkMySQLPasswordAttr:   schemaCheckDatabasePassword(),
 			checkMySQLQueryAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				StateFunc:    func(v interface{}) string { return strings.TrimSpace(v.(string)) },
 				ValidateFunc: validateRegexp(checkMySQLQueryAttr, `.+`),
 			},
+			checkMySQLTLSAttr: schemaCheckDatabaseTLS(),
 		}),
 	},
 }
 
 // checkAPIToStateMySQL reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateMySQL(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateMySQL(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	MySQLConfig := make(map[string]interface{}, len(c.Config))
 
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
 	MySQLConfig[string(checkMySQLDSNAttr)] = c.Config[config.DSN]
-	MySQLConfig[string(checkMySQLQueryAttr)] = c.Config[config.SQL]
+	delete(swamp, config.DSN)
+
+	if v, found := c.Config[config.SQL]; found {
+		MySQLConfig[string(checkMySQLQueryAttr)] = v
+	}
+	delete(swamp, config.SQL)
+
+	checkDatabaseAPIToState(c, MySQLConfig, swamp)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %v", configKeys(swamp))
+		}
+	}
 
 	if err := d.Set(checkMySQLAttr, schema.NewSet(hashCheckMySQL, []interface{}{MySQLConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkMySQLAttr, err)
@@ -75,13 +130,14 @@ func hashCheckMySQL(v interface{}) int {
 	// reconciliation with other lists.
 	writeString(checkMySQLDSNAttr)
 	writeString(checkMySQLQueryAttr)
+	hashCheckDatabase(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
 func checkConfigToAPIMySQL(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeMySQL)
+	c.Type = string(apiCheckTypeMySQLAttr)
 
 	// Iterate over all `mysql` attributes, even though we have a max of 1 in the
 	// schema.
@@ -92,9 +148,11 @@ func checkConfigToAPIMySQL(c *circonusCheck, l interfaceList) error { //nolint:u
 			c.Config[config.DSN] = v.(string)
 		}
 
-		if v, found := mysqlConfig[checkMySQLQueryAttr]; found {
+		if v, found := mysqlConfig[checkMySQLQueryAttr]; found && v.(string) != "" {
 			c.Config[config.SQL] = v.(string)
 		}
+
+		checkDatabaseConfigToAPI(c, mysqlConfig)
 	}
 
 	return nil
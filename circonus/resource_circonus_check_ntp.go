@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 
@@ -11,6 +12,10 @@ import (
 )
 
 const (
+	// circonus_check.ntp check type.
+	checkNTPAttr                     = "ntp"
+	apiCheckTypeNTPAttr apiCheckType = "ntp"
+
 	// circonus_check.ntp.* resource attribute names.
 	checkNTPPortAttr       = "port"
 	checkNTPUseControlAttr = "use_control"
@@ -21,6 +26,17 @@ var checkNTPDescriptions = attrDescrs{
 	checkNTPUseControlAttr: "Control protocol means that the agent will request the NTP telemetry of the target regarding its preferred peer, (default: false)",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeNTPAttr,
+		attr:        checkNTPAttr,
+		description: "NTP check configuration",
+		schema:      schemaCheckNTP,
+		toAPI:       checkConfigToAPINTP,
+		toState:     checkAPIToStateNTP,
+	})
+}
+
 var schemaCheckNTP = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -45,7 +61,7 @@ var schemaCheckNTP = &schema.Schema{
 
 // checkAPIToStateNTP reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateNTP(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateNTP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	ntpConfig := make(map[string]interface{}, len(c.Config))
 
 	if port, ok := c.Config[config.Port]; ok {
@@ -89,7 +105,7 @@ func hashCheckNTP(v interface{}) int {
 }
 
 func checkConfigToAPINTP(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeNTP)
+	c.Type = string(apiCheckTypeNTPAttr)
 
 	mapRaw := l[0]
 	ntpConfig := newInterfaceMap(mapRaw)
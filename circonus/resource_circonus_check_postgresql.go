@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,28 +12,48 @@ import (
 )
 
 const (
+	// circonus_check.postgresql check type.
+	checkPostgreSQLAttr                     = "postgresql"
+	apiCheckTypePostgreSQLAttr apiCheckType = "postgres"
+
 	// circonus_check.postgresql.* resource attribute names.
-	checkPostgreSQLDSNAttr = "dsn"
+	checkPostgreSQLDSNAttr        = "dsn"
+	checkPostgreSQLNamedQueryAttr = checkDatabaseNamedQueryAttr
 	// checkPostgreSQLHostAttr      = "host"
 	// checkPostgreSQLNameAttr      = "name"
-	// checkPostgreSQLPasswordAttr  = "password"
+	checkPostgreSQLPasswordAttr = checkDatabasePasswordAttr
 	// checkPostgreSQLPortAttr      = "port".
 	checkPostgreSQLQueryAttr = "query"
 	// checkPostgreSQLSSLModeAttr   = "sslmode"
+	checkPostgreSQLTLSAttr = checkDatabaseTLSAttr
 	// checkPostgreSQLUserAttr      = "user".
 )
 
 var checkPostgreSQLDescriptions = attrDescrs{
-	checkPostgreSQLDSNAttr: "The connect DSN for the PostgreSQL instance",
+	checkPostgreSQLDSNAttr:        "The connect DSN for the PostgreSQL instance",
+	checkPostgreSQLNamedQueryAttr: "A named query to run, used instead of query to run several queries",
 	// checkPostgreSQLHostAttr:     "The Hostname to connect to",
 	// checkPostgreSQLNameAttr:     "The database name to connect to",
-	// checkPostgreSQLPasswordAttr: "The password to use",
+	checkPostgreSQLPasswordAttr: "The password to connect with, kept out of the DSN",
 	// checkPostgreSQLPortAttr:     "The TCP port number to use to connect on",
 	checkPostgreSQLQueryAttr: "The SQL to use as the query",
 	// checkPostgreSQLSSLModeAttr:  "The SSL Mode to connect as",
+	checkPostgreSQLTLSAttr: "Connect using TLS",
 	// checkPostgreSQLUserAttr:     "The username to connect as",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypePostgreSQLAttr,
+		attr:        checkPostgreSQLAttr,
+		description: "PostgreSQL check configuration",
+		schema:      schemaCheckPostgreSQL,
+		toAPI:       checkConfigToAPIPostgreSQL,
+		toState:     checkAPIToStatePostgreSQL,
+		validate:    validateCheckSQL(checkPostgreSQLAttr, checkPostgreSQLQueryAttr),
+	})
+}
+
 var schemaCheckPostgreSQL = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -44,6 +65,7 @@ var schemaCheckPostgreSQL = &schema.Schema{
 			checkPostgreSQLDSNAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkPostgreSQLDSNAttr, `^.+$`),
 			},
 			// TODO(sean@): Parse out the DSN into individual PostgreSQL connect
@@ -60,11 +82,8 @@ var schemaCheckPostgreSQL = &schema.Schema{
 			// 	Required:     true,
 			// 	ValidateFunc: validateRegexp(checkPostgreSQLNameAttr, `^[\S]+$`),
 			// },
-			// checkPostgreSQLPasswordAttr: &schema.Schema{
-			// 	Type:      schema.TypeString,
-			// 	Optional:  true,
-			// 	Sensitive: true,
-			// },
+			checkPostgreSQLNamedQueryAttr: schemaCheckDatabaseNamedQuery(),
+			checkPostgreSQLPasswordAttr:   schemaCheckDatabasePassword(),
 			// checkPostgreSQLPortAttr: &schema.Schema{
 			// 	Type:     schema.TypeInt,
 			// 	Optional: true,
@@ -76,7 +95,7 @@ var schemaCheckPostgreSQL = &schema.Schema{
 			// },
 			checkPostgreSQLQueryAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				StateFunc:    suppressWhitespace,
 				ValidateFunc: validateRegexp(checkPostgreSQLQueryAttr, `.+`),
 			},
@@ -86,6 +105,7 @@ var schemaCheckPostgreSQL = &schema.Schema{
 			// 	Default:      "require",
 			// 	ValidateFunc: validateRegexp(checkPostgreSQLSSLModeAttr, `^(disable|require|verify-ca|verify-full)$`),
 			// },
+			checkPostgreSQLTLSAttr: schemaCheckDatabaseTLS(),
 			// checkPostgreSQLUserAttr: &schema.Schema{
 			// 	Type:         schema.TypeString,
 			// 	Required:     true,
@@ -97,12 +117,40 @@ var schemaCheckPostgreSQL = &schema.Schema{
 
 // checkAPIToStatePostgreSQL reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStatePostgreSQL(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStatePostgreSQL(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	postgresqlConfig := make(map[string]interface{}, len(c.Config))
 
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
 	// TODO(sean@): Parse out the DSN into individual PostgreSQL connect options
 	postgresqlConfig[string(checkPostgreSQLDSNAttr)] = c.Config[config.DSN]
-	postgresqlConfig[string(checkPostgreSQLQueryAttr)] = c.Config[config.SQL]
+	delete(swamp, config.DSN)
+
+	if v, found := c.Config[config.SQL]; found {
+		postgresqlConfig[string(checkPostgreSQLQueryAttr)] = v
+	}
+	delete(swamp, config.SQL)
+
+	checkDatabaseAPIToState(c, postgresqlConfig, swamp)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %v", configKeys(swamp))
+		}
+	}
 
 	if err := d.Set(checkPostgreSQLAttr, schema.NewSet(hashCheckPostgreSQL, []interface{}{postgresqlConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkPostgreSQLAttr, err)
@@ -134,18 +182,18 @@ func hashCheckPostgreSQL(v interface{}) int {
 	writeString(checkPostgreSQLDSNAttr)
 	// writeString(checkPostgreSQLHostAttr)
 	// writeString(checkPostgreSQLNameAttr)
-	// writeString(checkPostgreSQLPasswordAttr)
 	// writeInt(checkPostgreSQLPortAttr)
 	// writeString(checkPostgreSQLSSLModeAttr)
 	writeString(checkPostgreSQLQueryAttr)
 	// writeString(checkPostgreSQLUserAttr)
+	hashCheckDatabase(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
 func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypePostgreSQL)
+	c.Type = string(apiCheckTypePostgreSQLAttr)
 
 	// Iterate over all `postgres` attributes, even though we have a max of 1 in
 	// the schema.
@@ -156,9 +204,11 @@ func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error { //nol
 			c.Config[config.DSN] = v.(string)
 		}
 
-		if v, found := postgresConfig[checkPostgreSQLQueryAttr]; found {
+		if v, found := postgresConfig[checkPostgreSQLQueryAttr]; found && v.(string) != "" {
 			c.Config[config.SQL] = v.(string)
 		}
+
+		checkDatabaseConfigToAPI(c, postgresConfig)
 	}
 
 	return nil
@@ -124,3 +124,84 @@ EOF
   target = "pgdb.example.org"
 }
 `
+
+func TestAccCirconusCheckPostgreSQL_namedQueries(t *testing.T) {
+	checkName := fmt.Sprintf("PostgreSQL named queries check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckPostgreSQLNamedQueriesConfigFmt,
+					checkName,
+					testAccBroker1,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.dsn", "user=postgres host=pg1.example.org port=5432"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.password", "12345"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.named_query.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.tls.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.tls.0.ca_chain", "/etc/ssl/certs/db-ca.pem"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "postgresql.0.tls.0.verify_mode", "verify-ca"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.pg_multi", "type", "postgres"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.pg_multi",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckPostgreSQLNamedQueriesConfigFmt = `
+resource "circonus_check" "pg_multi" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "%s"
+  }
+
+  postgresql {
+    dsn      = "user=postgres host=pg1.example.org port=5432"
+    password = "12345"
+
+    named_query {
+      name = "tables"
+      sql  = "SELECT 'tables', sum(n_tup_ins) as inserts from pg_stat_all_tables"
+    }
+
+    named_query {
+      name = "locks"
+      sql  = "SELECT 'locks', count(*) as held from pg_locks"
+    }
+
+    tls {
+      ca_chain    = "/etc/ssl/certs/db-ca.pem"
+      verify_mode = "verify-ca"
+    }
+  }
+
+  metric {
+    name = "tables` + "`" + `tables` + "`" + `inserts"
+    type = "numeric"
+  }
+
+  metric {
+    name = "locks` + "`" + `locks` + "`" + `held"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "pgdb.example.org"
+}
+`
@@ -0,0 +1,211 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.prometheus check type.
+	checkPrometheusAttr                     = "prometheus"
+	apiCheckTypePrometheusAttr apiCheckType = "prometheus"
+
+	// circonus_check.prometheus.* resource attribute names.
+	checkPrometheusAcceptedLabelsAttr    = "accepted_labels"
+	checkPrometheusConvertHistogramsAttr = "convert_histograms"
+	checkPrometheusSecretAttr            = "secret"
+
+	// apiPrometheusAcceptedLabels is the comma separated list of the labels
+	// the broker stores as stream tags.  Every label is accepted when it is
+	// not set.
+	apiPrometheusAcceptedLabels = config.Key("accepted_labels")
+
+	// apiPrometheusConvertHistograms controls whether the broker converts
+	// Prometheus histogram buckets into Circonus histograms.
+	apiPrometheusConvertHistograms = config.Key("convert_histograms")
+)
+
+var checkPrometheusDescriptions = attrDescrs{
+	checkPrometheusAcceptedLabelsAttr:    "The labels stored as stream tags, all labels when empty",
+	checkPrometheusConvertHistogramsAttr: "Convert Prometheus histogram buckets into Circonus histograms",
+	checkPrometheusSecretAttr:            "The secret remote write requests must be sent with",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypePrometheusAttr,
+		attr:        checkPrometheusAttr,
+		description: "Prometheus remote write check configuration",
+		schema:      schemaCheckPrometheus,
+		toAPI:       checkConfigToAPIPrometheus,
+		toState:     checkAPIToStatePrometheus,
+
+		submissionURL: collectorModuleSubmissionURL(string(apiCheckTypePrometheusAttr)),
+	})
+}
+
+var schemaCheckPrometheus = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckPrometheus,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkPrometheusDescriptions, map[schemaAttr]*schema.Schema{
+			checkPrometheusAcceptedLabelsAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRegexp(checkPrometheusAcceptedLabelsAttr, `^[a-zA-Z_][a-zA-Z0-9_]*$`),
+				},
+			},
+			checkPrometheusConvertHistogramsAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  defaultCheckPrometheusConvertHistograms,
+			},
+			checkPrometheusSecretAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkPrometheusSecretAttr, `^[a-zA-Z0-9_]+$`),
+			},
+		}),
+	},
+}
+
+// checkAPIToStatePrometheus reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStatePrometheus(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	prometheusConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	if s, ok := c.Config[apiPrometheusAcceptedLabels]; ok && s != "" {
+		labels := make([]interface{}, 0)
+		for _, label := range strings.Split(s, ",") {
+			labels = append(labels, strings.TrimSpace(label))
+		}
+		prometheusConfig[string(checkPrometheusAcceptedLabelsAttr)] = labels
+	}
+	delete(swamp, apiPrometheusAcceptedLabels)
+
+	prometheusConfig[string(checkPrometheusConvertHistogramsAttr)] = defaultCheckPrometheusConvertHistograms
+	if s, ok := c.Config[apiPrometheusConvertHistograms]; ok {
+		switch s {
+		case "true", "on":
+			prometheusConfig[string(checkPrometheusConvertHistogramsAttr)] = true
+		case "false", "off":
+			prometheusConfig[string(checkPrometheusConvertHistogramsAttr)] = false
+		default:
+			tflog.Error(ctx, "PROVIDER BUG: unsupported value returned in API config", map[string]interface{}{"config_key": string(apiPrometheusConvertHistograms), "value": s})
+		}
+	}
+	delete(swamp, apiPrometheusConvertHistograms)
+
+	if s, ok := c.Config[config.Secret]; ok {
+		prometheusConfig[string(checkPrometheusSecretAttr)] = s
+	}
+	delete(swamp, config.Secret)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
+		}
+	}
+
+	if err := d.Set(checkPrometheusAttr, schema.NewSet(hashCheckPrometheus, []interface{}{prometheusConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkPrometheusAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckPrometheus creates a stable hash of the normalized values.
+func hashCheckPrometheus(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	if labelsRaw, ok := m[string(checkPrometheusAcceptedLabelsAttr)]; ok {
+		for _, label := range labelsRaw.([]interface{}) {
+			if label != nil {
+				fmt.Fprint(b, strings.TrimSpace(label.(string)))
+			}
+		}
+	}
+
+	writeBool(checkPrometheusConvertHistogramsAttr)
+	writeString(checkPrometheusSecretAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIPrometheus(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypePrometheusAttr)
+
+	// Iterate over all `prometheus` attributes, even though we have a max of 1
+	// in the schema.
+	for _, mapRaw := range l {
+		prometheusConfig := newInterfaceMap(mapRaw)
+
+		if v, found := prometheusConfig[checkPrometheusAcceptedLabelsAttr]; found {
+			labels := make([]string, 0)
+			for _, label := range v.([]interface{}) {
+				if label != nil {
+					labels = append(labels, label.(string))
+				}
+			}
+
+			if len(labels) > 0 {
+				c.Config[apiPrometheusAcceptedLabels] = strings.Join(labels, ",")
+			}
+		}
+
+		if v, found := prometheusConfig[checkPrometheusConvertHistogramsAttr]; found {
+			c.Config[apiPrometheusConvertHistograms] = fmt.Sprintf("%t", v.(bool))
+		}
+
+		if v, found := prometheusConfig[checkPrometheusSecretAttr]; found && v.(string) != "" {
+			c.Config[config.Secret] = v.(string)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckPrometheus_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Prometheus remote write - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckPrometheusConfigFmt, checkName, testAccBroker3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "collector.0.id", testAccBroker3),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.0.accepted_labels.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.0.accepted_labels.0", "job"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.0.accepted_labels.1", "instance"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.0.convert_histograms", "true"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "prometheus.0.secret", "12345"),
+					resource.TestCheckResourceAttrSet("circonus_check.remote_write", "submission_url"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "target", "prometheus-agents"),
+					resource.TestCheckResourceAttr("circonus_check.remote_write", "type", "prometheus"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.remote_write",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckPrometheusConfigFmt = `
+resource "circonus_check" "remote_write" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  prometheus {
+    accepted_labels = [ "job", "instance" ]
+    secret          = "12345"
+  }
+
+  metric_filter {
+    type    = "allow"
+    regex   = ".*"
+    comment = "Allow all remote written metrics"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "prometheus-agents"
+}
+`
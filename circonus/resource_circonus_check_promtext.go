@@ -2,26 +2,78 @@ package circonus
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
-	// circonus_check.json.* resource attribute names.
-	checkPromTextPortAttr = "port"
-	checkPromTextURLAttr  = "url"
+	// circonus_check.promtext check type.
+	checkPromTextAttr                     = "promtext"
+	apiCheckTypePromTextAttr apiCheckType = "promtext"
+
+	// circonus_check.promtext.* resource attribute names.
+	checkPromTextAuthMethodAttr   = "auth_method"
+	checkPromTextAuthPasswordAttr = "auth_password"
+	checkPromTextAuthUserAttr     = "auth_user"
+	checkPromTextBearerTokenAttr  = "bearer_token"
+	checkPromTextCAChainAttr      = "ca_chain"
+	checkPromTextCertFileAttr     = "certificate_file"
+	checkPromTextCiphersAttr      = "ciphers"
+	checkPromTextHeadersAttr      = "headers"
+	checkPromTextKeyFileAttr      = "key_file"
+	checkPromTextLabelTagsAttr    = "label_tags"
+	checkPromTextMetricFilterAttr = "metric_filter"
+	checkPromTextPortAttr         = "port"
+	checkPromTextURLAttr          = "url"
+
+	// apiPromTextLabelTagPrefix prefixes each Prometheus label that is mapped to
+	// a stream tag category.
+	apiPromTextLabelTagPrefix = "label_tag_"
+
+	// apiPromTextMetricFilters holds the JSON encoded metric filters applied
+	// when the exporter is scraped.
+	apiPromTextMetricFilters = config.Key("metric_filters")
+
+	// The bearer token is sent as the Authorization header.
+	promTextAuthorizationHeader = "Authorization"
+	promTextBearerPrefix        = "Bearer "
 )
 
 var checkPromTextDescriptions = attrDescrs{
-	checkPromTextPortAttr: "Specifies the port on which the prometheus metrics can be scraped",
-	checkPromTextURLAttr:  "The URL to use as the target of the check",
+	checkPromTextAuthMethodAttr:   "The HTTP Authentication method",
+	checkPromTextAuthPasswordAttr: "The HTTP Authentication user password",
+	checkPromTextAuthUserAttr:     "The HTTP Authentication user name",
+	checkPromTextBearerTokenAttr:  "A bearer token sent in the Authorization header",
+	checkPromTextCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
+	checkPromTextCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS-enabled checks)",
+	checkPromTextCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for HTTPS checks)",
+	checkPromTextHeadersAttr:      "Map of HTTP Headers to send along with HTTP Requests",
+	checkPromTextKeyFileAttr:      "A path to a file containing key to be used in conjunction with the client certificate (for TLS checks)",
+	checkPromTextLabelTagsAttr:    "Map of Prometheus label names to the stream tag categories their values are stored as",
+	checkPromTextMetricFilterAttr: "Allow/deny configuration applied to the scraped metrics",
+	checkPromTextPortAttr:         "Specifies the port on which the prometheus metrics can be scraped",
+	checkPromTextURLAttr:          "The URL to use as the target of the check",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypePromTextAttr,
+		attr:        checkPromTextAttr,
+		description: "Prometheus URL scraper check configuration",
+		schema:      schemaCheckPromText,
+		toAPI:       checkConfigToAPIPromText,
+		toState:     checkAPIToStatePromText,
+	})
 }
 
 var schemaCheckPromText = &schema.Schema{
@@ -32,6 +84,60 @@ var schemaCheckPromText = &schema.Schema{
 	Set:      checkPromTextConfigChecksum,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkPromTextDescriptions, map[schemaAttr]*schema.Schema{
+			checkPromTextAuthMethodAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextAuthMethodAttr, `^(?:Basic|Digest|Auto)$`),
+			},
+			checkPromTextAuthPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkPromTextAuthPasswordAttr, `^.*`),
+			},
+			checkPromTextAuthUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextAuthUserAttr, `[^:]+`),
+			},
+			checkPromTextBearerTokenAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkPromTextBearerTokenAttr, `^\S+$`),
+			},
+			checkPromTextCAChainAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextCAChainAttr, `.+`),
+			},
+			checkPromTextCertFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextCertFileAttr, `.+`),
+			},
+			checkPromTextCiphersAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextCiphersAttr, `.+`),
+			},
+			checkPromTextHeadersAttr: {
+				Type:         schema.TypeMap,
+				Elem:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateHTTPHeaders,
+			},
+			checkPromTextKeyFileAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPromTextKeyFileAttr, `.+`),
+			},
+			checkPromTextLabelTagsAttr: {
+				Type:     schema.TypeMap,
+				Elem:     schema.TypeString,
+				Optional: true,
+			},
+			checkPromTextMetricFilterAttr: schemaMetricFilter(),
 			checkPromTextPortAttr: {
 				Type:     schema.TypeInt,
 				Default:  443,
@@ -52,9 +158,9 @@ var schemaCheckPromText = &schema.Schema{
 	},
 }
 
-// checkAPIToStateJSON reads the Config data out of circonusCheck.CheckBundle into
-// the statefile.
-func checkAPIToStatePromText(c *circonusCheck, d *schema.ResourceData) error {
+// checkAPIToStatePromText reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStatePromText(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	ptConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -75,7 +181,7 @@ func checkAPIToStatePromText(c *circonusCheck, d *schema.ResourceData) error {
 		if s, ok := c.Config[apiKey]; ok && s != "0" {
 			i, err := strconv.ParseInt(s, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			ptConfig[string(attrName)] = int(i)
@@ -84,6 +190,55 @@ func checkAPIToStatePromText(c *circonusCheck, d *schema.ResourceData) error {
 		delete(swamp, apiKey)
 	}
 
+	saveStringConfigToState(config.AuthMethod, checkPromTextAuthMethodAttr)
+	saveStringConfigToState(config.AuthPassword, checkPromTextAuthPasswordAttr)
+	saveStringConfigToState(config.AuthUser, checkPromTextAuthUserAttr)
+	saveStringConfigToState(config.CAChain, checkPromTextCAChainAttr)
+	saveStringConfigToState(config.CertFile, checkPromTextCertFileAttr)
+	saveStringConfigToState(config.Ciphers, checkPromTextCiphersAttr)
+
+	// A bearer Authorization header is only read back as bearer_token when the
+	// state already uses bearer_token, otherwise it stays in headers where a
+	// configuration setting it directly expects it.
+	bearerToken := promTextUsesBearerToken(d)
+
+	headers := make(map[string]interface{}, len(c.Config))
+	labelTags := make(map[string]interface{}, len(c.Config))
+	for k, v := range c.Config {
+		switch {
+		case strings.HasPrefix(string(k), string(config.HeaderPrefix)):
+			header := strings.TrimPrefix(string(k), string(config.HeaderPrefix))
+			if bearerToken && header == promTextAuthorizationHeader && strings.HasPrefix(v, promTextBearerPrefix) {
+				ptConfig[string(checkPromTextBearerTokenAttr)] = strings.TrimPrefix(v, promTextBearerPrefix)
+			} else {
+				headers[header] = v
+			}
+		case strings.HasPrefix(string(k), apiPromTextLabelTagPrefix):
+			labelTags[strings.TrimPrefix(string(k), apiPromTextLabelTagPrefix)] = v
+		default:
+			continue
+		}
+		delete(swamp, k)
+	}
+	ptConfig[string(checkPromTextHeadersAttr)] = headers
+	ptConfig[string(checkPromTextLabelTagsAttr)] = labelTags
+
+	saveStringConfigToState(config.KeyFile, checkPromTextKeyFileAttr)
+
+	metricFilters := make([]interface{}, 0)
+	if v, ok := c.Config[apiPromTextMetricFilters]; ok && v != "" {
+		var filters [][]string
+		if err := json.Unmarshal([]byte(v), &filters); err != nil {
+			return fmt.Errorf("Unable to decode check %q config %q: %w", checkPromTextAttr, apiPromTextMetricFilters, err)
+		}
+
+		for _, m := range filters {
+			metricFilters = append(metricFilters, metricFilterToState(m))
+		}
+	}
+	ptConfig[string(checkPromTextMetricFilterAttr)] = metricFilters
+	delete(swamp, apiPromTextMetricFilters)
+
 	saveIntConfigToState(config.Port, checkPromTextPortAttr)
 	saveStringConfigToState(config.URL, checkPromTextURLAttr)
 
@@ -109,8 +264,8 @@ func checkAPIToStatePromText(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
-// checkJSONConfigChecksum creates a stable hash of the normalized values found
-// in a user's Terraform config.
+// checkPromTextConfigChecksum creates a stable hash of the normalized values
+// found in a user's Terraform config.
 func checkPromTextConfigChecksum(v interface{}) int {
 	m := v.(map[string]interface{})
 	b := &bytes.Buffer{}
@@ -122,6 +277,22 @@ func checkPromTextConfigChecksum(v interface{}) int {
 		}
 	}
 
+	writeMap := func(attrName schemaAttr) {
+		if mapRaw, ok := m[string(attrName)]; ok {
+			valueMap := mapRaw.(map[string]interface{})
+			keys := make([]string, 0, len(valueMap))
+			for k := range valueMap {
+				keys = append(keys, k)
+			}
+
+			sort.Strings(keys)
+			for i := range keys {
+				fmt.Fprint(b, keys[i])
+				fmt.Fprint(b, valueMap[keys[i]].(string))
+			}
+		}
+	}
+
 	writeString := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
 			fmt.Fprint(b, strings.TrimSpace(v.(string)))
@@ -130,6 +301,24 @@ func checkPromTextConfigChecksum(v interface{}) int {
 
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
+	writeString(checkPromTextAuthMethodAttr)
+	writeString(checkPromTextAuthPasswordAttr)
+	writeString(checkPromTextAuthUserAttr)
+	writeString(checkPromTextBearerTokenAttr)
+	writeString(checkPromTextCAChainAttr)
+	writeString(checkPromTextCertFileAttr)
+	writeString(checkPromTextCiphersAttr)
+	writeMap(checkPromTextHeadersAttr)
+	writeString(checkPromTextKeyFileAttr)
+	writeMap(checkPromTextLabelTagsAttr)
+
+	if filtersRaw, ok := m[string(checkPromTextMetricFilterAttr)]; ok {
+		for _, filterRaw := range filtersRaw.([]interface{}) {
+			filter := filterRaw.(map[string]interface{})
+			fmt.Fprint(b, strings.Join(metricFilterToAPI(filter), "\x00"))
+		}
+	}
+
 	writeInt(checkPromTextPortAttr)
 	writeString(checkPromTextURLAttr)
 
@@ -137,14 +326,79 @@ func checkPromTextConfigChecksum(v interface{}) int {
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypePromText)
+func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypePromTextAttr)
 
 	// Iterate over all `promtext` attributes, even though we have a max of 1 in the
 	// schema.
 	for _, mapRaw := range l {
 		ptConfig := newInterfaceMap(mapRaw)
 
+		if v, found := ptConfig[checkPromTextAuthMethodAttr]; found && v.(string) != "" {
+			c.Config[config.AuthMethod] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextAuthPasswordAttr]; found && v.(string) != "" {
+			c.Config[config.AuthPassword] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextAuthUserAttr]; found && v.(string) != "" {
+			c.Config[config.AuthUser] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextCAChainAttr]; found && v.(string) != "" {
+			c.Config[config.CAChain] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextCertFileAttr]; found && v.(string) != "" {
+			c.Config[config.CertFile] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextCiphersAttr]; found && v.(string) != "" {
+			c.Config[config.Ciphers] = v.(string)
+		}
+
+		for k, v := range ptConfig.CollectMap(checkPromTextHeadersAttr) {
+			h := config.HeaderPrefix + config.Key(k)
+			c.Config[h] = v
+		}
+
+		if v, found := ptConfig[checkPromTextBearerTokenAttr]; found && v.(string) != "" {
+			if _, found := c.Config[config.HeaderPrefix+promTextAuthorizationHeader]; found {
+				return attrErrorf(attrPath(checkPromTextAttr), "only one of %s or an %s header may be set", checkPromTextBearerTokenAttr, promTextAuthorizationHeader)
+			}
+
+			if _, found := c.Config[config.AuthUser]; found {
+				return attrErrorf(attrPath(checkPromTextAttr), "only one of %s or %s may be set", checkPromTextBearerTokenAttr, checkPromTextAuthUserAttr)
+			}
+
+			c.Config[config.HeaderPrefix+promTextAuthorizationHeader] = promTextBearerPrefix + v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextKeyFileAttr]; found && v.(string) != "" {
+			c.Config[config.KeyFile] = v.(string)
+		}
+
+		for k, v := range ptConfig.CollectMap(checkPromTextLabelTagsAttr) {
+			c.Config[config.Key(apiPromTextLabelTagPrefix+k)] = v
+		}
+
+		if v, found := ptConfig[checkPromTextMetricFilterAttr]; found {
+			filterList := v.([]interface{})
+			if len(filterList) > 0 {
+				filters := make([][]string, 0, len(filterList))
+				for _, filterRaw := range filterList {
+					filters = append(filters, metricFilterToAPI(filterRaw.(map[string]interface{})))
+				}
+
+				buf, err := json.Marshal(filters)
+				if err != nil {
+					return fmt.Errorf("Unable to encode check %q %q: %w", checkPromTextAttr, checkPromTextMetricFilterAttr, err)
+				}
+				c.Config[apiPromTextMetricFilters] = string(buf)
+			}
+		}
+
 		if v, found := ptConfig[checkPromTextPortAttr]; found {
 			i := v.(int)
 			if i != 0 {
@@ -169,3 +423,20 @@ func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolin
 
 	return nil
 }
+
+// promTextUsesBearerToken reports whether the promtext block in the state sets
+// bearer_token.
+func promTextUsesBearerToken(d *schema.ResourceData) bool {
+	v, ok := d.GetOk(checkPromTextAttr)
+	if !ok {
+		return false
+	}
+
+	for _, mapRaw := range v.(*schema.Set).List() {
+		if s, _ := newInterfaceMap(mapRaw)[checkPromTextBearerTokenAttr].(string); s != "" {
+			return true
+		}
+	}
+
+	return false
+}
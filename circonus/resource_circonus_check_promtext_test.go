@@ -0,0 +1,231 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccCirconusCheckPromText_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Prometheus exporter - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckPromTextConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.exporter", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "collector.0.id", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.url", "https://exporter.example.com:9100/metrics"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.bearer_token", "abc123"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.ca_chain", "/etc/ssl/certs/exporter-ca.pem"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.headers.%", "1"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.headers.X-Scope-OrgID", "platform"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.label_tags.%", "1"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.label_tags.job", "service"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.metric_filter.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.metric_filter.0.type", "allow"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.metric_filter.0.regex", "^node_"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "promtext.0.metric_filter.1.type", "deny"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "target", "exporter.example.com"),
+					resource.TestCheckResourceAttr("circonus_check.exporter", "type", "promtext"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.exporter",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metric", "timeouts"},
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckPromTextConfigFmt = `
+resource "circonus_check" "exporter" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  promtext {
+    url          = "https://exporter.example.com:9100/metrics"
+    bearer_token = "abc123"
+    ca_chain     = "/etc/ssl/certs/exporter-ca.pem"
+
+    headers = {
+      X-Scope-OrgID = "platform",
+    }
+
+    label_tags = {
+      job = "service",
+    }
+
+    metric_filter {
+      type    = "allow"
+      regex   = "^node_"
+      comment = "Node metrics only"
+    }
+
+    metric_filter {
+      type    = "deny"
+      regex   = ".*"
+      comment = "Deny everything else"
+    }
+  }
+
+  metric_filter {
+    type    = "allow"
+    regex   = ".*"
+    comment = "Allow all scraped metrics"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
+
+func Test_CheckConfigToAPIPromText(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[config.Key]string
+		fail     bool
+	}{
+		{
+			name: "bearer token",
+			config: map[string]interface{}{
+				string(checkPromTextBearerTokenAttr): "abc123",
+				string(checkPromTextURLAttr):         "https://exporter.example.com/metrics",
+			},
+			expected: map[config.Key]string{
+				"header_Authorization": "Bearer abc123",
+				config.URL:             "https://exporter.example.com/metrics",
+			},
+		},
+		{
+			name: "filters and label tags",
+			config: map[string]interface{}{
+				string(checkPromTextLabelTagsAttr): map[string]interface{}{"job": "service"},
+				string(checkPromTextMetricFilterAttr): []interface{}{
+					map[string]interface{}{"type": "deny", "regex": "^go_", "tag_query": "", "comment": ""},
+				},
+				string(checkPromTextURLAttr): "https://exporter.example.com/metrics",
+			},
+			expected: map[config.Key]string{
+				"label_tag_job":          "service",
+				apiPromTextMetricFilters: `[["deny","^go_","tags","",""]]`,
+				config.URL:               "https://exporter.example.com/metrics",
+			},
+		},
+		{
+			name: "bearer token and authorization header",
+			config: map[string]interface{}{
+				string(checkPromTextBearerTokenAttr): "abc123",
+				string(checkPromTextHeadersAttr):     map[string]interface{}{"Authorization": "Basic Zm9vOmJhcg=="},
+				string(checkPromTextURLAttr):         "https://exporter.example.com/metrics",
+			},
+			fail: true,
+		},
+		{
+			name: "bearer token and basic auth",
+			config: map[string]interface{}{
+				string(checkPromTextAuthUserAttr):    "scraper",
+				string(checkPromTextBearerTokenAttr): "abc123",
+				string(checkPromTextURLAttr):         "https://exporter.example.com/metrics",
+			},
+			fail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPIPromText(&c, interfaceList{test.config})
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Config) != len(test.expected) {
+				t.Fatalf("expected %d config keys, got %#v", len(test.expected), c.Config)
+			}
+
+			for k, v := range test.expected {
+				if c.Config[k] != v {
+					t.Errorf("config key %q: expected %q, got %q", k, v, c.Config[k])
+				}
+			}
+		})
+	}
+}
+
+func Test_CheckAPIToStatePromTextBearerToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		prior   map[string]interface{}
+		token   string
+		headers map[string]interface{}
+	}{
+		{
+			name:    "bearer_token configured",
+			prior:   map[string]interface{}{"bearer_token": "abc123"},
+			token:   "abc123",
+			headers: map[string]interface{}{},
+		},
+		{
+			name:    "header configured",
+			prior:   map[string]interface{}{"headers": map[string]interface{}{"Authorization": "Bearer abc123"}},
+			headers: map[string]interface{}{"Authorization": "Bearer abc123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prior := test.prior
+			prior["url"] = "https://exporter.example.com/metrics"
+			d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+				"promtext": []interface{}{prior},
+			})
+
+			c := newCheck()
+			c.Config = map[config.Key]string{
+				"header_Authorization": "Bearer abc123",
+				config.URL:             "https://exporter.example.com/metrics",
+			}
+
+			if err := checkAPIToStatePromText(context.Background(), &c, d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ptConfig := d.Get("promtext").(*schema.Set).List()[0].(map[string]interface{})
+			if token := ptConfig["bearer_token"].(string); token != test.token {
+				t.Errorf("expected bearer_token %q, got %q", test.token, token)
+			}
+
+			if headers := ptConfig["headers"].(map[string]interface{}); !reflect.DeepEqual(headers, test.headers) {
+				t.Errorf("expected headers %v, got %v", test.headers, headers)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.raw check type.
+	checkRawAttr = "raw"
+
+	// circonus_check.raw.* resource attribute names.
+	checkRawTypeAttr   = "type"
+	checkRawConfigAttr = "config"
+)
+
+var checkRawDescriptions = attrDescrs{
+	checkRawTypeAttr:   "The Circonus check type (e.g. nginx, selfcheck, haproxy)",
+	checkRawConfigAttr: "The check's config, passed through to the check bundle unmodified",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		attr:        checkRawAttr,
+		description: "Check configuration for any check type, passed through to the API unmodified",
+		schema:      schemaCheckRaw,
+		toAPI:       checkConfigToAPIRaw,
+		toState:     checkAPIToStateRaw,
+	})
+}
+
+var schemaCheckRaw = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckRaw,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkRawDescriptions, map[schemaAttr]*schema.Schema{
+			checkRawTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkRawTypeAttr, `^[\w:.-]+$`),
+			},
+			checkRawConfigAttr: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		}),
+	},
+}
+
+// checkRawGeneratedConfigKeys are config keys the API adds to a check bundle.
+// They are never stored in the raw config so they don't show up as a diff.
+var checkRawGeneratedConfigKeys = map[config.Key]struct{}{
+	config.ReverseSecretKey: {},
+	config.SubmissionURL:    {},
+}
+
+// checkAPIToStateRaw reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.  It is used for check types the provider has no
+// dedicated block for, and for checks already managed through a raw block.
+func checkAPIToStateRaw(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
+	rawCheckConfig := make(map[string]interface{}, len(c.Config))
+	for k, v := range c.Config {
+		if _, generated := checkRawGeneratedConfigKeys[k]; generated {
+			continue
+		}
+
+		rawCheckConfig[string(k)] = v
+	}
+
+	rawConfig := map[string]interface{}{
+		string(checkRawTypeAttr):   c.Type,
+		string(checkRawConfigAttr): rawCheckConfig,
+	}
+
+	if err := d.Set(checkRawAttr, schema.NewSet(hashCheckRaw, []interface{}{rawConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkRawAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckRaw creates a stable hash of the normalized values.
+func hashCheckRaw(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	if v, ok := m[string(checkRawTypeAttr)]; ok {
+		fmt.Fprint(b, v.(string))
+	}
+
+	if v, ok := m[string(checkRawConfigAttr)]; ok && v != nil {
+		rawCheckConfig := v.(map[string]interface{})
+		keys := make([]string, 0, len(rawCheckConfig))
+		for k := range rawCheckConfig {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(b, "%s=%v", k, rawCheckConfig[k])
+		}
+	}
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIRaw(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	for _, mapRaw := range l {
+		rawConfig := newInterfaceMap(mapRaw)
+
+		if v, found := rawConfig[checkRawTypeAttr]; found {
+			c.Type = v.(string)
+		}
+
+		for k, v := range rawConfig.CollectMap(checkRawConfigAttr) {
+			c.Config[config.Key(k)] = v
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckRaw_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Raw ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckRawConfigFmt,
+					checkName,
+					testAccBroker1,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "collector.0.id", testAccBroker1),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "icmp_ping.#", "0"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "raw.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "raw.0.type", "ping_icmp"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "raw.0.config.%", "2"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "raw.0.config.count", "5"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "raw.0.config.interval", "500"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "target", "api.circonus.com"),
+					resource.TestCheckResourceAttr("circonus_check.raw_ping", "type", "ping_icmp"),
+				),
+			},
+			{
+				ResourceName:      "circonus_check.raw_ping",
+				ImportState:       true,
+				ImportStateVerify: false,
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckRawConfigFmt = `
+resource "circonus_check" "raw_ping" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "%s"
+  }
+
+  raw {
+    type = "ping_icmp"
+    config = {
+      count    = "5"
+      interval = "500"
+    }
+  }
+
+  metric {
+    name = "average"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "api.circonus.com"
+}
+`
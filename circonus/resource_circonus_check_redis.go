@@ -2,22 +2,28 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.redis check type.
+	checkRedisAttr                     = "redis"
+	apiCheckTypeRedisAttr apiCheckType = "redis"
+
 	// circonus_check.redis.* resource attribute names.
 	checkRedisCommandAttr  = "command"
 	checkRedisDbIndexAttr  = "db_index"
-	checkRedisPasswordAttr = "password"
+	checkRedisPasswordAttr = checkDatabasePasswordAttr
 	checkRedisPortAttr     = "port"
+	checkRedisTLSAttr      = checkDatabaseTLSAttr
 )
 
 var checkRedisDescriptions = attrDescrs{
@@ -25,6 +31,19 @@ var checkRedisDescriptions = attrDescrs{
 	checkRedisDbIndexAttr:  "The database index to query, defaults to zero",
 	checkRedisPasswordAttr: "The pass required to run the command.",
 	checkRedisPortAttr:     "Specifies the port on which the Redis instance can be reached.",
+	checkRedisTLSAttr:      "Connect using TLS",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeRedisAttr,
+		attr:        checkRedisAttr,
+		description: "Redis check configuration",
+		schema:      schemaCheckRedis,
+		toAPI:       checkConfigToAPIRedis,
+		toState:     checkAPIToStateRedis,
+		validate:    validateCheckDatabaseTLS(checkRedisAttr),
+	})
 }
 
 var schemaCheckRedis = &schema.Schema{
@@ -62,11 +81,12 @@ var schemaCheckRedis = &schema.Schema{
 					validateIntMax(checkTCPPortAttr, 65535),
 				),
 			},
+			checkRedisTLSAttr: schemaCheckDatabaseTLS(),
 		}),
 	},
 }
 
-func checkAPIToStateRedis(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateRedis(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	redisConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -79,7 +99,7 @@ func checkAPIToStateRedis(c *circonusCheck, d *schema.ResourceData) error {
 		if v, ok := c.Config[apiKey]; ok {
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			redisConfig[string(attrName)] = int(i)
@@ -98,8 +118,8 @@ func checkAPIToStateRedis(c *circonusCheck, d *schema.ResourceData) error {
 
 	saveStringConfigToState(config.Command, checkRedisCommandAttr)
 	saveIntConfigToState(config.DBIndex, checkRedisDbIndexAttr)
-	saveStringConfigToState(config.Password, checkRedisPasswordAttr)
 	saveIntConfigToState(config.Port, checkRedisPortAttr)
+	checkDatabaseAPIToState(c, redisConfig, swamp)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
@@ -112,7 +132,7 @@ func checkAPIToStateRedis(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -144,15 +164,15 @@ func hashCheckRedis(v interface{}) int {
 	// reconciliation with other lists.
 	writeString(checkRedisCommandAttr)
 	writeInt(checkRedisDbIndexAttr)
-	writeString(checkRedisPasswordAttr)
 	writeInt(checkRedisPortAttr)
+	hashCheckDatabase(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
 func checkConfigToAPIRedis(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeRedis)
+	c.Type = string(apiCheckTypeRedisAttr)
 
 	// Iterate over all `tcp` attributes, even though we have a max of 1 in the
 	// schema.
@@ -167,13 +187,11 @@ func checkConfigToAPIRedis(c *circonusCheck, l interfaceList) error { //nolint:u
 			c.Config[config.DBIndex] = fmt.Sprintf("%d", v.(int))
 		}
 
-		if v, found := redisConfig[checkRedisPasswordAttr]; found {
-			c.Config[config.Password] = v.(string)
-		}
-
 		if v, found := redisConfig[checkRedisPortAttr]; found {
 			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
 		}
+
+		checkDatabaseConfigToAPI(c, redisConfig)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 
@@ -11,6 +12,10 @@ import (
 )
 
 const (
+	// circonus_check.smtp check type.
+	checkSMTPAttr                     = "smtp"
+	apiCheckTypeSMTPAttr apiCheckType = "smtp"
+
 	checkSMTPEhloAttr               = "ehlo"
 	checkSMTPFromAttr               = "from"
 	checkSMTPPayloadAttr            = "payload"
@@ -48,6 +53,17 @@ var checkSMTPDescriptions = attrDescrs{
 	checkSMTPToAttr:                 "Specifies the envelope recipient.",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeSMTPAttr,
+		attr:        checkSMTPAttr,
+		description: "SMTP check configuration",
+		schema:      schemaCheckSMTP,
+		toAPI:       checkConfigToAPISMTP,
+		toState:     checkAPIToStateSMTP,
+	})
+}
+
 var schemaCheckSMTP = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -145,7 +161,7 @@ var schemaCheckSMTP = &schema.Schema{
 
 // checkAPIToStateSMTP reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateSMTP(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateSMTP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	smtpConfig := make(map[string]interface{}, len(c.Config))
 
 	if ehlo, ok := c.Config[config.EHLO]; ok {
@@ -273,7 +289,7 @@ func hashCheckSMTP(v interface{}) int {
 }
 
 func checkConfigToAPISMTP(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeSMTP)
+	c.Type = string(apiCheckTypeSMTPAttr)
 
 	mapRaw := l[0]
 	smtpConfig := newInterfaceMap(mapRaw)
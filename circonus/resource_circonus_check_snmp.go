@@ -1,24 +1,31 @@
 package circonus
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
-	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.snmp check type.
+	checkSNMPAttr                     = "snmp"
+	apiCheckTypeSNMPAttr apiCheckType = "snmp"
+
 	// circonus_check.snmp.* resource attribute names.
 	checkSNMPAuthPassphrase    = "auth_passphrase"
 	checkSNMPAuthProtocol      = "auth_protocol"
 	checkSNMPCommunity         = "community"
 	checkSNMPContextEngine     = "context_engine"
 	checkSNMPContextName       = "context_name"
+	checkSNMPMIBDirs           = "mib_dirs"
 	checkSNMPOID               = "oid"
 	checkSNMPOIDName           = "name"
 	checkSNMPOIDPath           = "path"
@@ -31,6 +38,23 @@ const (
 	checkSNMPSecurityName      = "security_name"
 	checkSNMPSeparateQueries   = "separate_queries"
 	checkSNMPVersion           = "version"
+	checkSNMPWalk              = "walk"
+	checkSNMPWalkIndexTag      = "index_tag"
+	checkSNMPWalkTagOIDs       = "tag_oids"
+
+	// Broker config keys for walks.  walk_<name> holds the OID of the table
+	// column walked, index_tag_<name> the stream tag category carrying the row
+	// index and tag_oids_<name> a JSON object of the stream tag categories
+	// taken from other columns of the same row.
+	apiSNMPWalkPrefix     = "walk_"
+	apiSNMPIndexTagPrefix = "index_tag_"
+	apiSNMPTagOIDsPrefix  = "tag_oids_"
+
+	// apiSNMPOrder lists the names of the OIDs and walks, in the order they
+	// are configured, so the order survives a round trip through the API.
+	apiSNMPOrder = config.Key("order")
+
+	defaultCheckSNMPWalkIndexTag = "index"
 )
 
 var checkSNMPDescriptions = attrDescrs{
@@ -39,6 +63,7 @@ var checkSNMPDescriptions = attrDescrs{
 	checkSNMPCommunity:         "The SNMP community string providing read access.",
 	checkSNMPContextEngine:     "The context engine hex value to use. Only applicaable to SNMP Version 3.",
 	checkSNMPContextName:       "The context name to use. Only applicaable to SNMP Version 3.",
+	checkSNMPMIBDirs:           "Local directories holding the MIB files used to resolve symbolic OIDs at plan time.",
 	checkSNMPOID:               "Defines a metric to query.",
 	checkSNMPPort:              "The UDP port to which SNMP queries will be sent.",
 	checkSNMPPrivacyPassphrase: "The privacy passphrase to use. Only applicaable to SNMP Version 3.",
@@ -48,6 +73,7 @@ var checkSNMPDescriptions = attrDescrs{
 	checkSNMPSecurityName:      "The security name (or user name) to use. Only applicaable to SNMP Version 3.",
 	checkSNMPSeparateQueries:   "Whether or not to query each OID separately.",
 	checkSNMPVersion:           "The SNMP version used for queries.",
+	checkSNMPWalk:              "Defines an SNMP table column to walk, producing one metric per row.",
 }
 
 var checkSNMPOIDDescriptions = attrDescrs{
@@ -56,6 +82,25 @@ var checkSNMPOIDDescriptions = attrDescrs{
 	checkSNMPOIDType: "The metric type of this OID. The value can be either one of the single letter codes in the metric_type_t enum or the following string variants: guess, int32, uint32, int64, uint64, double, string.",
 }
 
+var checkSNMPWalkDescriptions = attrDescrs{
+	checkSNMPOIDName:      "Name of the metrics produced by this walk.",
+	checkSNMPOIDPath:      "The decimal notation or MIB name of the table column to walk.",
+	checkSNMPOIDType:      checkSNMPOIDDescriptions[checkSNMPOIDType],
+	checkSNMPWalkIndexTag: "The stream tag category holding the row index of each metric.",
+	checkSNMPWalkTagOIDs:  "A map of stream tag categories to the decimal notation or MIB name of the table column providing the tag value of each row.",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeSNMPAttr,
+		attr:        checkSNMPAttr,
+		description: "SNMP check configuration",
+		schema:      schemaCheckSNMP,
+		toAPI:       checkConfigToAPISNMP,
+		toState:     checkAPIToStateSNMP,
+	})
+}
+
 var schemaCheckSNMP = &schema.Schema{
 	Type:     schema.TypeList,
 	Optional: true,
@@ -89,6 +134,14 @@ var schemaCheckSNMP = &schema.Schema{
 				Optional:     true,
 				ValidateFunc: validateRegexp(checkSNMPContextName, `.+`),
 			},
+			checkSNMPMIBDirs: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRegexp(checkSNMPMIBDirs, `.+`),
+				},
+			},
 			checkSNMPPort: {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -132,16 +185,37 @@ var schemaCheckSNMP = &schema.Schema{
 				ValidateFunc: validateRegexp(checkSNMPVersion, `(1|2c|3)`),
 			},
 			checkSNMPOID: {
-				Type:     schema.TypeSet,
-				Required: true,
-				MinItems: 1,
-				Set:      hashCheckSNMPOID,
+				Type:     schema.TypeList,
+				Optional: true,
 				Elem: &schema.Resource{
 					Schema: convertToHelperSchema(checkSNMPOIDDescriptions, map[schemaAttr]*schema.Schema{
 						checkSNMPOIDName: {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validateRegexp(checkSNMPOIDName, `^.+$`),
+							ValidateFunc: validateRegexp(checkSNMPOIDName, `^[^,]+$`),
+						},
+						checkSNMPOIDPath: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDPath, `^.+$`),
+						},
+						checkSNMPOIDType: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDType, `^.+$`),
+						},
+					}),
+				},
+			},
+			checkSNMPWalk: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkSNMPWalkDescriptions, map[schemaAttr]*schema.Schema{
+						checkSNMPOIDName: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDName, `^[^,]+$`),
 						},
 						checkSNMPOIDPath: {
 							Type:         schema.TypeString,
@@ -153,6 +227,17 @@ var schemaCheckSNMP = &schema.Schema{
 							Optional:     true,
 							ValidateFunc: validateRegexp(checkSNMPOIDType, `^.+$`),
 						},
+						checkSNMPWalkIndexTag: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultCheckSNMPWalkIndexTag,
+							ValidateFunc: validateRegexp(checkSNMPWalkIndexTag, `^[^:|,]+$`),
+						},
+						checkSNMPWalkTagOIDs: {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					}),
 				},
 			},
@@ -160,30 +245,9 @@ var schemaCheckSNMP = &schema.Schema{
 	},
 }
 
-// hashCheckSNMPOID creates a stable hash of the normalized OID values.
-func hashCheckSNMPOID(v interface{}) int {
-	m := v.(map[string]interface{})
-
-	b := &bytes.Buffer{}
-
-	b.Grow(defaultHashBufSize)
-
-	writeString := func(attrName schemaAttr) {
-		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
-			fmt.Fprint(b, strings.TrimSpace(v.(string)))
-		}
-	}
-
-	writeString(checkSNMPOIDName)
-
-	s := b.String()
-
-	return hashcode.String(s)
-}
-
 // checkAPIToStateSNMP reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateSNMP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	snmpConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -196,7 +260,7 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 		if v, ok := c.Config[apiKey]; ok {
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			snmpConfig[string(attrName)] = int(i)
@@ -213,7 +277,7 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 			case "false", "off":
 				snmpConfig[string(attrName)] = false
 			default:
-				log.Printf("PROVIDER BUG: unsupported value %q returned in key %q", s, apiKey)
+				tflog.Error(ctx, "PROVIDER BUG: unsupported value returned in API config", map[string]interface{}{"config_key": string(apiKey), "value": s})
 			}
 		}
 
@@ -242,35 +306,75 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 	saveBoolConfigToState(config.SeparateQueries, checkSNMPSeparateQueries)
 	saveStringConfigToState(config.Version, checkSNMPVersion)
 
-	// count the number of oids in the config so we can make our list
-	oidCount := 0
-	for k := range c.Config {
-		key := string(k)
-		if strings.HasPrefix(key, string(config.OIDPrefix)) {
-			oidCount++
-		}
-	}
+	// The MIB directories only exist locally, the API never sees them.
+	prior := checkSNMPPriorState(d)
+	snmpConfig[string(checkSNMPMIBDirs)] = prior.mibDirs
+
 	oidList := make([]interface{}, 0)
+	walkList := make([]interface{}, 0)
 	for k, v := range c.Config {
 		key := string(k)
-		if strings.HasPrefix(key, string(config.OIDPrefix)) {
-			oidProps := make(map[string]interface{})
-			name := key[4:]
-			oidProps[string(checkSNMPOIDName)] = name
-			oidProps[string(checkSNMPOIDPath)] = v
-
-			t := string(config.TypePrefix) + name
-			if tv, ok := c.Config[config.Key(t)]; ok {
+		switch {
+		case strings.HasPrefix(key, string(config.OIDPrefix)):
+			name := strings.TrimPrefix(key, string(config.OIDPrefix))
+			oidProps := map[string]interface{}{
+				string(checkSNMPOIDName): name,
+				string(checkSNMPOIDPath): prior.path(ctx, name, v),
+			}
+
+			t := config.Key(string(config.TypePrefix) + name)
+			if tv, ok := c.Config[t]; ok {
 				oidProps[string(checkSNMPOIDType)] = tv
-				delete(swamp, config.Key(t))
+				delete(swamp, t)
 			}
 			delete(swamp, k)
 			oidList = append(oidList, oidProps)
+		case strings.HasPrefix(key, apiSNMPWalkPrefix):
+			name := strings.TrimPrefix(key, apiSNMPWalkPrefix)
+			walkProps := map[string]interface{}{
+				string(checkSNMPOIDName):      name,
+				string(checkSNMPOIDPath):      prior.path(ctx, name, v),
+				string(checkSNMPWalkIndexTag): defaultCheckSNMPWalkIndexTag,
+			}
+
+			t := config.Key(string(config.TypePrefix) + name)
+			if tv, ok := c.Config[t]; ok {
+				walkProps[string(checkSNMPOIDType)] = tv
+				delete(swamp, t)
+			}
+
+			indexTag := config.Key(apiSNMPIndexTagPrefix + name)
+			if tv, ok := c.Config[indexTag]; ok {
+				walkProps[string(checkSNMPWalkIndexTag)] = tv
+				delete(swamp, indexTag)
+			}
+
+			tagOIDsKey := config.Key(apiSNMPTagOIDsPrefix + name)
+			if tv, ok := c.Config[tagOIDsKey]; ok {
+				var tagOIDs map[string]string
+				if err := json.Unmarshal([]byte(tv), &tagOIDs); err != nil {
+					return fmt.Errorf("unable to decode %s config %q: %w", checkSNMPWalkTagOIDs, tv, err)
+				}
+				tags := make(map[string]interface{}, len(tagOIDs))
+				for category, oid := range tagOIDs {
+					tags[category] = prior.tagPath(ctx, name, category, oid)
+				}
+				walkProps[string(checkSNMPWalkTagOIDs)] = tags
+				delete(swamp, tagOIDsKey)
+			}
+			delete(swamp, k)
+			walkList = append(walkList, walkProps)
 		}
 	}
 
-	snmpConfig[string(checkSNMPOID)] = schema.NewSet(hashCheckSNMPOID,
-		oidList)
+	var order []string
+	if v, ok := c.Config[apiSNMPOrder]; ok && v != "" {
+		order = strings.Split(v, ",")
+	}
+	delete(swamp, apiSNMPOrder)
+
+	snmpConfig[string(checkSNMPOID)] = sortSNMPItems(oidList, order)
+	snmpConfig[string(checkSNMPWalk)] = sortSNMPItems(walkList, order)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
@@ -283,7 +387,7 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -294,12 +398,120 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
-func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeSNMP)
+// checkSNMPState holds the parts of a prior snmp block that the API doesn't
+// return: the MIB directories and the symbolic OIDs as they were written.
+type checkSNMPState struct {
+	mibDirs []interface{}
+	paths   map[string]string
+	tags    map[string]map[string]interface{}
+
+	mib    *snmpMIB
+	mibErr error
+}
+
+func checkSNMPPriorState(d *schema.ResourceData) *checkSNMPState {
+	prior := &checkSNMPState{
+		mibDirs: []interface{}{},
+		paths:   make(map[string]string),
+		tags:    make(map[string]map[string]interface{}),
+	}
+
+	l, ok := d.Get(checkSNMPAttr).([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return prior
+	}
+
+	snmpConfig := newInterfaceMap(l[0])
+	if v, ok := snmpConfig[checkSNMPMIBDirs].([]interface{}); ok {
+		prior.mibDirs = v
+	}
+
+	for _, attr := range []schemaAttr{checkSNMPOID, checkSNMPWalk} {
+		items, _ := snmpConfig[string(attr)].([]interface{})
+		for _, itemRaw := range items {
+			item := newInterfaceMap(itemRaw)
+			name, _ := item[checkSNMPOIDName].(string)
+			path, _ := item[checkSNMPOIDPath].(string)
+			prior.paths[name] = path
+			if tags, ok := item[checkSNMPWalkTagOIDs].(map[string]interface{}); ok {
+				prior.tags[name] = tags
+			}
+		}
+	}
+
+	return prior
+}
+
+// path returns the symbolic OID previously configured for name if it still
+// resolves to oid, otherwise oid itself.
+func (s *checkSNMPState) path(ctx context.Context, name, oid string) string {
+	return s.symbolic(ctx, s.paths[name], oid)
+}
+
+// tagPath is path for the tag_oids entry category of the walk name.
+func (s *checkSNMPState) tagPath(ctx context.Context, name, category, oid string) string {
+	prior, _ := s.tags[name][category].(string)
+	return s.symbolic(ctx, prior, oid)
+}
+
+func (s *checkSNMPState) symbolic(ctx context.Context, prior, oid string) string {
+	if prior == "" || prior == oid || snmpOIDIsNumeric(prior) {
+		return oid
+	}
+
+	if s.mib == nil && s.mibErr == nil {
+		var dirs []string
+		if dirs, s.mibErr = interfaceList(s.mibDirs).List(); s.mibErr == nil {
+			s.mib, s.mibErr = loadSNMPMIBs(dirs)
+		}
+		if s.mibErr != nil {
+			tflog.Warn(ctx, "Unable to load MIB files", map[string]interface{}{logFieldError: s.mibErr.Error()})
+		}
+	}
+	if s.mibErr != nil {
+		return oid
+	}
+
+	if resolved, err := s.mib.Resolve(prior); err == nil && resolved == oid {
+		return prior
+	}
+
+	return oid
+}
+
+// sortSNMPItems orders the oid or walk items read from the API by their
+// position in order.  Items missing from order, e.g. on checks created before
+// the order was recorded, follow sorted by name.
+func sortSNMPItems(items []interface{}, order []string) []interface{} {
+	pos := make(map[string]int, len(order))
+	for i, name := range order {
+		pos[name] = i
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ni := items[i].(map[string]interface{})[string(checkSNMPOIDName)].(string)
+		nj := items[j].(map[string]interface{})[string(checkSNMPOIDName)].(string)
+		pi, iFound := pos[ni]
+		pj, jFound := pos[nj]
+		switch {
+		case iFound && jFound:
+			return pi < pj
+		case iFound != jFound:
+			return iFound
+		default:
+			return ni < nj
+		}
+	})
+
+	return items
+}
+
+func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeSNMPAttr)
 
 	// Iterate over all `snmp` attributes, even though we have a max of 1 in the
 	// schema.
-	for _, mapRaw := range l {
+	for i, mapRaw := range l {
 		snmpConfig := newInterfaceMap(mapRaw)
 
 		if v, found := snmpConfig[checkSNMPAuthPassphrase]; found {
@@ -348,19 +560,107 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error { //nolint:un
 			c.Config[config.Version] = v.(string)
 		}
 
-		if v, found := snmpConfig[checkSNMPOID]; found {
-			ss := v.(*schema.Set)
+		oids, _ := snmpConfig[checkSNMPOID].([]interface{})
+		walks, _ := snmpConfig[checkSNMPWalk].([]interface{})
+		if len(oids) == 0 && len(walks) == 0 {
+			return attrErrorf(attrPath(checkSNMPAttr, i), "at least one %s or %s is required", checkSNMPOID, checkSNMPWalk)
+		}
+
+		// MIB files are only loaded when a symbolic OID needs resolving.
+		var mib *snmpMIB
+		resolve := func(at cty.Path, path string) (string, error) {
+			if snmpOIDIsNumeric(path) {
+				return path, nil
+			}
+
+			if mib == nil {
+				dirsRaw, _ := snmpConfig[checkSNMPMIBDirs].([]interface{})
+				if len(dirsRaw) == 0 {
+					return "", attrErrorf(at, "symbolic OID %q requires %s", path, checkSNMPMIBDirs)
+				}
+
+				dirs, err := interfaceList(dirsRaw).List()
+				if err != nil {
+					return "", attrErrorf(attrPath(checkSNMPAttr, i, checkSNMPMIBDirs), "%v", err)
+				}
+
+				if mib, err = loadSNMPMIBs(dirs); err != nil {
+					return "", attrErrorf(attrPath(checkSNMPAttr, i, checkSNMPMIBDirs), "%v", err)
+				}
+			}
+
+			oid, err := mib.Resolve(path)
+			if err != nil {
+				return "", attrErrorf(at, "%v", err)
+			}
+
+			return oid, nil
+		}
+
+		order := make([]string, 0, len(oids)+len(walks))
+		seen := make(map[string]struct{}, len(oids)+len(walks))
+		addName := func(at cty.Path, name string) error {
+			if _, found := seen[name]; found {
+				return attrErrorf(at, "duplicate %s %q", checkSNMPOIDName, name)
+			}
+			seen[name] = struct{}{}
+			order = append(order, name)
+
+			return nil
+		}
+
+		for j, oidRaw := range oids {
+			oidConfig := newInterfaceMap(oidRaw)
+			name := oidConfig[checkSNMPOIDName].(string)
+			if err := addName(attrPath(checkSNMPAttr, i, checkSNMPOID, j, checkSNMPOIDName), name); err != nil {
+				return err
+			}
 
-			m := ss.List()
-			for _, ll := range m {
-				if ll == nil {
-					continue
+			oid, err := resolve(attrPath(checkSNMPAttr, i, checkSNMPOID, j, checkSNMPOIDPath), oidConfig[checkSNMPOIDPath].(string))
+			if err != nil {
+				return err
+			}
+
+			c.Config[config.OIDPrefix+config.Key(name)] = oid
+			c.Config[config.TypePrefix+config.Key(name)], _ = oidConfig[checkSNMPOIDType].(string)
+		}
+
+		for j, walkRaw := range walks {
+			walkConfig := newInterfaceMap(walkRaw)
+			name := walkConfig[checkSNMPOIDName].(string)
+			if err := addName(attrPath(checkSNMPAttr, i, checkSNMPWalk, j, checkSNMPOIDName), name); err != nil {
+				return err
+			}
+
+			oid, err := resolve(attrPath(checkSNMPAttr, i, checkSNMPWalk, j, checkSNMPOIDPath), walkConfig[checkSNMPOIDPath].(string))
+			if err != nil {
+				return err
+			}
+
+			c.Config[config.Key(apiSNMPWalkPrefix+name)] = oid
+			c.Config[config.TypePrefix+config.Key(name)], _ = walkConfig[checkSNMPOIDType].(string)
+
+			if v, _ := walkConfig[checkSNMPWalkIndexTag].(string); v != "" && v != defaultCheckSNMPWalkIndexTag {
+				c.Config[config.Key(apiSNMPIndexTagPrefix+name)] = v
+			}
+
+			if tags := walkConfig.CollectMap(checkSNMPWalkTagOIDs); len(tags) > 0 {
+				tagOIDs := make(map[string]string, len(tags))
+				for category, path := range tags {
+					if tagOIDs[category], err = resolve(attrPath(checkSNMPAttr, i, checkSNMPWalk, j, checkSNMPWalkTagOIDs), path); err != nil {
+						return err
+					}
+				}
+
+				buf, err := json.Marshal(tagOIDs)
+				if err != nil {
+					return attrErrorf(attrPath(checkSNMPAttr, i, checkSNMPWalk, j, checkSNMPWalkTagOIDs), "unable to encode: %v", err)
 				}
-				n := ll.(map[string]interface{})
-				c.Config[config.Key(fmt.Sprintf("oid_%s", n[string(checkSNMPOIDName)].(string)))] = n[string(checkSNMPOIDPath)].(string)
-				c.Config[config.Key(fmt.Sprintf("type_%s", n[string(checkSNMPOIDName)].(string)))] = n[string(checkSNMPOIDType)].(string)
+				c.Config[config.Key(apiSNMPTagOIDsPrefix+name)] = string(buf)
 			}
 		}
+
+		c.Config[apiSNMPOrder] = strings.Join(order, ",")
 	}
 	return nil
 }
@@ -1,11 +1,15 @@
 package circonus
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -36,9 +40,9 @@ func TestAccCirconusCheckSNMP_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.port", "161"),
 					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.security_name", "admin"),
 					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.#", "3"),
-					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.0.name", "upsBatCapacity"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.0.name", "upsBatVoltage"),
 					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.1.name", "upsBatTimeRemaining"),
-					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.2.name", "upsBatVoltage"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.2.name", "upsBatCapacity"),
 				),
 			},
 		},
@@ -102,3 +106,223 @@ resource "circonus_check" "snmp" {
   target = "127.0.0.1"
 }
 `
+
+func TestAccCirconusCheckSNMP_walk(t *testing.T) {
+	checkName := fmt.Sprintf("SNMP walk check - %s", acctest.RandString(5))
+
+	mibDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(mibDir, "IF-MIB.txt"), []byte(testSNMPIfMIB), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckSNMPWalkConfigFmt, checkName, testAccBroker1, mibDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.mib_dirs.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.oid.0.path", ".1.3.6.1.2.1.1.3.0"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.0.name", "ifHCInOctets"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.0.path", "IF-MIB::ifHCInOctets"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.0.type", "uint64"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.0.index_tag", "ifIndex"),
+					resource.TestCheckResourceAttr("circonus_check.snmp", "snmp.0.walk.0.tag_oids.ifName", "IF-MIB::ifName"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckSNMPWalkConfigFmt = `
+resource "circonus_check" "snmp" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "%s"
+  }
+
+  snmp {
+    version = "2c"
+    community = "public"
+    mib_dirs = ["%s"]
+
+    oid {
+      name = "sysUpTime"
+      path = ".1.3.6.1.2.1.1.3.0"
+      type = "uint32"
+    }
+
+    walk {
+      name = "ifHCInOctets"
+      path = "IF-MIB::ifHCInOctets"
+      type = "uint64"
+      index_tag = "ifIndex"
+      tag_oids = {
+        ifName = "IF-MIB::ifName"
+      }
+    }
+  }
+
+  metric {
+    name = "sysUpTime"
+    type = "numeric"
+  }
+
+  metric {
+    name = "ifHCInOctets"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "127.0.0.1"
+}
+`
+
+func Test_CheckConfigToAPISNMP(t *testing.T) {
+	mibDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(mibDir, "IF-MIB.txt"), []byte(testSNMPIfMIB), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[config.Key]string
+		fail     bool
+		path     cty.Path
+	}{
+		{
+			name: "ordered oids",
+			config: map[string]interface{}{
+				string(checkSNMPOID): []interface{}{
+					map[string]interface{}{"name": "upsBatVoltage", "path": ".1.3.6.1.4.1.318.1.1.1.2.2.8.0", "type": "double"},
+					map[string]interface{}{"name": "upsBatCapacity", "path": ".1.3.6.1.4.1.318.1.1.1.2.2.1.0", "type": ""},
+				},
+			},
+			expected: map[config.Key]string{
+				"oid_upsBatVoltage":   ".1.3.6.1.4.1.318.1.1.1.2.2.8.0",
+				"type_upsBatVoltage":  "double",
+				"oid_upsBatCapacity":  ".1.3.6.1.4.1.318.1.1.1.2.2.1.0",
+				"type_upsBatCapacity": "",
+				apiSNMPOrder:          "upsBatVoltage,upsBatCapacity",
+			},
+		},
+		{
+			name: "symbolic walk",
+			config: map[string]interface{}{
+				string(checkSNMPMIBDirs): []interface{}{mibDir},
+				string(checkSNMPWalk): []interface{}{
+					map[string]interface{}{
+						"name":      "ifHCInOctets",
+						"path":      "IF-MIB::ifHCInOctets",
+						"type":      "uint64",
+						"index_tag": "ifIndex",
+						"tag_oids":  map[string]interface{}{"ifName": "ifName"},
+					},
+				},
+			},
+			expected: map[config.Key]string{
+				"walk_ifHCInOctets":      ".1.3.6.1.2.1.31.1.1.1.6",
+				"type_ifHCInOctets":      "uint64",
+				"index_tag_ifHCInOctets": "ifIndex",
+				"tag_oids_ifHCInOctets":  `{"ifName":".1.3.6.1.2.1.31.1.1.1.1"}`,
+				apiSNMPOrder:             "ifHCInOctets",
+			},
+		},
+		{
+			name: "symbolic oid without mib_dirs",
+			config: map[string]interface{}{
+				string(checkSNMPOID): []interface{}{
+					map[string]interface{}{"name": "ifName", "path": "IF-MIB::ifName.1", "type": ""},
+				},
+			},
+			fail: true,
+			path: attrPath(checkSNMPAttr, 0, checkSNMPOID, 0, checkSNMPOIDPath),
+		},
+		{
+			name: "unknown symbolic oid",
+			config: map[string]interface{}{
+				string(checkSNMPMIBDirs): []interface{}{mibDir},
+				string(checkSNMPOID): []interface{}{
+					map[string]interface{}{"name": "sysDescr", "path": "SNMPv2-MIB::sysDescr.0", "type": ""},
+				},
+			},
+			fail: true,
+			path: attrPath(checkSNMPAttr, 0, checkSNMPOID, 0, checkSNMPOIDPath),
+		},
+		{
+			name: "duplicate names",
+			config: map[string]interface{}{
+				string(checkSNMPOID): []interface{}{
+					map[string]interface{}{"name": "ifIn", "path": ".1.3.6.1.2.1.2.2.1.10.1", "type": ""},
+				},
+				string(checkSNMPWalk): []interface{}{
+					map[string]interface{}{"name": "ifIn", "path": ".1.3.6.1.2.1.2.2.1.10", "type": "", "index_tag": "index"},
+				},
+			},
+			fail: true,
+			path: attrPath(checkSNMPAttr, 0, checkSNMPWalk, 0, checkSNMPOIDName),
+		},
+		{
+			name:   "no oids or walks",
+			config: map[string]interface{}{},
+			fail:   true,
+			path:   attrPath(checkSNMPAttr, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPISNMP(&c, interfaceList{test.config})
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				var attrErr *attributeError
+				if !errors.As(err, &attrErr) || !attrErr.path.Equals(test.path) {
+					t.Errorf("expected an error at %#v, got %v", test.path, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.Config) != len(test.expected) {
+				t.Fatalf("expected %d config keys, got %#v", len(test.expected), c.Config)
+			}
+
+			for k, v := range test.expected {
+				if c.Config[k] != v {
+					t.Errorf("config key %q: expected %q, got %q", k, v, c.Config[k])
+				}
+			}
+		})
+	}
+}
+
+func Test_SortSNMPItems(t *testing.T) {
+	item := func(name string) interface{} {
+		return map[string]interface{}{string(checkSNMPOIDName): name}
+	}
+
+	items := sortSNMPItems([]interface{}{item("d"), item("a"), item("c"), item("b")}, []string{"c", "a"})
+
+	expected := []string{"c", "a", "b", "d"}
+	for i, name := range expected {
+		if got := items[i].(map[string]interface{})[string(checkSNMPOIDName)]; got != name {
+			t.Errorf("item %d: expected %q, got %q", i, name, got)
+		}
+	}
+}
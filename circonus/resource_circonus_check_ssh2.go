@@ -2,17 +2,22 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.ssh2 check type.
+	checkSSH2Attr                     = "ssh2"
+	apiCheckTypeSSH2Attr apiCheckType = "ssh2"
+
 	// circonus_check.ssh2.* resource attribute names.
 	checkSSH2PortAttr          = "port"
 	checkSSH2MethodKexAttr     = "method_kex"
@@ -24,7 +29,15 @@ const (
 	checkSSH2MethodCompCSAttr  = "method_comp_cs"
 	checkSSH2MethodCompSCAttr  = "method_comp_sc"
 	checkSSH2MethodLangCSAttr  = "method_lang_cs"
-	checkSSH2MethodLangSCAttr  = "method_land_sc"
+	checkSSH2MethodLangSCAttr  = "method_lang_sc"
+
+	// checkSSH2MethodLandSCAttr is the misspelled name used for
+	// method_lang_sc before schema version 1.
+	checkSSH2MethodLandSCAttr = "method_land_sc"
+
+	// Broker config key for the server to client language, which
+	// go-apiclient has no constant for.
+	apiSSH2MethodLangSC = config.Key("method_lang_sc")
 )
 
 var checkSSH2Descriptions = attrDescrs{
@@ -39,6 +52,18 @@ var checkSSH2Descriptions = attrDescrs{
 	checkSSH2MethodCompSCAttr:  "The compress algorithm used from server to client",
 	checkSSH2MethodLangCSAttr:  "The language used from client to server",
 	checkSSH2MethodLangSCAttr:  "The language used from server to client",
+	checkSSH2MethodLandSCAttr:  "Deprecated spelling of method_lang_sc",
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeSSH2Attr,
+		attr:        checkSSH2Attr,
+		description: "SSH2 check configuration",
+		schema:      schemaCheckSSH2,
+		toAPI:       checkConfigToAPISSH2,
+		toState:     checkAPIToStateSSH2,
+	})
 }
 
 var schemaCheckSSH2 = &schema.Schema{
@@ -114,13 +139,19 @@ var schemaCheckSSH2 = &schema.Schema{
 				Optional:     true,
 				ValidateFunc: validateRegexp(checkSSH2MethodLangSCAttr, `^(?:|\w+)$`),
 			},
+			checkSSH2MethodLandSCAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkSSH2MethodLandSCAttr, `^(?:|\w+)$`),
+				Deprecated:   fmt.Sprintf("use %s instead", checkSSH2MethodLangSCAttr),
+			},
 		}),
 	},
 }
 
 // checkAPIToStateSSH2 reads the Config data out of circonusCheck.CheckBundle into
 // the statefile.
-func checkAPIToStateSSH2(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateSSH2(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	ssh2Config := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -141,7 +172,7 @@ func checkAPIToStateSSH2(c *circonusCheck, d *schema.ResourceData) error {
 		if s, ok := c.Config[apiKey]; ok && s != "0" {
 			i, err := strconv.ParseInt(s, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			ssh2Config[string(attrName)] = int(i)
@@ -160,13 +191,19 @@ func checkAPIToStateSSH2(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.MethodCompCS, checkSSH2MethodCompCSAttr)
 	saveStringConfigToState(config.MethodCompSC, checkSSH2MethodCompSCAttr)
 	// saveStringConfigToState(config.MethodLangCS, checkSSH2MethodLangCSAttr)
-	// saveStringConfigToState(config.MethodLangSC, checkSSH2MethodLangSCAttr)
+
+	// Keep the language under whichever name is already in use so that
+	// configurations still using the deprecated name don't show a diff.
+	var langSCAttr schemaAttr = checkSSH2MethodLangSCAttr
+	if ssh2UsesDeprecatedMethodLangSC(d) {
+		langSCAttr = checkSSH2MethodLandSCAttr
+	}
+	saveStringConfigToState(apiSSH2MethodLangSC, langSCAttr)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey:      {},
 		config.SubmissionURL:         {},
 		config.Key("method_lang_cs"): {},
-		config.Key("method_lang_sc"): {},
 	}
 
 	for k := range swamp {
@@ -218,13 +255,14 @@ func checkSSH2ConfigChecksum(v interface{}) int {
 	writeString(checkSSH2MethodCompSCAttr)
 	writeString(checkSSH2MethodLangCSAttr)
 	writeString(checkSSH2MethodLangSCAttr)
+	writeString(checkSSH2MethodLandSCAttr)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPISSH2(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeSSH2)
+func checkConfigToAPISSH2(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeSSH2Attr)
 
 	for _, mapRaw := range l {
 		ssh2Config := newInterfaceMap(mapRaw)
@@ -272,12 +310,38 @@ func checkConfigToAPISSH2(c *circonusCheck, l interfaceList) error { //nolint:un
 			if v, found := ssh2Config[checkSSH2MethodLangCSAttr]; found {
 				c.Config[config.MethodLangCS] = v.(string)
 			}
-
-			if v, found := ssh2Config[checkSSH2MethodLangSCAttr]; found {
-				c.Config[config.MethodLangSC] = v.(string)
-			}
 		*/
+
+		langSC, _ := ssh2Config[checkSSH2MethodLangSCAttr].(string)
+		deprecatedLangSC, _ := ssh2Config[checkSSH2MethodLandSCAttr].(string)
+		switch {
+		case langSC != "" && deprecatedLangSC != "":
+			return attrErrorf(attrPath(checkSSH2Attr), "only one of %s or %s may be set", checkSSH2MethodLangSCAttr, checkSSH2MethodLandSCAttr)
+		case langSC == "":
+			langSC = deprecatedLangSC
+		}
+
+		if langSC != "" {
+			c.Config[apiSSH2MethodLangSC] = langSC
+		}
 	}
 
 	return nil
 }
+
+// ssh2UsesDeprecatedMethodLangSC reports whether the ssh2 block in the state
+// stores method_lang_sc under the pre-version 1 attribute name.
+func ssh2UsesDeprecatedMethodLangSC(d *schema.ResourceData) bool {
+	v, ok := d.GetOk(checkSSH2Attr)
+	if !ok {
+		return false
+	}
+
+	for _, mapRaw := range v.(*schema.Set).List() {
+		if s, _ := newInterfaceMap(mapRaw)[checkSSH2MethodLandSCAttr].(string); s != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -9,6 +9,41 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+func Test_CheckConfigToAPISSH2LangSC(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+		fail     bool
+	}{
+		{"unset", map[string]interface{}{"method_lang_sc": ""}, "", false},
+		{"method_lang_sc", map[string]interface{}{"method_lang_sc": "en"}, "en", false},
+		{"method_land_sc", map[string]interface{}{"method_lang_sc": "", "method_land_sc": "en"}, "en", false},
+		{"both", map[string]interface{}{"method_lang_sc": "en", "method_land_sc": "de"}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPISSH2(&c, interfaceList{test.config})
+			if test.fail {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if v := c.Config[apiSSH2MethodLangSC]; v != test.expected {
+				t.Errorf("expected %s %q, got %q", apiSSH2MethodLangSC, test.expected, v)
+			}
+		})
+	}
+}
+
 func TestAccCirconusCheckSSH2_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -0,0 +1,107 @@
+package circonus
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// checkStateUpgradeV0 moves values stored under the misspelled attribute names
+// used before schema version 1 to their corrected names:
+//
+//	cloudwatch.dimmensions -> cloudwatch.dimensions
+//	ssh2.method_land_sc    -> ssh2.method_lang_sc
+//
+// Both spellings hash the same in the cloudwatch and ssh2 sets, so
+// configurations still using the misspelled names plan clean.
+func checkStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	renameCheckStateAttr(ctx, rawState, checkCloudWatchAttr, checkCloudWatchDimmensionsAttr, checkCloudWatchDimensionsAttr)
+	renameCheckStateAttr(ctx, rawState, checkSSH2Attr, checkSSH2MethodLandSCAttr, checkSSH2MethodLangSCAttr)
+
+	return rawState, nil
+}
+
+// checkStateUpgradeV1 names the metric filters of a version 1 state, which
+// were an ordered list.  Each filter is given the name and priority a filter
+// read without a recorded name gets, so the order of the list is kept.
+func checkStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	filters, ok := rawState[checkMetricFilterAttr].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+
+	for i, filterRaw := range filters {
+		filter, ok := filterRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if name, _ := filter[checkMetricFilterNameAttr].(string); name == "" {
+			filter[checkMetricFilterNameAttr] = defaultCheckMetricFilterName(i)
+		}
+
+		if _, found := filter[checkMetricFilterPriorityAttr]; !found {
+			filter[checkMetricFilterPriorityAttr] = defaultCheckMetricFilterPriority(i)
+		}
+	}
+
+	tflog.Debug(ctx, "Named metric filters during state upgrade", map[string]interface{}{
+		"count": len(filters),
+	})
+
+	return rawState, nil
+}
+
+// renameCheckStateAttr renames oldAttr to newAttr in every element of the
+// check type block blockAttr.  A value already present under newAttr is kept.
+func renameCheckStateAttr(ctx context.Context, rawState map[string]interface{}, blockAttr, oldAttr, newAttr schemaAttr) {
+	elems, ok := rawState[string(blockAttr)].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, elemRaw := range elems {
+		elem, ok := elemRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		v, found := elem[string(oldAttr)]
+		if !found {
+			continue
+		}
+		delete(elem, string(oldAttr))
+
+		if cur, found := elem[string(newAttr)]; found && !emptyStateValue(cur) {
+			continue
+		}
+
+		elem[string(newAttr)] = v
+		tflog.Debug(ctx, "Renamed check attribute during state upgrade", map[string]interface{}{
+			"block": string(blockAttr),
+			"from":  string(oldAttr),
+			"to":    string(newAttr),
+		})
+	}
+}
+
+func emptyStateValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,172 @@
+package circonus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_CheckStateUpgradeV0(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawState map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no check type blocks",
+			rawState: map[string]interface{}{"name": "foo"},
+			expected: map[string]interface{}{"name": "foo"},
+		},
+		{
+			name: "cloudwatch dimmensions",
+			rawState: map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"dimmensions": map[string]interface{}{"DBInstanceIdentifier": "atlas-production"},
+						"namespace":   "AWS/RDS",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"dimensions": map[string]interface{}{"DBInstanceIdentifier": "atlas-production"},
+						"namespace":  "AWS/RDS",
+					},
+				},
+			},
+		},
+		{
+			name: "ssh2 method_land_sc",
+			rawState: map[string]interface{}{
+				"ssh2": []interface{}{
+					map[string]interface{}{
+						"method_land_sc": "en",
+						"port":           22,
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"ssh2": []interface{}{
+					map[string]interface{}{
+						"method_lang_sc": "en",
+						"port":           22,
+					},
+				},
+			},
+		},
+		{
+			name: "corrected name already set",
+			rawState: map[string]interface{}{
+				"ssh2": []interface{}{
+					map[string]interface{}{
+						"method_land_sc": "",
+						"method_lang_sc": "en",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"ssh2": []interface{}{
+					map[string]interface{}{
+						"method_lang_sc": "en",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := checkStateUpgradeV0(context.Background(), test.rawState, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_CheckStateUpgradeV0Hash(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     func(interface{}) int
+		old, new map[string]interface{}
+	}{
+		{
+			name: "cloudwatch",
+			hash: hashCheckCloudWatch,
+			old:  map[string]interface{}{"dimmensions": map[string]interface{}{"DBInstanceIdentifier": "atlas-production"}, "namespace": "AWS/RDS"},
+			new:  map[string]interface{}{"dimensions": map[string]interface{}{"DBInstanceIdentifier": "atlas-production"}, "namespace": "AWS/RDS"},
+		},
+		{
+			name: "ssh2",
+			hash: checkSSH2ConfigChecksum,
+			old:  map[string]interface{}{"method_land_sc": "en", "port": 22},
+			new:  map[string]interface{}{"method_lang_sc": "en", "port": 22},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.hash(test.old) != test.hash(test.new) {
+				t.Errorf("expected both spellings to hash the same")
+			}
+		})
+	}
+}
+
+func Test_CheckStateUpgradeV1(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawState map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no metric filters",
+			rawState: map[string]interface{}{"name": "foo"},
+			expected: map[string]interface{}{"name": "foo"},
+		},
+		{
+			name: "metric filters",
+			rawState: map[string]interface{}{
+				"metric_filter": []interface{}{
+					map[string]interface{}{"type": "allow", "regex": "^cpu"},
+					map[string]interface{}{"type": "deny", "regex": ".*"},
+				},
+			},
+			expected: map[string]interface{}{
+				"metric_filter": []interface{}{
+					map[string]interface{}{"type": "allow", "regex": "^cpu", "name": "filter_1", "priority": 10},
+					map[string]interface{}{"type": "deny", "regex": ".*", "name": "filter_2", "priority": 20},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := checkStateUpgradeV1(context.Background(), test.rawState, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_CheckStateUpgraderType(t *testing.T) {
+	r := resourceCheck()
+	if r.SchemaVersion != 2 {
+		t.Fatalf("expected schema version 2, got %d", r.SchemaVersion)
+	}
+
+	if err := r.InternalValidate(nil, true); err != nil {
+		t.Fatalf("resource failed validation: %v", err)
+	}
+}
@@ -1,12 +1,19 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.statsd check type.
+	checkStatsdAttr                     = "statsd"
+	apiCheckTypeStatsdAttr apiCheckType = "statsd"
+
 	// circonus_check.statsd.* resource attribute names.
 	checkStatsdSourceIPAttr = "source_ip"
 )
@@ -15,6 +22,21 @@ var checkStatsdDescriptions = attrDescrs{
 	checkStatsdSourceIPAttr: "The source IP of the statsd metrics stream",
 }
 
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeStatsdAttr,
+		attr:        checkStatsdAttr,
+		description: "statsd check configuration",
+		schema:      schemaCheckStatsd,
+		toAPI:       checkConfigToAPIStatsd,
+		toState:     checkAPIToStateStatsd,
+		planInputs:  []string{checkTargetAttr},
+
+		submissionURL:  checkStatsdSubmissionURL,
+		submissionPort: defaultCheckStatsdPort,
+	})
+}
+
 var schemaCheckStatsd = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -33,7 +55,7 @@ var schemaCheckStatsd = &schema.Schema{
 
 // checkAPIToStateStatsd reads the Config data out of circonusCheck.CheckBundle
 // into the statefile.
-func checkAPIToStateStatsd(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateStatsd(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	statsdConfig := make(map[string]interface{}, len(c.Config))
 
 	// Unconditionally map the target to the source_ip config attribute
@@ -46,8 +68,13 @@ func checkAPIToStateStatsd(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
+// checkStatsdSubmissionURL returns the address statsd metrics are sent to.
+func checkStatsdSubmissionURL(c *circonusCheck, host string, port uint16) string {
+	return fmt.Sprintf("udp://%s", net.JoinHostPort(host, strconv.Itoa(int(port))))
+}
+
 func checkConfigToAPIStatsd(c *circonusCheck, l interfaceList) error {
-	c.Type = string(apiCheckTypeStatsd)
+	c.Type = string(apiCheckTypeStatsdAttr)
 
 	// Iterate over all `statsd` attributes, even though we have a max of 1 in the
 	// schema.
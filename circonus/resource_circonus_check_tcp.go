@@ -2,17 +2,22 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
+	// circonus_check.tcp check type.
+	checkTCPAttr                     = "tcp"
+	apiCheckTypeTCPAttr apiCheckType = "tcp"
+
 	// circonus_check.tcp.* resource attribute names.
 	checkTCPBannerRegexpAttr = "banner_regexp"
 	checkTCPCAChainAttr      = "ca_chain"
@@ -33,6 +38,23 @@ var checkTCPDescriptions = attrDescrs{
 	checkTCPKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks)",
 	checkTCPPortAttr:         "Specifies the port on which the management interface can be reached.",
 	checkTCPTLSAttr:          "Upgrade TCP connection to use TLS.",
+
+	checkTLSCertExpiryAttr:    checkTLSDescriptions[checkTLSCertExpiryAttr],
+	checkTLSExpectedNamesAttr: checkTLSDescriptions[checkTLSExpectedNamesAttr],
+	checkTLSMinVersionAttr:    checkTLSDescriptions[checkTLSMinVersionAttr],
+	checkTLSSNIAttr:           checkTLSDescriptions[checkTLSSNIAttr],
+	checkTLSVerifyModeAttr:    checkTLSDescriptions[checkTLSVerifyModeAttr],
+}
+
+func init() {
+	registerCheckType(&checkType{
+		apiType:     apiCheckTypeTCPAttr,
+		attr:        checkTCPAttr,
+		description: "TCP check configuration",
+		schema:      schemaCheckTCP,
+		toAPI:       checkConfigToAPITCP,
+		toState:     checkAPIToStateTCP,
+	})
 }
 
 var schemaCheckTCP = &schema.Schema{
@@ -42,7 +64,7 @@ var schemaCheckTCP = &schema.Schema{
 	MinItems: 1,
 	Set:      hashCheckTCP,
 	Elem: &schema.Resource{
-		Schema: convertToHelperSchema(checkTCPDescriptions, map[schemaAttr]*schema.Schema{
+		Schema: convertToHelperSchema(checkTCPDescriptions, addCheckTLSSchema(map[schemaAttr]*schema.Schema{
 			checkTCPBannerRegexpAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -86,13 +108,13 @@ var schemaCheckTCP = &schema.Schema{
 				Optional: true,
 				Default:  false,
 			},
-		}),
+		})),
 	},
 }
 
 // checkAPIToStateTCP reads the Config data out of circonusCheck.CheckBundle into the
 // statefile.
-func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
+func checkAPIToStateTCP(ctx context.Context, c *circonusCheck, d *schema.ResourceData) error {
 	tcpConfig := make(map[string]interface{}, len(c.Config))
 
 	// swamp is a sanity check: it must be empty by the time this method returns
@@ -109,7 +131,7 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 			case "0", "false", "f", "no", "n":
 				tcpConfig[string(attrName)] = false
 			default:
-				log.Printf("PROVIDER BUG: unsupported boolean: %q for API Config Key %q", s, string(apiKey))
+				tflog.Error(ctx, "PROVIDER BUG: unsupported boolean in API config", map[string]interface{}{"config_key": string(apiKey), "value": s})
 				return
 			}
 		}
@@ -121,7 +143,7 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 		if v, ok := c.Config[apiKey]; ok {
 			i, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				tflog.Error(ctx, "Unable to convert API config value to an integer", map[string]interface{}{"config_key": string(apiKey), logFieldError: err.Error()})
 				return
 			}
 			tcpConfig[string(attrName)] = int(i)
@@ -147,6 +169,8 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 	saveIntConfigToState(config.Port, checkTCPPortAttr)
 	saveBoolConfigToState(config.UseSSL, checkTCPTLSAttr)
 
+	checkTLSAPIToState(c, d, checkTCPAttr, swamp, tcpConfig)
+
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
 		config.SubmissionURL:    {},
@@ -158,7 +182,7 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if _, ok := whitelistedConfigKeys[k]; !ok {
-			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+			tflog.Error(ctx, "PROVIDER BUG: API Config not empty", map[string]interface{}{"config_keys": configKeys(swamp)})
 		}
 	}
 
@@ -204,12 +228,14 @@ func hashCheckTCP(v interface{}) int {
 	writeInt(checkTCPPortAttr)
 	writeBool(checkTCPTLSAttr)
 
+	hashCheckTLS(b, m)
+
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error { //nolint:unparam
-	c.Type = string(apiCheckTypeTCP)
+func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeTCPAttr)
 
 	// Iterate over all `tcp` attributes, even though we have a max of 1 in the
 	// schema.
@@ -247,6 +273,14 @@ func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error { //nolint:unp
 		if v, found := tcpConfig[checkTCPTLSAttr]; found {
 			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
 		}
+
+		if tls, _ := tcpConfig[checkTCPTLSAttr].(bool); !tls && checkTLSSet(tcpConfig) {
+			return attrErrorf(attrPath(checkTCPAttr), "TLS settings require %s to be true", checkTCPTLSAttr)
+		}
+
+		if err := checkTLSConfigToAPI(c, checkTCPAttr, tcpConfig); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -82,6 +82,41 @@ func TestAccCirconusCheckTCP_basic(t *testing.T) {
 	})
 }
 
+func TestAccCirconusCheckTCP_certExpiry(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: TCP+TLS cert expiry - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckTCPCertExpiryConfigFmt, checkName, testAccBroker1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.sni", "www.circonus.com"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.verify_mode", "verify-full"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.min_tls_version", "1.2"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.expected_cert_names.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.expected_cert_names.0", "www.circonus.com"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.cert_expiry.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.cert_expiry.0.days", "21"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tcp.0.cert_expiry.0.severity", "2"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "cert_expiry_rule_sets.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "metric.0.name", "duration"),
+				),
+			},
+			{
+				ResourceName:            "circonus_check.tls_cert",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"cert_expiry_rule_sets", "metric", "tcp.0.cert_expiry", "timeouts"},
+			},
+		},
+	})
+}
+
 const testAccCirconusCheckTCPConfigFmt = `
 variable "tcp_check_tags" {
   type = list(string)
@@ -151,3 +186,37 @@ resource "circonus_check" "tls_cert" {
   tags = "${var.tcp_check_tags}"
 }
 `
+
+const testAccCirconusCheckTCPCertExpiryConfigFmt = `
+resource "circonus_check" "tls_cert" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "%s"
+  }
+
+  tcp {
+    host = "www.circonus.com"
+    port = 443
+    tls = true
+    sni = "www.circonus.com"
+    verify_mode = "verify-full"
+    min_tls_version = "1.2"
+    expected_cert_names = [ "www.circonus.com" ]
+
+    cert_expiry {
+      days = 21
+      severity = 2
+    }
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  tags = [ "lifecycle:unittest" ]
+}
+`
@@ -0,0 +1,673 @@
+package circonus
+
+// The `circonus_check_template` resource stamps one check bundle per target
+// out of a single check configuration.  The bundles share everything but their
+// target, tags and name, and are managed in batches.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	api "github.com/circonus-labs/go-apiclient"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check_template.* resource attribute names.
+	checkTemplateBatchSizeAttr = "batch_size"
+	checkTemplateTargetsAttr   = "targets"
+
+	// circonus_check_template.targets.* resource attribute names.
+	checkTemplateTargetKeyAttr    = "key"
+	checkTemplateTargetTagsAttr   = "tags"
+	checkTemplateTargetTargetAttr = "target"
+
+	// Out parameters for circonus_check_template.
+	checkTemplateOutBundleIDsAttr = "bundle_ids"
+	checkTemplateOutUUIDsAttr     = "uuids"
+
+	defaultCheckTemplateBatchSize = 10
+)
+
+// checkTemplateSharedAttrs are the circonus_check attributes a template shares
+// with every bundle it stamps, in addition to the check type blocks.
+var checkTemplateSharedAttrs = []schemaAttr{
+	checkActiveAttr,
+	checkCollectorAttr,
+	checkMetricAttr,
+	checkMetricFilterAttr,
+	checkMetricLimitAttr,
+	checkNameAttr,
+	checkNotesAttr,
+	checkPeriodAttr,
+	checkTagsAttr,
+	checkTimeoutAttr,
+}
+
+// checkTemplateUnsupported are check type attributes that manage state per
+// check, which a template can't keep for each of its bundles.
+var checkTemplateUnsupported = []struct {
+	blockAttr schemaAttr
+	attr      schemaAttr
+}{
+	{checkHTTPAttr, checkTLSCertExpiryAttr},
+	{checkHTTPTrapAttr, checkHTTPTrapGenerateSecretAttr},
+	{checkTCPAttr, checkTLSCertExpiryAttr},
+}
+
+var checkTemplateDescriptions = attrDescrs{
+	checkTemplateBatchSizeAttr: "The number of bundles created, updated or deleted at a time",
+	checkTemplateTargetsAttr:   "The targets to stamp a check bundle for, keyed by key",
+
+	checkTemplateOutBundleIDsAttr: "The check bundle ID of each target, by key",
+	checkTemplateOutUUIDsAttr:     "The comma separated check UUIDs of each target, by key",
+}
+
+var checkTemplateTargetDescriptions = attrDescrs{
+	checkTemplateTargetKeyAttr:    "Uniquely identifies the target within the template",
+	checkTemplateTargetTagsAttr:   "Tags added to the template's tags for this target",
+	checkTemplateTargetTargetAttr: "The target of the check bundle (e.g. hostname, URL, IP, etc)",
+}
+
+// checkTemplateTarget is one element of a template's targets.
+type checkTemplateTarget struct {
+	Key    string
+	Target string
+	Tags   []string
+}
+
+// checkTemplateTargetConfig reads the configuration of the bundle stamped for
+// one target: the template's configuration with the target's name, target and
+// tags.
+type checkTemplateTargetConfig struct {
+	checkConfigReader
+	target checkTemplateTarget
+}
+
+func (r checkTemplateTargetConfig) GetOk(key string) (interface{}, bool) {
+	switch key {
+	case checkNameAttr:
+		v, _ := r.checkConfigReader.GetOk(key)
+		name, _ := v.(string)
+		return checkTemplateBundleName(name, r.target.Key), true
+	case checkTargetAttr:
+		return r.target.Target, r.target.Target != ""
+	case checkTagsAttr:
+		tags := schema.NewSet(schema.HashString, nil)
+		if v, found := r.checkConfigReader.GetOk(key); found {
+			for _, tag := range v.(*schema.Set).List() {
+				tags.Add(tag)
+			}
+		}
+		for _, tag := range r.target.Tags {
+			tags.Add(tag)
+		}
+		return tags, tags.Len() > 0
+	}
+
+	return r.checkConfigReader.GetOk(key)
+}
+
+func resourceCheckTemplate() *schema.Resource {
+	checkSchema := resourceCheck().Schema
+
+	templateSchema := map[schemaAttr]*schema.Schema{
+		checkTemplateBatchSizeAttr: {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      defaultCheckTemplateBatchSize,
+			ValidateFunc: validateIntMin(checkTemplateBatchSizeAttr, 1),
+		},
+		checkTemplateTargetsAttr: {
+			Type:     schema.TypeSet,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: convertToHelperSchema(checkTemplateTargetDescriptions, map[schemaAttr]*schema.Schema{
+					checkTemplateTargetKeyAttr: {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateRegexp(checkTemplateTargetKeyAttr, `^\S+$`),
+					},
+					checkTemplateTargetTagsAttr: tagMakeConfigSchema(checkTemplateTargetTagsAttr),
+					checkTemplateTargetTargetAttr: {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateRegexp(checkTemplateTargetTargetAttr, `.+`),
+					},
+				}),
+			},
+		},
+		checkTemplateOutBundleIDsAttr: {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		checkTemplateOutUUIDsAttr: {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+
+	descriptions := make(attrDescrs, len(checkTemplateDescriptions)+len(checkTemplateSharedAttrs))
+	for attr, descr := range checkTemplateDescriptions {
+		descriptions[attr] = descr
+	}
+
+	for _, attr := range checkTemplateSharedAttrs {
+		templateSchema[attr] = checkSchema[string(attr)]
+		descriptions[attr] = checkDescriptions[attr]
+	}
+
+	// Every bundle runs on the listed collectors, they aren't selected.
+	collector := *checkSchema[checkCollectorAttr]
+	collector.Optional = false
+	collector.Computed = false
+	collector.Required = true
+	templateSchema[checkCollectorAttr] = &collector
+
+	for _, t := range registeredCheckTypes() {
+		templateSchema[t.attr] = checkSchema[string(t.attr)]
+		descriptions[t.attr] = checkDescriptions[t.attr]
+	}
+
+	return &schema.Resource{
+		CreateContext: checkTemplateCreate,
+		ReadContext:   checkTemplateRead,
+		UpdateContext: checkTemplateUpdate,
+		DeleteContext: checkTemplateDelete,
+		CustomizeDiff: checkTemplateCustomizeDiff,
+		Timeouts:      resourceTimeouts(),
+
+		Schema: convertToHelperSchema(descriptions, templateSchema),
+	}
+}
+
+func checkTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheckTemplate, "")
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return diag.Errorf("check template ID creation failed: %v", err)
+	}
+	d.SetId(id)
+
+	targets := checkTemplateTargets(d.Get(checkTemplateTargetsAttr))
+	if diags := checkTemplateApply(ctx, ctxt, d, map[string]string{}, nil, targets, nil); diags.HasError() {
+		return diags
+	}
+
+	return checkTemplateRead(ctx, d, meta)
+}
+
+// checkTemplateRead refreshes the template's bundles.  A bundle that no longer
+// exists is dropped along with its target, and a bundle changed outside of
+// Terraform has its target dropped, so the next plan puts the target back.
+func checkTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheckTemplate, d.Id())
+
+	bundleIDs := checkTemplateBundleIDs(d.Get(checkTemplateOutBundleIDsAttr))
+	targets := checkTemplateTargets(d.Get(checkTemplateTargetsAttr))
+
+	// ResourceData isn't safe for concurrent use, so the wanted bundle of
+	// every target is parsed up front.
+	keys := sortedKeys(bundleIDs)
+	wants := make(map[string]*circonusCheck, len(keys))
+	parseErrs := make(map[string]error)
+	for _, key := range keys {
+		target, found := targets[key]
+		if !found {
+			continue
+		}
+
+		want := newCheck()
+		if err := want.ParseConfig(checkTemplateTargetConfig{checkConfigReader: d, target: target}); err != nil {
+			parseErrs[key] = err
+			continue
+		}
+		wants[key] = &want
+	}
+
+	var mu sync.Mutex
+	uuids := make(map[string]interface{}, len(bundleIDs))
+	errs := runCheckTemplateBatches(ctx, keys, d.Get(checkTemplateBatchSizeAttr).(int), func(ctx context.Context, key string) error {
+		mu.Lock()
+		cid := bundleIDs[key]
+		mu.Unlock()
+
+		c, err := loadCheck(ctx, ctxt, api.CIDType(&cid))
+		if err != nil && !apiNotFound(err) {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if apiNotFound(err) || c.CID == "" {
+			tflog.Warn(ctx, "Check bundle of a template target no longer exists", map[string]interface{}{"target": key, "bundle": cid})
+			delete(bundleIDs, key)
+			delete(targets, key)
+			return nil
+		}
+
+		uuids[key] = strings.Join(c.CheckUUIDs, ",")
+
+		want, found := wants[key]
+		if !found {
+			return nil
+		}
+
+		if checkTemplateBundleDrifted(want, &c) {
+			tflog.Info(ctx, "Check bundle of a template target changed outside of Terraform", map[string]interface{}{"target": key, "bundle": cid})
+			delete(targets, key)
+		}
+
+		return nil
+	})
+	for key, err := range parseErrs {
+		errs[key] = err
+	}
+	if diags := checkTemplateDiags(keys, errs); diags.HasError() {
+		return diags
+	}
+
+	if err := d.Set(checkTemplateTargetsAttr, checkTemplateTargetsToState(targets)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkTemplateOutBundleIDsAttr, bundleIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkTemplateOutUUIDsAttr, uuids); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func checkTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheckTemplate, d.Id())
+
+	o, n := d.GetChange(checkTemplateTargetsAttr)
+	oldTargets := checkTemplateTargets(o)
+	newTargets := checkTemplateTargets(n)
+
+	// The bundle IDs are unknown in the plan when the targets change.
+	o, _ = d.GetChange(checkTemplateOutBundleIDsAttr)
+	bundleIDs := checkTemplateBundleIDs(o)
+
+	// Bundles only need updating when the configuration they share changes or
+	// their own target does.
+	shared := d.HasChangesExcept(checkTemplateTargetsAttr, checkTemplateBatchSizeAttr, checkTemplateOutBundleIDsAttr, checkTemplateOutUUIDsAttr)
+
+	apply := make(map[string]checkTemplateTarget, len(newTargets))
+	for key, target := range newTargets {
+		if old, found := oldTargets[key]; !shared && found && bundleIDs[key] != "" && reflect.DeepEqual(old, target) {
+			continue
+		}
+
+		apply[key] = target
+	}
+
+	remove := make([]string, 0)
+	for key := range bundleIDs {
+		if _, found := newTargets[key]; !found {
+			remove = append(remove, key)
+		}
+	}
+
+	if diags := checkTemplateApply(ctx, ctxt, d, bundleIDs, oldTargets, apply, remove); diags.HasError() {
+		return diags
+	}
+
+	return checkTemplateRead(ctx, d, meta)
+}
+
+func checkTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeCheckTemplate, d.Id())
+
+	bundleIDs := checkTemplateBundleIDs(d.Get(checkTemplateOutBundleIDsAttr))
+	if diags := checkTemplateApply(ctx, ctxt, d, bundleIDs, checkTemplateTargets(d.Get(checkTemplateTargetsAttr)), nil, sortedKeys(bundleIDs)); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// checkTemplateApply creates or updates the bundles of the targets in apply
+// and deletes the bundles of the keys in remove, batch_size at a time.
+// bundleIDs holds the template's bundles before the apply and is updated with
+// the bundles created and deleted.  The
+// template's state is updated with what was done: a target whose bundle
+// failed is left out of the state so the next plan retries it, and a removed
+// target whose bundle couldn't be deleted is kept.
+func checkTemplateApply(ctx context.Context, ctxt *providerContext, d *schema.ResourceData, bundleIDs map[string]string, oldTargets, apply map[string]checkTemplateTarget, remove []string) diag.Diagnostics {
+	batchSize := d.Get(checkTemplateBatchSizeAttr).(int)
+
+	// ResourceData isn't safe for concurrent use, so the bundle of every
+	// target is parsed up front and the batches only make API calls.
+	applyKeys := sortedKeys(apply)
+	checks := make(map[string]*circonusCheck, len(applyKeys))
+	parseErrs := make(map[string]error)
+	batchKeys := make([]string, 0, len(applyKeys))
+	for _, key := range applyKeys {
+		c := newCheck()
+		if err := c.ParseConfig(checkTemplateTargetConfig{checkConfigReader: d, target: apply[key]}); err != nil {
+			parseErrs[key] = err
+			continue
+		}
+		c.CID = bundleIDs[key]
+
+		checks[key] = &c
+		batchKeys = append(batchKeys, key)
+	}
+
+	var mu sync.Mutex
+	applyErrs := runCheckTemplateBatches(ctx, batchKeys, batchSize, func(ctx context.Context, key string) error {
+		c := checks[key]
+		if c.CID == "" {
+			if err := c.Create(ctx, ctxt); err != nil {
+				return err
+			}
+		} else if err := c.Update(ctx, ctxt); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		bundleIDs[key] = c.CID
+		mu.Unlock()
+
+		return nil
+	})
+	for key, err := range parseErrs {
+		applyErrs[key] = err
+	}
+
+	removeErrs := runCheckTemplateBatches(ctx, remove, batchSize, func(ctx context.Context, key string) error {
+		mu.Lock()
+		cid := bundleIDs[key]
+		mu.Unlock()
+
+		err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+			_, err := client.Delete(cid)
+			return err
+		})
+		if err != nil && !apiNotFound(err) {
+			return err
+		}
+
+		mu.Lock()
+		delete(bundleIDs, key)
+		mu.Unlock()
+
+		return nil
+	})
+
+	targets := checkTemplateTargets(d.Get(checkTemplateTargetsAttr))
+	for key := range applyErrs {
+		delete(targets, key)
+	}
+	for key := range removeErrs {
+		if old, found := oldTargets[key]; found {
+			targets[key] = old
+		}
+	}
+
+	if err := d.Set(checkTemplateTargetsAttr, checkTemplateTargetsToState(targets)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkTemplateOutBundleIDsAttr, bundleIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return append(checkTemplateDiags(applyKeys, applyErrs), checkTemplateDiags(remove, removeErrs)...)
+}
+
+// checkTemplateCustomizeDiff validates the bundle of every target at plan
+// time, the way circonus_check validates its check.
+func checkTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	known := checkPlanKnown(d)
+
+	if d.HasChange(checkTemplateTargetsAttr) {
+		for _, attr := range []string{checkTemplateOutBundleIDsAttr, checkTemplateOutUUIDsAttr} {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, u := range checkTemplateUnsupported {
+		if checkTemplateBlockSets(d, u.blockAttr, u.attr) {
+			return planError(attrErrorf(attrPath(u.blockAttr), "%s is not supported by %s", u.attr, resourceTypeCheckTemplate))
+		}
+	}
+
+	checkType, ok, err := checkPlanType(d, known)
+	if err != nil || !ok {
+		return planError(err)
+	}
+
+	inputs := []string{checkMetricAttr, checkMetricFilterAttr, checkPeriodAttr, checkTimeoutAttr, checkTemplateTargetsAttr, checkType}
+	inputs = append(inputs, checkTypesByAttr[schemaAttr(checkType)].planInputs...)
+
+	if !known(inputs...) {
+		tflog.Debug(ctx, "Skipping plan time check template validation, configuration contains unknown values")
+		return nil
+	}
+
+	targets := checkTemplateTargets(d.Get(checkTemplateTargetsAttr))
+	for _, key := range sortedKeys(targets) {
+		c := newCheck()
+		if err := c.ParseConfig(checkTemplateTargetConfig{checkConfigReader: d, target: targets[key]}); err != nil {
+			return planError(err)
+		}
+	}
+
+	return nil
+}
+
+// checkTemplateBlockSets reports whether attr is set in the check type block
+// blockAttr of d.
+func checkTemplateBlockSets(d checkConfigReader, blockAttr, attr schemaAttr) bool {
+	v, found := d.GetOk(string(blockAttr))
+	if !found {
+		return false
+	}
+
+	var blocks []interface{}
+	switch v := v.(type) {
+	case *schema.Set:
+		blocks = v.List()
+	case []interface{}:
+		blocks = v
+	}
+
+	for _, block := range blocks {
+		m, _ := block.(map[string]interface{})
+		switch v := m[string(attr)].(type) {
+		case bool:
+			if v {
+				return true
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkTemplateBundleDrifted reports whether the bundle got, read from the
+// API, no longer matches the bundle want stamped from the template.  Only the
+// attributes the API doesn't fill in itself are compared.
+func checkTemplateBundleDrifted(want, got *circonusCheck) bool {
+	switch {
+	case want.DisplayName != got.DisplayName,
+		want.Target != got.Target,
+		want.Period != got.Period,
+		want.Status != got.Status,
+		want.Type != got.Type:
+		return true
+	}
+
+	wantTags := append([]string(nil), want.Tags...)
+	gotTags := append([]string(nil), got.Tags...)
+	for i := range wantTags {
+		wantTags[i] = strings.ToLower(wantTags[i])
+	}
+	for i := range gotTags {
+		gotTags[i] = strings.ToLower(gotTags[i])
+	}
+
+	return !stringSlicesEqual(wantTags, gotTags)
+}
+
+// checkTemplateBundleName is the display name of the bundle of the target key.
+func checkTemplateBundleName(name, key string) string {
+	if name == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s (%s)", name, key)
+}
+
+// checkTemplateTargets returns a targets attribute value by key.
+func checkTemplateTargets(v interface{}) map[string]checkTemplateTarget {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return map[string]checkTemplateTarget{}
+	}
+
+	targets := make(map[string]checkTemplateTarget, set.Len())
+	for _, raw := range set.List() {
+		m := newInterfaceMap(raw)
+		t := checkTemplateTarget{}
+		t.Key, _ = m[checkTemplateTargetKeyAttr].(string)
+		t.Target, _ = m[checkTemplateTargetTargetAttr].(string)
+		if tags, ok := m[checkTemplateTargetTagsAttr].(*schema.Set); ok {
+			t.Tags = derefStringList(flattenSet(tags))
+			sort.Strings(t.Tags)
+		}
+		targets[t.Key] = t
+	}
+
+	return targets
+}
+
+func checkTemplateTargetsToState(targets map[string]checkTemplateTarget) []interface{} {
+	l := make([]interface{}, 0, len(targets))
+	for _, key := range sortedKeys(targets) {
+		t := targets[key]
+		tags := make([]interface{}, 0, len(t.Tags))
+		for _, tag := range t.Tags {
+			tags = append(tags, tag)
+		}
+
+		l = append(l, map[string]interface{}{
+			string(checkTemplateTargetKeyAttr):    t.Key,
+			string(checkTemplateTargetTagsAttr):   schema.NewSet(schema.HashString, tags),
+			string(checkTemplateTargetTargetAttr): t.Target,
+		})
+	}
+
+	return l
+}
+
+// checkTemplateBundleIDs returns a copy of a bundle_ids attribute value.
+func checkTemplateBundleIDs(v interface{}) map[string]string {
+	m, _ := v.(map[string]interface{})
+	bundleIDs := make(map[string]string, len(m))
+	for key, v := range m {
+		if cid, ok := v.(string); ok && cid != "" {
+			bundleIDs[key] = cid
+		}
+	}
+
+	return bundleIDs
+}
+
+// runCheckTemplateBatches calls fn for each key, running up to size calls at
+// a time and waiting for a batch to finish before starting the next.  It
+// returns the errors by key.
+func runCheckTemplateBatches(ctx context.Context, keys []string, size int, fn func(ctx context.Context, key string) error) map[string]error {
+	if size < 1 {
+		size = 1
+	}
+
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		var wg sync.WaitGroup
+		for _, key := range keys[start:end] {
+			key := key
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if err := fn(ctx, key); err != nil {
+					mu.Lock()
+					errs[key] = err
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	return errs
+}
+
+// checkTemplateDiags returns an error diagnostic per failed target, in key
+// order.
+func checkTemplateDiags(keys []string, errs map[string]error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, key := range keys {
+		err, found := errs[key]
+		if !found {
+			continue
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Check template target %q failed", key),
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
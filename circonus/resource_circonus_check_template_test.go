@@ -0,0 +1,380 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func Test_CheckTemplateTargetConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheckTemplate().Schema, map[string]interface{}{
+		"name":      "latency",
+		"collector": []interface{}{map[string]interface{}{"id": "/broker/1"}},
+		"icmp_ping": []interface{}{map[string]interface{}{}},
+		"metric": []interface{}{
+			map[string]interface{}{"name": "average", "type": "numeric"},
+		},
+		"period": "60s",
+		"tags":   []interface{}{"author:terraform"},
+		"targets": []interface{}{
+			map[string]interface{}{"key": "web1", "target": "web1.example.com", "tags": []interface{}{"role:web"}},
+			map[string]interface{}{"key": "db1", "target": "db1.example.com"},
+		},
+	})
+
+	tests := []struct {
+		key    string
+		name   string
+		target string
+		tags   []string
+	}{
+		{"db1", "latency (db1)", "db1.example.com", []string{"author:terraform"}},
+		{"web1", "latency (web1)", "web1.example.com", []string{"author:terraform", "role:web"}},
+	}
+
+	targets := checkTemplateTargets(d.Get(checkTemplateTargetsAttr))
+	if len(targets) != len(tests) {
+		t.Fatalf("expected %d targets, got %d", len(tests), len(targets))
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			c := newCheck()
+			if err := c.ParseConfig(checkTemplateTargetConfig{checkConfigReader: d, target: targets[test.key]}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.DisplayName != test.name {
+				t.Errorf("expected name %q, got %q", test.name, c.DisplayName)
+			}
+
+			if c.Target != test.target {
+				t.Errorf("expected target %q, got %q", test.target, c.Target)
+			}
+
+			if !stringSlicesEqual(c.Tags, test.tags) {
+				t.Errorf("expected tags %v, got %v", test.tags, c.Tags)
+			}
+
+			if c.Period != 60 {
+				t.Errorf("expected period 60, got %d", c.Period)
+			}
+
+			if len(c.Brokers) != 1 || c.Brokers[0] != "/broker/1" {
+				t.Errorf("expected collectors [/broker/1], got %v", c.Brokers)
+			}
+		})
+	}
+}
+
+func Test_CheckTemplateBundleDrifted(t *testing.T) {
+	want := func() *circonusCheck {
+		c := newCheck()
+		c.DisplayName = "latency (web1)"
+		c.Target = "web1.example.com"
+		c.Period = 60
+		c.Status = "active"
+		c.Type = "ping_icmp"
+		c.Tags = []string{"author:terraform", "role:web"}
+		return &c
+	}
+
+	tests := []struct {
+		name    string
+		change  func(c *circonusCheck)
+		drifted bool
+	}{
+		{"unchanged", func(c *circonusCheck) {}, false},
+		{"tags reordered", func(c *circonusCheck) { c.Tags = []string{"role:web", "author:terraform"} }, false},
+		{"tags case", func(c *circonusCheck) { c.Tags = []string{"Author:Terraform", "role:web"} }, false},
+		{"name", func(c *circonusCheck) { c.DisplayName = "renamed" }, true},
+		{"target", func(c *circonusCheck) { c.Target = "web2.example.com" }, true},
+		{"period", func(c *circonusCheck) { c.Period = 300 }, true},
+		{"status", func(c *circonusCheck) { c.Status = "disabled" }, true},
+		{"tag added", func(c *circonusCheck) { c.Tags = append(c.Tags, "env:prod") }, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := want()
+			test.change(got)
+
+			if drifted := checkTemplateBundleDrifted(want(), got); drifted != test.drifted {
+				t.Errorf("expected drifted %t, got %t", test.drifted, drifted)
+			}
+		})
+	}
+}
+
+func Test_RunCheckTemplateBatches(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	calls := make(map[string]int)
+
+	errs := runCheckTemplateBatches(context.Background(), keys, 2, func(ctx context.Context, key string) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		calls[key]++
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		if key == "c" {
+			return errors.New("failed")
+		}
+
+		return nil
+	})
+
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", maxRunning)
+	}
+
+	for _, key := range keys {
+		if calls[key] != 1 {
+			t.Errorf("expected 1 call for %q, got %d", key, calls[key])
+		}
+	}
+
+	if len(errs) != 1 || errs["c"] == nil {
+		t.Errorf("expected an error for \"c\" only, got %v", errs)
+	}
+
+	diags := checkTemplateDiags(keys, errs)
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary, `"c"`) {
+		t.Errorf("expected one diagnostic for \"c\", got %v", diags)
+	}
+}
+
+func Test_CheckTemplateCustomizeDiff(t *testing.T) {
+	targets := []interface{}{
+		map[string]interface{}{"key": "web1", "target": "web1.example.com"},
+	}
+	collector := []interface{}{map[string]interface{}{"id": "/broker/1"}}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		errMsg string
+		path   cty.Path
+	}{
+		{
+			name: "valid",
+			config: map[string]interface{}{
+				"name":      "test",
+				"collector": collector,
+				"icmp_ping": []interface{}{map[string]interface{}{}},
+				"metric": []interface{}{
+					map[string]interface{}{"name": "average", "type": "numeric"},
+				},
+				"targets": targets,
+			},
+		},
+		{
+			name: "no check type",
+			config: map[string]interface{}{
+				"name":      "test",
+				"collector": collector,
+				"targets":   targets,
+			},
+			errMsg: "one check type must be configured",
+			path:   cty.Path{},
+		},
+		{
+			name: "cert_expiry",
+			config: map[string]interface{}{
+				"name":      "test",
+				"collector": collector,
+				"tcp": []interface{}{
+					map[string]interface{}{
+						"host":        "127.0.0.1",
+						"port":        443,
+						"cert_expiry": []interface{}{map[string]interface{}{"days": 14}},
+					},
+				},
+				"targets": targets,
+			},
+			errMsg: "cert_expiry is not supported by circonus_check_template",
+			path:   attrPath(checkTCPAttr),
+		},
+		{
+			name: "generate_secret",
+			config: map[string]interface{}{
+				"name":      "test",
+				"collector": collector,
+				"httptrap": []interface{}{
+					map[string]interface{}{"generate_secret": true},
+				},
+				"targets": targets,
+			},
+			errMsg: "generate_secret is not supported by circonus_check_template",
+			path:   attrPath(checkHTTPTrapAttr),
+		},
+	}
+
+	r := resourceCheckTemplate()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(test.config), &providerContext{})
+			if test.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error containing %q", test.errMsg)
+			}
+
+			if !strings.Contains(err.Error(), test.errMsg) {
+				t.Errorf("expected error containing %q, got %q", test.errMsg, err)
+			}
+
+			pathErr, ok := err.(cty.PathError)
+			if !ok {
+				t.Fatalf("expected cty.PathError, got %T", err)
+			}
+
+			if !pathErr.Path.Equals(test.path) {
+				t.Errorf("expected path %#v, got %#v", test.path, pathErr.Path)
+			}
+		})
+	}
+}
+
+func TestAccCirconusCheckTemplate_basic(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping template - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckTemplate,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckTemplateConfigFmt, checkName, testAccBroker1, `
+  targets {
+    key = "circonus"
+    target = "api.circonus.com"
+  }
+
+  targets {
+    key = "www"
+    target = "www.circonus.com"
+    tags = [ "role:web" ]
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "targets.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "bundle_ids.%", "2"),
+					resource.TestMatchResourceAttr("circonus_check_template.latency", "bundle_ids.circonus", regexp.MustCompile(config.CheckBundleCIDRegex)),
+					resource.TestMatchResourceAttr("circonus_check_template.latency", "bundle_ids.www", regexp.MustCompile(config.CheckBundleCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "uuids.%", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check_template.latency", "targets.*", map[string]string{
+						"key":    "www",
+						"target": "www.circonus.com",
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckTemplateConfigFmt, checkName, testAccBroker1, `
+  targets {
+    key = "www"
+    target = "www.circonus.com"
+    tags = [ "role:web", "env:prod" ]
+  }
+
+  targets {
+    key = "login"
+    target = "login.circonus.com"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "targets.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "bundle_ids.%", "2"),
+					resource.TestCheckNoResourceAttr("circonus_check_template.latency", "bundle_ids.circonus"),
+					resource.TestMatchResourceAttr("circonus_check_template.latency", "bundle_ids.login", regexp.MustCompile(config.CheckBundleCIDRegex)),
+					resource.TestMatchResourceAttr("circonus_check_template.latency", "bundle_ids.www", regexp.MustCompile(config.CheckBundleCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check_template.latency", "uuids.%", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDestroyCirconusCheckTemplate(s *terraform.State) error {
+	c := testAccProvider.Meta().(*providerContext)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "circonus_check_template" {
+			continue
+		}
+
+		for attr, cid := range rs.Primary.Attributes {
+			if !strings.HasPrefix(attr, "bundle_ids.") || attr == "bundle_ids.%" {
+				continue
+			}
+
+			cid := cid
+			exists, err := checkCheckBundleExists(c, api.CIDType(&cid))
+			if err != nil {
+				return fmt.Errorf("Error checking check bundle %s", err)
+			}
+
+			if exists {
+				return fmt.Errorf("check bundle %s still exists after destroy", cid)
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccCirconusCheckTemplateConfigFmt = `
+resource "circonus_check_template" "latency" {
+  active = true
+  name = "%s"
+  period = "300s"
+  batch_size = 1
+
+  collector {
+    id = "%s"
+  }
+
+  icmp_ping {
+    availability = "100.0"
+    count = 5
+    interval = "500ms"
+  }
+
+  metric {
+    name = "average"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+%s
+}
+`
@@ -2,15 +2,16 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"strings"
+	"net/http"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -195,14 +196,14 @@ var contactVictorOpsDescriptions = attrDescrs{
 
 func resourceContactGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: contactGroupCreate,
-		Read:   contactGroupRead,
-		Update: contactGroupUpdate,
-		Delete: contactGroupDelete,
-		Exists: contactGroupExists,
+		CreateContext: contactGroupCreate,
+		ReadContext:   contactGroupRead,
+		UpdateContext: contactGroupUpdate,
+		DeleteContext: contactGroupDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: convertToHelperSchema(contactGroupDescriptions, map[schemaAttr]*schema.Schema{
 			contactAggregationWindowAttr: {
@@ -483,99 +484,97 @@ func resourceContactGroup() *schema.Resource {
 	}
 }
 
-func contactGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func contactGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeContactGroup, "")
 
 	in, err := getContactGroupInput(d)
 	if err != nil {
-		return err
+		return configDiag(err)
 	}
 
-	cg, err := ctxt.client.CreateContactGroup(in)
-	if err != nil {
+	var cg *api.ContactGroup
+	err = ctxt.apiRequest(ctx, http.MethodPost, config.ContactGroupPrefix, func(client *api.API) (err error) {
+		cg, err = client.CreateContactGroup(in)
 		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to create contact group: %w", err), "", schema.TimeoutCreate)
 	}
 
 	d.SetId(cg.CID)
 
-	return contactGroupRead(d, meta)
+	return contactGroupRead(ctx, d, meta)
 }
 
-func contactGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+func contactGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*providerContext)
+	var diags diag.Diagnostics
 
 	cid := d.Id()
-	cg, err := c.client.FetchContactGroup(api.CIDType(&cid))
+	ctx = c.logContext(ctx, resourceTypeContactGroup, cid)
+
+	var cg *api.ContactGroup
+	err := c.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		cg, err = client.FetchContactGroup(api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
-			return false, nil
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Contact group", cid)
 		}
 
-		return false, err
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 
 	if cg.CID == "" {
-		return false, nil
-	}
-
-	return true, nil
-}
-
-func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
-	c := meta.(*providerContext)
-
-	cid := d.Id()
-
-	cg, err := c.client.FetchContactGroup(api.CIDType(&cid))
-	if err != nil {
-		return err
-	}
-
-	if cg.CID == "" {
-		return nil
+		return notFoundDiag(d, "Contact group", cid)
 	}
 
 	d.SetId(cg.CID)
 
 	httpState, err := contactGroupHTTPToState(cg)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pagerDutyState, err := contactGroupPagerDutyToState(cg)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	slackState, err := contactGroupSlackToState(cg)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	smsState, err := contactGroupSMSToState(cg)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	victorOpsState, err := contactGroupVictorOpsToState(cg)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
+	alertOptionsState, alertOptionsDiags := contactGroupAlertOptionsToState(cg)
+	diags = append(diags, alertOptionsDiags...)
+
 	_ = d.Set(contactAggregationWindowAttr, fmt.Sprintf("%ds", cg.AggregationWindow))
 	_ = d.Set(contactAlwaysSendClearAttr, cg.AlwaysSendClear)
 	_ = d.Set(contactGroupTypeAttr, cg.GroupType)
 
-	if err := d.Set(contactAlertOptionAttr, contactGroupAlertOptionsToState(cg)); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactAlertOptionAttr, err)
+	if err := d.Set(contactAlertOptionAttr, alertOptionsState); err != nil {
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactAlertOptionAttr, err)...)
 	}
 
 	if err := d.Set(contactEmailAttr, contactGroupEmailToState(cg)); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactEmailAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactEmailAttr, err)...)
 	}
 
 	if err := d.Set(contactHTTPAttr, httpState); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactHTTPAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactHTTPAttr, err)...)
 	}
 
 	_ = d.Set(contactLongMessageAttr, cg.AlertFormats.LongMessage)
@@ -584,58 +583,68 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set(contactNameAttr, cg.Name)
 
 	if err := d.Set(contactPagerDutyAttr, pagerDutyState); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactPagerDutyAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactPagerDutyAttr, err)...)
 	}
 
 	_ = d.Set(contactShortMessageAttr, cg.AlertFormats.ShortMessage)
 	_ = d.Set(contactShortSummaryAttr, cg.AlertFormats.ShortSummary)
 
 	if err := d.Set(contactSlackAttr, slackState); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSlackAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactSlackAttr, err)...)
 	}
 
 	if err := d.Set(contactSMSAttr, smsState); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSMSAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactSMSAttr, err)...)
 	}
 
 	if err := d.Set(contactTagsAttr, cg.Tags); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactTagsAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactTagsAttr, err)...)
 	}
 
 	if err := d.Set(contactVictorOpsAttr, victorOpsState); err != nil {
-		return fmt.Errorf("Unable to store contact %q attribute: %w", contactVictorOpsAttr, err)
+		return append(diags, diag.Errorf("Unable to store contact %q attribute: %v", contactVictorOpsAttr, err)...)
 	}
 
 	// Out parameters
 	_ = d.Set(contactLastModifiedAttr, cg.LastModified)
 	_ = d.Set(contactLastModifiedByAttr, cg.LastModifiedBy)
 
-	return nil
+	return diags
 }
 
-func contactGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func contactGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*providerContext)
+	ctx = c.logContext(ctx, resourceTypeContactGroup, d.Id())
 
 	in, err := getContactGroupInput(d)
 	if err != nil {
-		return err
+		return configDiag(err)
 	}
 
 	in.CID = d.Id()
 
-	if _, err := c.client.UpdateContactGroup(in); err != nil {
-		return fmt.Errorf("unable to update contact group %q: %w", d.Id(), err)
+	err = c.apiRequest(ctx, http.MethodPut, in.CID, func(client *api.API) error {
+		_, err := client.UpdateContactGroup(in)
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update contact group %q: %w", d.Id(), err), d.Id(), schema.TimeoutUpdate)
 	}
 
-	return contactGroupRead(d, meta)
+	return contactGroupRead(ctx, d, meta)
 }
 
-func contactGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func contactGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*providerContext)
 
 	cid := d.Id()
-	if _, err := c.client.DeleteContactGroupByCID(api.CIDType(&cid)); err != nil {
-		return fmt.Errorf("unable to delete contact group %q: %w", d.Id(), err)
+	ctx = c.logContext(ctx, resourceTypeContactGroup, cid)
+	err := c.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteContactGroupByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to delete contact group %q: %w", d.Id(), err), cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -643,14 +652,20 @@ func contactGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func contactGroupAlertOptionsToState(cg *api.ContactGroup) []interface{} {
-	if config.NumSeverityLevels != len(cg.Reminders) {
-		log.Printf("[FATAL] PROVIDER BUG: Need to update constants in contactGroupAlertOptionsToState re: reminders")
-		return nil
-	}
-	if config.NumSeverityLevels != len(cg.Escalations) {
-		log.Printf("[FATAL] PROVIDER BUG: Need to update constants in contactGroupAlertOptionsToState re: escalations")
-		return nil
+// contactGroupAlertOptionsToState flattens the reminders and escalations of a
+// contact group into alert_option blocks.  If the API returns a different
+// number of severity levels than the provider knows about the alert options
+// are left out of the state and a warning is returned.
+func contactGroupAlertOptionsToState(cg *api.ContactGroup) ([]interface{}, diag.Diagnostics) {
+	if config.NumSeverityLevels != len(cg.Reminders) || config.NumSeverityLevels != len(cg.Escalations) {
+		return nil, diag.Diagnostics{
+			{
+				Severity:      diag.Warning,
+				Summary:       "Unable to read contact group alert options",
+				Detail:        fmt.Sprintf("PROVIDER BUG: contact group %q has %d reminders and %d escalations but the provider supports %d severity levels.", cg.CID, len(cg.Reminders), len(cg.Escalations), config.NumSeverityLevels),
+				AttributePath: attrPath(contactAlertOptionAttr),
+			},
+		}
 	}
 
 	// Populate all alert options for every severity level.  We'll prune empty
@@ -687,7 +702,7 @@ func contactGroupAlertOptionsToState(cg *api.ContactGroup) []interface{} {
 		}
 	}
 
-	return alertOptionsList
+	return alertOptionsList, nil
 }
 
 func contactGroupEmailToState(cg *api.ContactGroup) []interface{} {
@@ -822,7 +837,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 			// Can't mark two attributes that are conflicting as required so we do our
 			// own validation check here.
 			if !requiredAttrFound {
-				return nil, fmt.Errorf("In type %s, either %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr)
+				return nil, attrErrorf(attrPath(contactEmailAttr), "In type %s, either %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr)
 			}
 		}
 	}
@@ -869,7 +884,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				cid := v.(string)
 				contactGroupID, err := failoverGroupCIDToID(api.CIDType(&cid))
 				if err != nil {
-					return nil, fmt.Errorf("error reading contact group CID: %w", err)
+					return nil, attrErrorf(attrPath(contactPagerDutyAttr), "error reading contact group CID: %w", err)
 				}
 				pagerDutyInfo.FallbackGroupCID = contactGroupID
 			}
@@ -922,7 +937,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				cid := v.(string)
 				contactGroupID, err := failoverGroupCIDToID(api.CIDType(&cid))
 				if err != nil {
-					return nil, fmt.Errorf("error reading contact group CID: %w", err)
+					return nil, attrErrorf(attrPath(contactSlackAttr), "error reading contact group CID: %w", err)
 				}
 				slackInfo.FallbackGroupCID = contactGroupID
 			}
@@ -972,7 +987,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 			// Can't mark two attributes that are conflicting as required so we do our
 			// own validation check here.
 			if !requiredAttrFound {
-				return nil, fmt.Errorf("In type %s, either %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr)
+				return nil, attrErrorf(attrPath(contactSMSAttr), "In type %s, either %s or %s must be specified", contactSMSAttr, contactSMSAddressAttr, contactUserCIDAttr)
 			}
 		}
 	}
@@ -988,7 +1003,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				cid := v.(string)
 				contactGroupID, err := failoverGroupCIDToID(api.CIDType(&cid))
 				if err != nil {
-					return nil, fmt.Errorf("error reading contact group CID: %w", err)
+					return nil, attrErrorf(attrPath(contactVictorOpsAttr), "error reading contact group CID: %w", err)
 				}
 				victorOpsInfo.FallbackGroupCID = contactGroupID
 			}
@@ -2,25 +2,29 @@ package circonus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceDashboard() *schema.Resource {
 	return &schema.Resource{
-		Create: dashboardCreate,
-		Read:   dashboardRead,
-		Update: dashboardUpdate,
-		Delete: dashboardDelete,
-		Exists: dashboardExists,
+		CreateContext: dashboardCreate,
+		ReadContext:   dashboardRead,
+		UpdateContext: dashboardUpdate,
+		DeleteContext: dashboardDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"title": {
 				Type:        schema.TypeString,
@@ -666,51 +670,42 @@ func hashWidgets(vv interface{}) int {
 	return hashcode.String(s)
 }
 
-func dashboardCreate(d *schema.ResourceData, meta interface{}) error {
+func dashboardCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeDashboard, "")
+
 	dash := newDashboard()
 	if err := dash.ParseConfig(d); err != nil {
-		return fmt.Errorf("error parsing graph schema during create: %w", err)
+		return configDiag(fmt.Errorf("error parsing dashboard schema during create: %w", err))
 	}
 
-	if err := dash.Create(ctxt); err != nil {
-		return fmt.Errorf("error creating dashboard: %w", err)
+	if err := dash.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("error creating dashboard: %w", err), "", schema.TimeoutCreate)
 	}
 
 	d.SetId(dash.CID)
 
-	return dashboardRead(d, meta)
+	return dashboardRead(ctx, d, meta)
 }
 
-func dashboardExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+// dashboardRead pulls data out of the Dashboard object and stores it into the
+// appropriate place in the statefile.
+func dashboardRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	dash, err := ctxt.client.FetchDashboard(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, resourceTypeDashboard, cid)
+	dash, err := loadDashboard(ctx, ctxt, api.CIDType(&cid))
 	if err != nil {
-		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
-			return false, nil
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Dashboard", cid)
 		}
 
-		return false, err
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 
 	if dash.CID == "" {
-		return false, nil
-	}
-
-	return true, nil
-}
-
-// dashboardRead pulls data out of the Dashboard object and stores it into the
-// appropriate place in the statefile.
-func dashboardRead(d *schema.ResourceData, meta interface{}) error {
-	ctxt := meta.(*providerContext)
-
-	cid := d.Id()
-	dash, err := loadDashboard(ctxt, api.CIDType(&cid))
-	if err != nil {
-		return err
+		return notFoundDiag(d, "Dashboard", cid)
 	}
 
 	d.SetId(dash.CID)
@@ -870,27 +865,34 @@ func dashboardRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func dashboardUpdate(d *schema.ResourceData, meta interface{}) error {
+func dashboardUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeDashboard, d.Id())
+
 	dash := newDashboard()
 	if err := dash.ParseConfig(d); err != nil {
-		return err
+		return configDiag(err)
 	}
 
 	dash.CID = d.Id()
-	if err := dash.Update(ctxt); err != nil {
-		return fmt.Errorf("unable to update dashboard %q: %w", d.Id(), err)
+	if err := dash.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update dashboard %q: %w", d.Id(), err), d.Id(), schema.TimeoutUpdate)
 	}
 
-	return dashboardRead(d, meta)
+	return dashboardRead(ctx, d, meta)
 }
 
-func dashboardDelete(d *schema.ResourceData, meta interface{}) error {
+func dashboardDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteDashboardByCID(api.CIDType(&cid)); err != nil {
-		return fmt.Errorf("unable to delete dashboard %q: %w", d.Id(), err)
+	ctx = ctxt.logContext(ctx, resourceTypeDashboard, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteDashboardByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to delete dashboard %q: %w", d.Id(), err), cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -911,9 +913,13 @@ func newDashboard() circonusDashboard {
 	return dash
 }
 
-func loadDashboard(ctxt *providerContext, cid api.CIDType) (circonusDashboard, error) {
+func loadDashboard(ctx context.Context, ctxt *providerContext, cid api.CIDType) (circonusDashboard, error) {
 	var dash circonusDashboard
-	ng, err := ctxt.client.FetchDashboard(cid)
+	var ng *api.Dashboard
+	err := ctxt.apiRequest(ctx, http.MethodGet, *cid, func(client *api.API) (err error) {
+		ng, err = client.FetchDashboard(cid)
+		return err
+	})
 	if err != nil {
 		return circonusDashboard{}, err
 	}
@@ -1343,9 +1349,12 @@ func (dash *circonusDashboard) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (dash *circonusDashboard) Create(ctxt *providerContext) error {
-	ctxt.client.Debug = true
-	ng, err := ctxt.client.CreateDashboard(&dash.Dashboard)
+func (dash *circonusDashboard) Create(ctx context.Context, ctxt *providerContext) error {
+	var ng *api.Dashboard
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.DashboardPrefix, func(client *api.API) (err error) {
+		ng, err = client.CreateDashboard(&dash.Dashboard)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -1356,8 +1365,11 @@ func (dash *circonusDashboard) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (dash *circonusDashboard) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateDashboard(&dash.Dashboard)
+func (dash *circonusDashboard) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, dash.CID, func(client *api.API) error {
+		_, err := client.UpdateDashboard(&dash.Dashboard)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update dashboard %s: %w", dash.CID, err)
 	}
@@ -1,14 +1,17 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -139,14 +142,14 @@ func resourceGraph() *schema.Resource {
 	// }
 
 	return &schema.Resource{
-		Create: graphCreate,
-		Read:   graphRead,
-		Update: graphUpdate,
-		Delete: graphDelete,
-		Exists: graphExists,
+		CreateContext: graphCreate,
+		ReadContext:   graphRead,
+		UpdateContext: graphUpdate,
+		DeleteContext: graphDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: convertToHelperSchema(graphDescriptions, map[schemaAttr]*schema.Schema{
 			graphDescriptionAttr: {
@@ -354,51 +357,42 @@ func resourceGraph() *schema.Resource {
 	}
 }
 
-func graphCreate(d *schema.ResourceData, meta interface{}) error {
+func graphCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeGraph, "")
+
 	g := newGraph()
-	if err := g.ParseConfig(d); err != nil {
-		return fmt.Errorf("error parsing graph schema during create: %w", err)
+	if err := g.ParseConfig(ctx, d); err != nil {
+		return configDiag(fmt.Errorf("error parsing graph schema during create: %w", err))
 	}
 
-	if err := g.Create(ctxt); err != nil {
-		return fmt.Errorf("error creating graph: %w", err)
+	if err := g.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("error creating graph: %w", err), "", schema.TimeoutCreate)
 	}
 
 	d.SetId(g.CID)
 
-	return graphRead(d, meta)
+	return graphRead(ctx, d, meta)
 }
 
-func graphExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+// graphRead pulls data out of the Graph object and stores it into the
+// appropriate place in the statefile.
+func graphRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	g, err := ctxt.client.FetchGraph(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, resourceTypeGraph, cid)
+	g, err := loadGraph(ctx, ctxt, api.CIDType(&cid))
 	if err != nil {
-		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
-			return false, nil
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Graph", cid)
 		}
 
-		return false, err
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 
 	if g.CID == "" {
-		return false, nil
-	}
-
-	return true, nil
-}
-
-// graphRead pulls data out of the Graph object and stores it into the
-// appropriate place in the statefile.
-func graphRead(d *schema.ResourceData, meta interface{}) error {
-	ctxt := meta.(*providerContext)
-
-	cid := d.Id()
-	g, err := loadGraph(ctxt, api.CIDType(&cid))
-	if err != nil {
-		return err
+		return notFoundDiag(d, "Graph", cid)
 	}
 
 	d.SetId(g.CID)
@@ -421,7 +415,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		case "r":
 			dataPointAttrs[string(graphMetricAxisAttr)] = "right"
 		default:
-			return fmt.Errorf("PROVIDER BUG: Unsupported axis type %q", datapoint.Axis)
+			return diag.Errorf("PROVIDER BUG: Unsupported axis type %q", datapoint.Axis)
 		}
 
 		if datapoint.CAQL != nil && *datapoint.CAQL != "" {
@@ -449,7 +443,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		case string:
 			dataPointAttrs[string(graphMetricFunctionAttr)] = u
 		default:
-			return fmt.Errorf("PROVIDER BUG: Unsupported type for derive: %T", datapoint.Derive)
+			return diag.Errorf("PROVIDER BUG: Unsupported type for derive: %T", datapoint.Derive)
 		}
 
 		if datapoint.LegendFormula != nil {
@@ -491,7 +485,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		case "r":
 			metricClusterAttrs[string(graphMetricClusterAxisAttr)] = "right"
 		default:
-			return fmt.Errorf("PROVIDER BUG: Unsupported axis type %q", metricCluster.Axis)
+			return diag.Errorf("PROVIDER BUG: Unsupported axis type %q", metricCluster.Axis)
 		}
 
 		if metricCluster.Color != nil {
@@ -552,7 +546,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if err := d.Set(graphLeftAttr, leftAxisMap); err != nil {
-		return fmt.Errorf("Unable to store graph %q attribute: %w", graphLeftAttr, err)
+		return diag.Errorf("Unable to store graph %q attribute: %v", graphLeftAttr, err)
 	}
 
 	if g.Title != "" {
@@ -568,15 +562,15 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if err := d.Set(graphRightAttr, rightAxisMap); err != nil {
-		return fmt.Errorf("Unable to store graph %q attribute: %w", graphRightAttr, err)
+		return diag.Errorf("Unable to store graph %q attribute: %v", graphRightAttr, err)
 	}
 
 	if err := d.Set(graphMetricAttr, metrics); err != nil {
-		return fmt.Errorf("Unable to store graph %q attribute: %w", graphMetricAttr, err)
+		return diag.Errorf("Unable to store graph %q attribute: %v", graphMetricAttr, err)
 	}
 
 	if err := d.Set(graphMetricClusterAttr, metricClusters); err != nil {
-		return fmt.Errorf("Unable to store graph %q attribute: %w", graphMetricClusterAttr, err)
+		return diag.Errorf("Unable to store graph %q attribute: %v", graphMetricClusterAttr, err)
 	}
 
 	if g.Style != nil && *g.Style != "" {
@@ -585,7 +579,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 
 	if len(g.Tags) > 0 {
 		if err := d.Set(graphTagsAttr, tagsToState(apiToTags(g.Tags))); err != nil {
-			return fmt.Errorf("Unable to store graph %q attribute: %w", graphTagsAttr, err)
+			return diag.Errorf("Unable to store graph %q attribute: %v", graphTagsAttr, err)
 		}
 	}
 
@@ -616,27 +610,34 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func graphUpdate(d *schema.ResourceData, meta interface{}) error {
+func graphUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeGraph, d.Id())
+
 	g := newGraph()
-	if err := g.ParseConfig(d); err != nil {
-		return err
+	if err := g.ParseConfig(ctx, d); err != nil {
+		return configDiag(err)
 	}
 
 	g.CID = d.Id()
-	if err := g.Update(ctxt); err != nil {
-		return fmt.Errorf("unable to update graph %q: %w", d.Id(), err)
+	if err := g.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update graph %q: %w", d.Id(), err), d.Id(), schema.TimeoutUpdate)
 	}
 
-	return graphRead(d, meta)
+	return graphRead(ctx, d, meta)
 }
 
-func graphDelete(d *schema.ResourceData, meta interface{}) error {
+func graphDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteGraphByCID(api.CIDType(&cid)); err != nil {
-		return fmt.Errorf("unable to delete graph %q: %w", d.Id(), err)
+	ctx = ctxt.logContext(ctx, resourceTypeGraph, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteGraphByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to delete graph %q: %w", d.Id(), err), cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -656,21 +657,24 @@ func newGraph() circonusGraph {
 	return g
 }
 
-func loadGraph(ctxt *providerContext, cid api.CIDType) (circonusGraph, error) {
+func loadGraph(ctx context.Context, ctxt *providerContext, cid api.CIDType) (circonusGraph, error) {
 	var g circonusGraph
-	ng, err := ctxt.client.FetchGraph(cid)
+	var ng *api.Graph
+	err := ctxt.apiRequest(ctx, http.MethodGet, *cid, func(client *api.API) (err error) {
+		ng, err = client.FetchGraph(cid)
+		return err
+	})
 	if err != nil {
 		return circonusGraph{}, err
 	}
 	g.Graph = *ng
-	log.Printf("[loadGraph] %#v\n", *ng)
 
 	return g, nil
 }
 
 // ParseConfig reads Terraform config data and stores the information into a
 // Circonus Graph object.  ParseConfig and graphRead() must be kept in sync.
-func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
+func (g *circonusGraph) ParseConfig(ctx context.Context, d *schema.ResourceData) error {
 	g.Datapoints = make([]api.GraphDatapoint, 0, defaultGraphDatapoints)
 	g.Tags = make([]string, 0)
 	g.AccessKeys = make([]api.GraphAccessKey, 0)
@@ -910,7 +914,7 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 				}
 			}
 
-			metricLocatorError := fmt.Errorf("metric[%d] name=%q: locator issue - %q(%v) + %q(%v) OR %q(%v) OR %q(%v)",
+			metricLocatorError := attrErrorf(attrPath(graphMetricAttr, metricIdx), "metric[%d] name=%q: locator issue - %q(%v) + %q(%v) OR %q(%v) OR %q(%v)",
 				metricIdx, datapoint.Name,
 				graphMetricCheckAttr, check,
 				graphMetricNameAttr, name,
@@ -921,9 +925,9 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 
 			switch {
 			case check == 0 && name != "":
-				return fmt.Errorf("metric[%d] name=%q: locator using %q requires %q", metricIdx, datapoint.Name, graphMetricNameAttr, graphMetricCheckAttr)
+				return attrErrorf(attrPath(graphMetricAttr, metricIdx, graphMetricCheckAttr), "metric[%d] name=%q: locator using %q requires %q", metricIdx, datapoint.Name, graphMetricNameAttr, graphMetricCheckAttr)
 			case check > 0 && name == "":
-				return fmt.Errorf("metric[%d] name=%q: locator using %q requires %q", metricIdx, datapoint.Name, graphMetricCheckAttr, graphMetricNameAttr)
+				return attrErrorf(attrPath(graphMetricAttr, metricIdx, graphMetricNameAttr), "metric[%d] name=%q: locator using %q requires %q", metricIdx, datapoint.Name, graphMetricCheckAttr, graphMetricNameAttr)
 			case check > 0 && (caql != "" || search != ""):
 				return metricLocatorError
 			case caql != "" && (check != 0 || name != "" || search != ""):
@@ -1105,7 +1109,7 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
-	log.Printf("[ParseConfig] %#v\n", g.Graph)
+	tflog.Trace(ctx, "Parsed graph config", map[string]interface{}{"datapoints": len(g.Datapoints), "metric_clusters": len(g.MetricClusters), "guides": len(g.Guides)})
 
 	if err := g.Validate(); err != nil {
 		return err
@@ -1114,8 +1118,12 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (g *circonusGraph) Create(ctxt *providerContext) error {
-	ng, err := ctxt.client.CreateGraph(&g.Graph)
+func (g *circonusGraph) Create(ctx context.Context, ctxt *providerContext) error {
+	var ng *api.Graph
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.GraphPrefix, func(client *api.API) (err error) {
+		ng, err = client.CreateGraph(&g.Graph)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -1125,8 +1133,11 @@ func (g *circonusGraph) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (g *circonusGraph) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateGraph(&g.Graph)
+func (g *circonusGraph) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, g.CID, func(client *api.API) error {
+		_, err := client.UpdateGraph(&g.Graph)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update graph %s: %w", g.CID, err)
 	}
@@ -1141,11 +1152,11 @@ func (g *circonusGraph) Validate() error {
 		// }
 
 		if datapoint.CheckID != 0 && datapoint.MetricName == "" {
-			return fmt.Errorf("Error with %s[%d] name=%q: %s is set, missing attribute %s must also be set", graphMetricAttr, i, datapoint.Name, graphMetricCheckAttr, graphMetricNameAttr)
+			return attrErrorf(attrPath(graphMetricAttr, i, graphMetricNameAttr), "Error with %s[%d] name=%q: %s is set, missing attribute %s must also be set", graphMetricAttr, i, datapoint.Name, graphMetricCheckAttr, graphMetricNameAttr)
 		}
 
 		if datapoint.CheckID == 0 && datapoint.MetricName != "" {
-			return fmt.Errorf("Error with %s[%d] name=%q: %s is set, missing attribute %s must also be set", graphMetricAttr, i, datapoint.Name, graphMetricNameAttr, graphMetricCheckAttr)
+			return attrErrorf(attrPath(graphMetricAttr, i, graphMetricCheckAttr), "Error with %s[%d] name=%q: %s is set, missing attribute %s must also be set", graphMetricAttr, i, datapoint.Name, graphMetricNameAttr, graphMetricCheckAttr)
 		}
 
 		// if datapoint.CAQL != nil && (datapoint.CheckID != 0 || datapoint.MetricName != "") {
@@ -1161,14 +1172,14 @@ func (g *circonusGraph) Validate() error {
 			switch v.(type) {
 			case bool:
 			default:
-				return fmt.Errorf("Error with %s[%d] (name=%q): attribute %q is mutually exclusive when %s=%q", graphMetricAttr, i, datapoint.Name, graphMetricFunctionAttr, graphMetricMetricTypeAttr, "text")
+				return attrErrorf(attrPath(graphMetricAttr, i, graphMetricFunctionAttr), "Error with %s[%d] (name=%q): attribute %q is mutually exclusive when %s=%q", graphMetricAttr, i, datapoint.Name, graphMetricFunctionAttr, graphMetricMetricTypeAttr, "text")
 			}
 		}
 	}
 
 	for i, mc := range g.MetricClusters {
 		if mc.AggregateFunc != "" && (mc.Color == nil || *mc.Color == "") {
-			return fmt.Errorf("Error with %s[%d] name=%q: %s is a required attribute for graphs with %s set", graphMetricClusterAttr, i, mc.Name, graphMetricClusterColorAttr, graphMetricClusterAggregateAttr)
+			return attrErrorf(attrPath(graphMetricClusterAttr, i, graphMetricClusterColorAttr), "Error with %s[%d] name=%q: %s is a required attribute for graphs with %s set", graphMetricClusterAttr, i, mc.Name, graphMetricClusterColorAttr, graphMetricClusterAggregateAttr)
 		}
 	}
 
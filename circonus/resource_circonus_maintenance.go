@@ -1,24 +1,28 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceMaintenance() *schema.Resource {
 	return &schema.Resource{
-		Create: maintenanceCreate,
-		Read:   maintenanceRead,
-		Update: maintenanceUpdate,
-		Delete: maintenanceDelete,
-		Exists: maintenanceExists,
+		CreateContext: maintenanceCreate,
+		ReadContext:   maintenanceRead,
+		UpdateContext: maintenanceUpdate,
+		DeleteContext: maintenanceDelete,
 		Importer: &schema.ResourceImporter{
-			State: importStatePassthroughUnescape,
+			StateContext: importStatePassthroughUnescape,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account": {
@@ -75,46 +79,40 @@ func resourceMaintenance() *schema.Resource {
 	}
 }
 
-func maintenanceCreate(d *schema.ResourceData, meta interface{}) error {
+func maintenanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
-	m := newMaintenance()
+	ctx = ctxt.logContext(ctx, resourceTypeMaintenance, "")
 
+	m := newMaintenance()
 	if err := m.ParseConfig(d); err != nil {
-		return fmt.Errorf("error parsing maintenance schema during create: %w", err)
+		return configDiag(fmt.Errorf("error parsing maintenance schema during create: %w", err))
 	}
 
-	if err := m.Create(ctxt); err != nil {
-		return fmt.Errorf("error creating maintenance: %w", err)
+	if err := m.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("error creating maintenance: %w", err), "", schema.TimeoutCreate)
 	}
 
 	d.SetId(m.CID)
 
-	return maintenanceRead(d, meta)
+	return maintenanceRead(ctx, d, meta)
 }
 
-func maintenanceExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+func maintenanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	m, err := ctxt.client.FetchMaintenanceWindow(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, resourceTypeMaintenance, cid)
+	m, err := loadMaintenance(ctx, ctxt, api.CIDType(&cid))
 	if err != nil {
-		return false, err
-	}
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Maintenance window", cid)
+		}
 
-	if m.CID == "" {
-		return false, nil
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 
-	return true, nil
-}
-
-func maintenanceRead(d *schema.ResourceData, meta interface{}) error {
-	ctxt := meta.(*providerContext)
-
-	cid := d.Id()
-	m, err := loadMaintenance(ctxt, api.CIDType(&cid))
-	if err != nil {
-		return err
+	if m.CID == "" {
+		return notFoundDiag(d, "Maintenance window", cid)
 	}
 
 	d.SetId(m.CID)
@@ -157,29 +155,34 @@ func maintenanceRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func maintenanceUpdate(d *schema.ResourceData, meta interface{}) error {
+func maintenanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
-	m := newMaintenance()
+	ctx = ctxt.logContext(ctx, resourceTypeMaintenance, d.Id())
 
+	m := newMaintenance()
 	if err := m.ParseConfig(d); err != nil {
-		return err
+		return configDiag(err)
 	}
 
 	m.CID = d.Id()
-
-	if err := m.Update(ctxt); err != nil {
-		return fmt.Errorf("unable to update maintenance %q: %w", d.Id(), err)
+	if err := m.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update maintenance %q: %w", d.Id(), err), d.Id(), schema.TimeoutUpdate)
 	}
 
-	return maintenanceRead(d, meta)
+	return maintenanceRead(ctx, d, meta)
 }
 
-func maintenanceDelete(d *schema.ResourceData, meta interface{}) error {
+func maintenanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteMaintenanceWindowByCID(api.CIDType(&cid)); err != nil {
-		return fmt.Errorf("unable to delete rule set %q: %w", d.Id(), err)
+	ctx = ctxt.logContext(ctx, resourceTypeMaintenance, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteMaintenanceWindowByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to delete maintenance %q: %w", d.Id(), err), cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -199,9 +202,13 @@ func newMaintenance() circonusMaintenance {
 	return m
 }
 
-func loadMaintenance(ctxt *providerContext, cid api.CIDType) (circonusMaintenance, error) {
+func loadMaintenance(ctx context.Context, ctxt *providerContext, cid api.CIDType) (circonusMaintenance, error) {
 	var m circonusMaintenance
-	cm, err := ctxt.client.FetchMaintenanceWindow(cid)
+	var cm *api.Maintenance
+	err := ctxt.apiRequest(ctx, http.MethodGet, *cid, func(client *api.API) (err error) {
+		cm, err = client.FetchMaintenanceWindow(cid)
+		return err
+	})
 	if err != nil {
 		return circonusMaintenance{}, err
 	}
@@ -267,8 +274,12 @@ func (m *circonusMaintenance) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (m *circonusMaintenance) Create(ctxt *providerContext) error {
-	cm, err := ctxt.client.CreateMaintenanceWindow(&m.Maintenance)
+func (m *circonusMaintenance) Create(ctx context.Context, ctxt *providerContext) error {
+	var cm *api.Maintenance
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.MaintenancePrefix, func(client *api.API) (err error) {
+		cm, err = client.CreateMaintenanceWindow(&m.Maintenance)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -278,8 +289,11 @@ func (m *circonusMaintenance) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (m *circonusMaintenance) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateMaintenanceWindow(&m.Maintenance)
+func (m *circonusMaintenance) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, m.CID, func(client *api.API) error {
+		_, err := client.UpdateMaintenanceWindow(&m.Maintenance)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update maintenance %s: %w", m.CID, err)
 	}
@@ -288,5 +302,9 @@ func (m *circonusMaintenance) Update(ctxt *providerContext) error {
 }
 
 func (m *circonusMaintenance) Validate() error {
+	if m.Stop <= m.Start {
+		return attrErrorf(attrPath("stop"), "maintenance window stop (%s) must be after start (%s)", time.Unix(int64(m.Stop), 0).Format(time.RFC3339), time.Unix(int64(m.Start), 0).Format(time.RFC3339))
+	}
+
 	return nil
 }
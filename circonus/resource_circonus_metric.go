@@ -7,17 +7,27 @@ package circonus
 // `circonus_metric` resource if no value was set.
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_metric.* resource attribute names.
-	metricActiveAttr = "active"
-	metricIDAttr     = "id"
-	metricNameAttr   = "name"
-	metricTypeAttr   = "type"
+	metricActiveAttr     = "active"
+	metricHistogramAttr  = "histogram"
+	metricIDAttr         = "id"
+	metricNameAttr       = "name"
+	metricStreamTagsAttr = "stream_tags"
+	metricTypeAttr       = "type"
+
+	// circonus_metric.histogram.* resource attribute names.
+	metricHistogramBucketsAttr = "buckets"
+	metricHistogramUnitAttr    = "unit"
+
+	metricTypeHistogram = "histogram"
 
 	// CheckBundle.Metric.Status can be one of these values.
 	metricStatusActive    = "active"
@@ -25,21 +35,28 @@ const (
 )
 
 var metricDescriptions = attrDescrs{
-	metricActiveAttr: "Enables or disables the metric",
-	metricNameAttr:   "Name of the metric",
-	metricTypeAttr:   "Type of metric (e.g. numeric, histogram, text)",
+	metricActiveAttr:     "Enables or disables the metric",
+	metricHistogramAttr:  "Unit and bucket settings of a histogram metric",
+	metricNameAttr:       "Name of the metric, without its stream tags",
+	metricStreamTagsAttr: "Stream tags of the metric as category:value pairs, encoded into its name by the provider",
+	metricTypeAttr:       "Type of metric (e.g. numeric, histogram, text)",
+}
+
+var metricHistogramDescriptions = attrDescrs{
+	metricHistogramBucketsAttr: "Bucket boundaries of the histogram, in ascending order.  Kept in the state only",
+	metricHistogramUnitAttr:    "Unit of the histogram's values",
 }
 
 func resourceMetric() *schema.Resource {
 	return &schema.Resource{
-		Create: metricCreate,
-		Read:   metricRead,
-		Update: metricUpdate,
-		Delete: metricDelete,
-		Exists: metricExists,
+		CreateContext: metricCreate,
+		ReadContext:   metricRead,
+		UpdateContext: metricUpdate,
+		DeleteContext: metricDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: convertToHelperSchema(metricDescriptions, map[schemaAttr]*schema.Schema{
 			metricActiveAttr: {
@@ -47,11 +64,13 @@ func resourceMetric() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			metricHistogramAttr: schemaMetricHistogram(),
 			metricNameAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
 			},
+			metricStreamTagsAttr: schemaMetricStreamTags(),
 			metricTypeAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -61,7 +80,40 @@ func resourceMetric() *schema.Resource {
 	}
 }
 
-func metricCreate(d *schema.ResourceData, meta interface{}) error {
+func schemaMetricHistogram() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(metricHistogramDescriptions, map[schemaAttr]*schema.Schema{
+				metricHistogramBucketsAttr: {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeFloat},
+				},
+				metricHistogramUnitAttr: {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			}),
+		},
+	}
+}
+
+func schemaMetricStreamTags() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Set:      schema.HashString,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validateMetricStreamTag,
+		},
+	}
+}
+
+func metricCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := newMetric()
 
 	id := d.Id()
@@ -69,59 +121,51 @@ func metricCreate(d *schema.ResourceData, meta interface{}) error {
 		var err error
 		id, err = newMetricID()
 		if err != nil {
-			return fmt.Errorf("metric ID creation failed: %w", err)
+			return diag.Errorf("metric ID creation failed: %v", err)
 		}
 	}
 
 	if err := m.ParseConfig(id, d); err != nil {
-		return fmt.Errorf("error parsing metric schema during create: %w", err)
+		return configDiag(fmt.Errorf("error parsing metric schema during create: %w", err))
 	}
 
 	if err := m.Create(d); err != nil {
-		return fmt.Errorf("error creating metric: %w", err)
+		return diag.Errorf("error creating metric: %v", err)
 	}
 
-	return metricRead(d, meta)
+	return metricRead(ctx, d, meta)
 }
 
-func metricRead(d *schema.ResourceData, meta interface{}) error {
+func metricRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := newMetric()
 
 	if err := m.ParseConfig(d.Id(), d); err != nil {
-		return fmt.Errorf("error parsing metric schema during read: %w", err)
+		return configDiag(fmt.Errorf("error parsing metric schema during read: %w", err))
 	}
 
 	if err := m.SaveState(d); err != nil {
-		return fmt.Errorf("error saving metric during read: %w", err)
+		return diag.Errorf("error saving metric during read: %v", err)
 	}
 
 	return nil
 }
 
-func metricUpdate(d *schema.ResourceData, meta interface{}) error {
+func metricUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := newMetric()
 
 	if err := m.ParseConfig(d.Id(), d); err != nil {
-		return fmt.Errorf("error parsing metric schema during update: %w", err)
+		return configDiag(fmt.Errorf("error parsing metric schema during update: %w", err))
 	}
 
 	if err := m.Update(d); err != nil {
-		return fmt.Errorf("error updating metric: %w", err)
+		return diag.Errorf("error updating metric: %v", err)
 	}
 
 	return nil
 }
 
-func metricDelete(d *schema.ResourceData, meta interface{}) error {
+func metricDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	d.SetId("")
 
 	return nil
 }
-
-func metricExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	if id := d.Id(); id != "" {
-		return true, nil
-	}
-
-	return false, nil
-}
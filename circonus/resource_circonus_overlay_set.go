@@ -1,11 +1,13 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
-	"strings"
+	"net/http"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -21,14 +23,14 @@ func randStringBytes(n int) string {
 
 func resourceOverlaySet() *schema.Resource {
 	return &schema.Resource{
-		Create: overlaySetCreate,
-		Read:   overlaySetRead,
-		Update: overlaySetUpdate,
-		Delete: overlaySetDelete,
-		Exists: overlaySetExists,
+		CreateContext: overlaySetCreate,
+		ReadContext:   overlaySetRead,
+		UpdateContext: overlaySetUpdate,
+		DeleteContext: overlaySetDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"graph_cid": {
 				Type:     schema.TypeString,
@@ -103,64 +105,44 @@ func resourceOverlaySet() *schema.Resource {
 	}
 }
 
-func overlaySetCreate(d *schema.ResourceData, meta interface{}) error {
+func overlaySetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
-	o := newOverlaySet()
+	ctx = ctxt.logContext(ctx, resourceTypeOverlaySet, "")
 
+	o := newOverlaySet()
 	if err := o.ParseConfig(d); err != nil {
-		return fmt.Errorf("error parsing graph schema during create: %w", err)
+		return configDiag(fmt.Errorf("error parsing overlay set schema during create: %w", err))
 	}
 
-	if err := o.Create(ctxt); err != nil {
-		return fmt.Errorf("error creating graph: %w", err)
+	if err := o.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("error creating overlay set: %w", err), o.GraphCID, schema.TimeoutCreate)
 	}
 
-	return overlaySetRead(d, meta)
+	d.SetId(o.OverlaySetID)
+
+	return overlaySetRead(ctx, d, meta)
 }
 
-func overlaySetExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+// overlaySetRead pulls the overlay set out of its Graph object and stores it
+// into the appropriate place in the statefile.
+func overlaySetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
+	id := d.Id()
+	ctx = ctxt.logContext(ctx, resourceTypeOverlaySet, id)
 	if graphCID, found := d.GetOk("graph_cid"); found {
-		id := d.Id()
 		s := graphCID.(string)
-		g, err := ctxt.client.FetchGraph(api.CIDType(&s))
+		g, err := loadOverlaySet(ctx, ctxt, api.CIDType(&s), id)
 		if err != nil {
-			if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
-				return false, nil
+			if apiNotFound(err) {
+				return notFoundDiag(d, "Graph", s)
 			}
 
-			return false, err
+			return timeoutDiag(err, s, schema.TimeoutRead)
 		}
 
-		if g.CID == "" {
-			return false, nil
-		}
-
-		if g.OverlaySets == nil {
-			return false, nil
-		}
-
-		if _, ok := (*g.OverlaySets)[id]; ok {
-			return true, nil
-		}
-
-		return false, nil
-	}
-	return false, nil
-}
-
-// graphRead pulls data out of the Graph object and stores it into the
-// appropriate place in the statefile.
-func overlaySetRead(d *schema.ResourceData, meta interface{}) error {
-	ctxt := meta.(*providerContext)
-
-	id := d.Id()
-	if graphCID, found := d.GetOk("graph_cid"); found {
-		s := graphCID.(string)
-		g, err := loadOverlaySet(ctxt, api.CIDType(&s), id)
-		if err != nil {
-			return err
+		if g.OverlaySetID == "" {
+			return notFoundDiag(d, "Overlay set", id)
 		}
 
 		_ = d.Set("graph_cid", graphCID)
@@ -198,43 +180,51 @@ func overlaySetRead(d *schema.ResourceData, meta interface{}) error {
 		_ = d.Set("overlays", dOverlays)
 		return nil
 	}
-	return fmt.Errorf("graph_cid field is required for %q", d.Id())
+
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("graph_cid field is required for %q", d.Id()),
+			AttributePath: attrPath("graph_cid"),
+		},
+	}
 }
 
-func overlaySetUpdate(d *schema.ResourceData, meta interface{}) error {
+func overlaySetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeOverlaySet, d.Id())
+
 	g := newOverlaySet()
 	if err := g.ParseConfig(d); err != nil {
-		return err
+		return configDiag(err)
 	}
 
 	g.OverlaySetID = d.Id()
-
-	if err := g.Update(ctxt); err != nil {
-		return fmt.Errorf("unable to update graph %q: %w", d.Id(), err)
+	if err := g.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update overlay set %q: %w", d.Id(), err), g.GraphCID, schema.TimeoutUpdate)
 	}
 
-	return overlaySetRead(d, meta)
+	return overlaySetRead(ctx, d, meta)
 }
 
-func overlaySetDelete(d *schema.ResourceData, meta interface{}) error {
+func overlaySetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeOverlaySet, d.Id())
 
 	if graphCID, found := d.GetOk("graph_cid"); found {
 		id := d.Id()
 		s := graphCID.(string)
-		var graph *api.Graph
-		var err error
-		if graph, err = ctxt.client.FetchGraph(api.CIDType(&s)); err != nil {
-			return fmt.Errorf("unable to delete overlay set %q: %w", d.Id(), err)
+		graph, err := fetchOverlayGraph(ctx, ctxt, api.CIDType(&s))
+		if err != nil {
+			return timeoutDiag(fmt.Errorf("unable to delete overlay set %q: %w", d.Id(), err), s, schema.TimeoutDelete)
 		}
 
 		if graph.OverlaySets != nil {
 			delete(*graph.OverlaySets, id)
 		}
 
-		if _, err := ctxt.client.UpdateGraph(graph); err != nil {
-			return fmt.Errorf("unable to delete overlay set %q: %w", d.Id(), err)
+		if err := updateOverlayGraph(ctx, ctxt, graph); err != nil {
+			return timeoutDiag(fmt.Errorf("unable to delete overlay set %q: %w", d.Id(), err), s, schema.TimeoutDelete)
 		}
 
 		d.SetId("")
@@ -260,9 +250,28 @@ func newOverlaySet() circonusOverlaySet {
 	return g
 }
 
-func loadOverlaySet(ctxt *providerContext, graphCID api.CIDType, setID string) (circonusOverlaySet, error) {
+// fetchOverlayGraph retrieves the graph an overlay set is stored on.
+func fetchOverlayGraph(ctx context.Context, ctxt *providerContext, graphCID api.CIDType) (*api.Graph, error) {
+	var g *api.Graph
+	err := ctxt.apiRequest(ctx, http.MethodGet, *graphCID, func(client *api.API) (err error) {
+		g, err = client.FetchGraph(graphCID)
+		return err
+	})
+
+	return g, err
+}
+
+// updateOverlayGraph saves a graph whose overlay sets have been modified.
+func updateOverlayGraph(ctx context.Context, ctxt *providerContext, g *api.Graph) error {
+	return ctxt.apiRequest(ctx, http.MethodPut, g.CID, func(client *api.API) error {
+		_, err := client.UpdateGraph(g)
+		return err
+	})
+}
+
+func loadOverlaySet(ctx context.Context, ctxt *providerContext, graphCID api.CIDType, setID string) (circonusOverlaySet, error) {
 	var g circonusOverlaySet
-	ng, err := ctxt.client.FetchGraph(graphCID)
+	ng, err := fetchOverlayGraph(ctx, ctxt, graphCID)
 	if err != nil {
 		return circonusOverlaySet{}, err
 	}
@@ -270,8 +279,13 @@ func loadOverlaySet(ctxt *providerContext, graphCID api.CIDType, setID string) (
 		return circonusOverlaySet{}, nil
 	}
 
+	set, found := (*ng.OverlaySets)[setID]
+	if !found {
+		return circonusOverlaySet{}, nil
+	}
+
 	g.OverlaySetID = setID
-	g.GraphOverlaySet = (*ng.OverlaySets)[setID]
+	g.GraphOverlaySet = set
 	g.GraphCID = *graphCID
 	return g, nil
 }
@@ -337,8 +351,8 @@ func (g *circonusOverlaySet) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (g *circonusOverlaySet) Create(ctxt *providerContext) error {
-	gg, err := ctxt.client.FetchGraph(api.CIDType(&g.GraphCID))
+func (g *circonusOverlaySet) Create(ctx context.Context, ctxt *providerContext) error {
+	gg, err := fetchOverlayGraph(ctx, ctxt, api.CIDType(&g.GraphCID))
 	if err != nil {
 		return err
 	}
@@ -353,7 +367,7 @@ func (g *circonusOverlaySet) Create(ctxt *providerContext) error {
 
 	(*gg.OverlaySets)[g.OverlaySetID] = g.GraphOverlaySet
 
-	_, err = ctxt.client.UpdateGraph(gg)
+	err = updateOverlayGraph(ctx, ctxt, gg)
 	if err != nil {
 		return err
 	}
@@ -361,15 +375,15 @@ func (g *circonusOverlaySet) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (g *circonusOverlaySet) Update(ctxt *providerContext) error {
-	gg, err := ctxt.client.FetchGraph(api.CIDType(&g.GraphCID))
+func (g *circonusOverlaySet) Update(ctx context.Context, ctxt *providerContext) error {
+	gg, err := fetchOverlayGraph(ctx, ctxt, api.CIDType(&g.GraphCID))
 	if err != nil {
 		return err
 	}
 
 	(*gg.OverlaySets)[g.OverlaySetID] = g.GraphOverlaySet
 
-	_, err = ctxt.client.UpdateGraph(gg)
+	err = updateOverlayGraph(ctx, ctxt, gg)
 	if err != nil {
 		return err
 	}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"net/http"
 	"sort"
 	"strconv"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -145,8 +146,9 @@ func resourceRuleSet() *schema.Resource {
 		UpdateContext: ruleSetUpdate,
 		DeleteContext: ruleSetDelete,
 		Importer: &schema.ResourceImporter{
-			State: importStatePassthroughUnescape,
+			StateContext: importStatePassthroughUnescape,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: convertToHelperSchema(ruleSetDescriptions, map[schemaAttr]*schema.Schema{
 			// _cid
 			ruleSetIDAttr: {
@@ -321,8 +323,6 @@ func resourceRuleSet() *schema.Resource {
 				Optional:  true,
 				StateFunc: jsonSort,
 				DiffSuppressFunc: func(k, old, update string, d *schema.ResourceData) bool {
-					log.Printf("Old: '%s', New: '%s'", old, update)
-
 					var ifce interface{}
 					ob := []byte(old)
 					err := json.Unmarshal(ob, &ifce)
@@ -344,8 +344,6 @@ func resourceRuleSet() *schema.Resource {
 						return false
 					}
 
-					log.Printf("Old reformatted: '%s', New reformatted: '%s'", os, ns)
-
 					return string(os) == string(ns)
 				},
 
@@ -396,14 +394,15 @@ func resourceRuleSet() *schema.Resource {
 
 func ruleSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSet, "")
 	rs := newRuleSet()
 
 	if err := rs.ParseConfig(d); err != nil {
-		return diag.FromErr(err)
+		return configDiag(err)
 	}
 
-	if err := rs.Create(ctxt); err != nil {
-		return diag.FromErr(err)
+	if err := rs.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(err, "", schema.TimeoutCreate)
 	}
 
 	d.SetId(rs.CID)
@@ -430,14 +429,23 @@ func ruleSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}
 // ruleSetRead pulls data out of the RuleSet object and stores it into the
 // appropriate place in the statefile.
 func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*providerContext).client
+	ctxt := meta.(*providerContext)
 	var diags diag.Diagnostics
 
 	cid := d.Id()
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSet, cid)
 	var rs circonusRuleSet
-	crs, err := client.FetchRuleSet(api.CIDType(&cid))
+	var crs *api.RuleSet
+	err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		crs, err = client.FetchRuleSet(api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Rule set", cid)
+		}
+
+		return timeoutDiag(err, cid, schema.TimeoutRead)
 	}
 	rs.RuleSet = *crs
 
@@ -541,7 +549,7 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	if err = d.Set(ruleSetIfAttr, ifRules); err != nil {
 		if s, errM := json.MarshalIndent(ifRules, "", "  "); errM == nil {
-			log.Printf("%s", s)
+			tflog.Error(ctx, "Unable to store rule set rules", map[string]interface{}{"rules": string(s)})
 		}
 
 		return diag.FromErr(err)
@@ -568,7 +576,7 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	j, err := rs.UserJSON.MarshalJSON()
 	rj := json.RawMessage(string(j))
-	log.Printf("%s", string(rj))
+	tflog.Trace(ctx, "Read rule set user JSON", map[string]interface{}{"user_json": string(rj)})
 	if err == nil {
 		_ = d.Set(ruleSetUserJSONAttr, string(rj))
 	} else {
@@ -585,16 +593,17 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 func ruleSetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSet, d.Id())
 	rs := newRuleSet()
 
 	if err := rs.ParseConfig(d); err != nil {
-		return diag.FromErr(err)
+		return configDiag(err)
 	}
 
 	rs.CID = d.Id()
 
-	if err := rs.Update(ctxt); err != nil {
-		diag.FromErr(err)
+	if err := rs.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(err, rs.CID, schema.TimeoutUpdate)
 	}
 
 	return ruleSetRead(ctx, d, meta)
@@ -605,8 +614,13 @@ func ruleSetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}
 	var diags diag.Diagnostics
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteRuleSetByCID(api.CIDType(&cid)); err != nil {
-		return diag.FromErr(err)
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSet, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteRuleSetByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(err, cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -888,8 +902,12 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (rs *circonusRuleSet) Create(ctxt *providerContext) error {
-	crs, err := ctxt.client.CreateRuleSet(&rs.RuleSet)
+func (rs *circonusRuleSet) Create(ctx context.Context, ctxt *providerContext) error {
+	var crs *api.RuleSet
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.RuleSetPrefix, func(client *api.API) (err error) {
+		crs, err = client.CreateRuleSet(&rs.RuleSet)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -899,8 +917,11 @@ func (rs *circonusRuleSet) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (rs *circonusRuleSet) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateRuleSet(&rs.RuleSet)
+func (rs *circonusRuleSet) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, rs.CID, func(client *api.API) error {
+		_, err := client.UpdateRuleSet(&rs.RuleSet)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update rule set %s: %w", rs.CID, err)
 	}
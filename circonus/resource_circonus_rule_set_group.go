@@ -3,13 +3,14 @@ package circonus
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
 	"reflect"
 	"sort"
 	"strconv"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -22,8 +23,9 @@ func resourceRuleSetGroup() *schema.Resource {
 		DeleteContext: ruleSetGroupDelete,
 		// Exists: ruleSetGroupExists,
 		Importer: &schema.ResourceImporter{
-			State: importStatePassthroughUnescape,
+			StateContext: importStatePassthroughUnescape,
 		},
+		Timeouts: resourceTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"notify": {
 				Type:     schema.TypeSet,
@@ -138,11 +140,12 @@ func resourceRuleSetGroup() *schema.Resource {
 
 func ruleSetGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSetGroup, "")
 	var diags diag.Diagnostics
 
 	rsg := newRuleSetGroup()
 
-	if err := rsg.ParseConfig(d); err != nil {
+	if err := rsg.ParseConfig(ctx, d); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error parsing rule set group",
@@ -151,7 +154,7 @@ func ruleSetGroupCreate(ctx context.Context, d *schema.ResourceData, meta interf
 		return diags
 	}
 
-	if err := rsg.Create(ctxt); err != nil {
+	if err := rsg.Create(ctx, ctxt); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error creating rule set group",
@@ -172,8 +175,17 @@ func ruleSetGroupRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	var diags diag.Diagnostics
 
 	cid := d.Id()
-	rs, err := ctxt.client.FetchRuleSetGroup(api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSetGroup, cid)
+	var rs *api.RuleSetGroup
+	err := ctxt.apiRequest(ctx, http.MethodGet, cid, func(client *api.API) (err error) {
+		rs, err = client.FetchRuleSetGroup(api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Rule set group", cid)
+		}
+
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error fetching rule set group",
@@ -248,11 +260,12 @@ func ruleSetGroupRead(ctx context.Context, d *schema.ResourceData, meta interfac
 
 func ruleSetGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSetGroup, d.Id())
 	var diags diag.Diagnostics
 
 	rs := newRuleSetGroup()
 
-	if err := rs.ParseConfig(d); err != nil {
+	if err := rs.ParseConfig(ctx, d); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error parsing rule set group",
@@ -263,7 +276,7 @@ func ruleSetGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	rs.CID = d.Id()
 
-	if err := rs.Update(ctxt); err != nil {
+	if err := rs.Update(ctx, ctxt); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error updating rule set group",
@@ -280,7 +293,12 @@ func ruleSetGroupDelete(ctx context.Context, d *schema.ResourceData, meta interf
 	var diags diag.Diagnostics
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteRuleSetGroupByCID(api.CIDType(&cid)); err != nil {
+	ctx = ctxt.logContext(ctx, resourceTypeRuleSetGroup, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteRuleSetGroupByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Error deleting rule set group",
@@ -361,7 +379,7 @@ func (s *conditionSorter) Less(i, j int) bool {
 // ParseConfig reads Terraform config data and stores the information into a
 // Circonus RuleSetGroup object.  ParseConfig, ruleSetGroupRead(), and ruleSetGroupChecksum
 // must be kept in sync.
-func (rsg *circonusRuleSetGroup) ParseConfig(d *schema.ResourceData) error {
+func (rsg *circonusRuleSetGroup) ParseConfig(ctx context.Context, d *schema.ResourceData) error {
 	if v, found := d.GetOk("name"); found {
 		rsg.Name = v.(string)
 	}
@@ -418,7 +436,7 @@ func (rsg *circonusRuleSetGroup) ParseConfig(d *schema.ResourceData) error {
 		rsg.Tags = derefStringList(flattenSet(v.(*schema.Set)))
 	}
 
-	log.Printf("Parsed RuleSetGroup: %v\n", rsg)
+	tflog.Trace(ctx, "Parsed rule set group", map[string]interface{}{"rule_set_group": fmt.Sprintf("%v", rsg)})
 	// if err := rsg.Validate(); err != nil {
 	// 	return err
 	// }
@@ -426,8 +444,12 @@ func (rsg *circonusRuleSetGroup) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func (rsg *circonusRuleSetGroup) Create(ctxt *providerContext) error {
-	crs, err := ctxt.client.CreateRuleSetGroup(&rsg.RuleSetGroup)
+func (rsg *circonusRuleSetGroup) Create(ctx context.Context, ctxt *providerContext) error {
+	var crs *api.RuleSetGroup
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.RuleSetGroupPrefix, func(client *api.API) (err error) {
+		crs, err = client.CreateRuleSetGroup(&rsg.RuleSetGroup)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("create rule set group: %w", err)
 	}
@@ -437,8 +459,11 @@ func (rsg *circonusRuleSetGroup) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (rsg *circonusRuleSetGroup) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateRuleSetGroup(&rsg.RuleSetGroup)
+func (rsg *circonusRuleSetGroup) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, rsg.CID, func(client *api.API) error {
+		_, err := client.UpdateRuleSetGroup(&rsg.RuleSetGroup)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("update rule set group %s: %w", rsg.CID, err)
 	}
@@ -3,21 +3,22 @@ package circonus
 import (
 	"context"
 	"fmt"
-	"strings"
+	"net/http"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
-	workspaceTitleAttr        = "title"
-	workspaceDescriptionAttr  = "description"
-	workspaceFavoriteAttr     = "favorite"
-	workspaceNotesAttr        = "notes"
-	workspaceTagsAttr         = "tags"
-	workspaceGraphsAttr       = "graphs"
-	workspaceSmartQueriesAttr = "smart_queries"
+	worksheetTitleAttr        = "title"
+	worksheetDescriptionAttr  = "description"
+	worksheetFavoriteAttr     = "favorite"
+	worksheetNotesAttr        = "notes"
+	worksheetTagsAttr         = "tags"
+	worksheetGraphsAttr       = "graphs"
+	worksheetSmartQueriesAttr = "smart_queries"
 
 	queryNameAttr  = "name"
 	queryQueryAttr = "query"
@@ -25,13 +26,13 @@ const (
 )
 
 var worksheetDescriptions = attrDescrs{
-	workspaceTitleAttr:        "",
-	workspaceDescriptionAttr:  "",
-	workspaceFavoriteAttr:     "",
-	workspaceNotesAttr:        "",
-	workspaceTagsAttr:         "",
-	workspaceGraphsAttr:       "",
-	workspaceSmartQueriesAttr: "",
+	worksheetTitleAttr:        "",
+	worksheetDescriptionAttr:  "",
+	worksheetFavoriteAttr:     "",
+	worksheetNotesAttr:        "",
+	worksheetTagsAttr:         "",
+	worksheetGraphsAttr:       "",
+	worksheetSmartQueriesAttr: "",
 }
 
 var worksheetSmartQueryDescriptions = attrDescrs{
@@ -46,35 +47,35 @@ func resourceWorksheet() *schema.Resource {
 		ReadContext:   worksheetRead,
 		UpdateContext: worksheetUpdate,
 		DeleteContext: worksheetDelete,
-		Exists:        worksheetExists,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: convertToHelperSchema(worksheetDescriptions, map[schemaAttr]*schema.Schema{
-			workspaceTitleAttr: {
+			worksheetTitleAttr: {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 
-			workspaceDescriptionAttr: {
+			worksheetDescriptionAttr: {
 				Type:      schema.TypeString,
 				Optional:  true,
 				StateFunc: suppressWhitespace,
 			},
 
-			workspaceFavoriteAttr: {
+			worksheetFavoriteAttr: {
 				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  defaultWorkspaceFavourite,
+				Default:  defaultWorksheetFavorite,
 			},
 
-			workspaceNotesAttr: {
+			worksheetNotesAttr: {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 
-			workspaceGraphsAttr: {
+			worksheetGraphsAttr: {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
@@ -82,7 +83,7 @@ func resourceWorksheet() *schema.Resource {
 				},
 			},
 
-			workspaceSmartQueriesAttr: {
+			worksheetSmartQueriesAttr: {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Resource{
@@ -105,21 +106,22 @@ func resourceWorksheet() *schema.Resource {
 					}),
 				},
 			},
-			workspaceTagsAttr: tagMakeConfigSchema(workspaceTagsAttr),
+			worksheetTagsAttr: tagMakeConfigSchema(worksheetTagsAttr),
 		}),
 	}
 }
 
 func worksheetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeWorksheet, "")
 
 	g := newWorksheet()
 	if err := g.ParseConfig(d); err != nil {
-		return diag.FromErr(fmt.Errorf("parsing worksheet schema during create: %w", err))
+		return configDiag(fmt.Errorf("parsing worksheet schema during create: %w", err))
 	}
 
-	if err := g.Create(ctxt); err != nil {
-		return diag.FromErr(fmt.Errorf("creating worksheet: %w", err))
+	if err := g.Create(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("creating worksheet: %w", err), "", schema.TimeoutCreate)
 	}
 
 	d.SetId(g.CID)
@@ -133,24 +135,33 @@ func worksheetRead(ctx context.Context, d *schema.ResourceData, meta interface{}
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	w, err := loadWorksheet(ctxt, api.CIDType(&cid))
+	ctx = ctxt.logContext(ctx, resourceTypeWorksheet, cid)
+	w, err := loadWorksheet(ctx, ctxt, api.CIDType(&cid))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("load worksheet: %w", err))
+		if apiNotFound(err) {
+			return notFoundDiag(d, "Worksheet", cid)
+		}
+
+		return timeoutDiag(fmt.Errorf("load worksheet: %w", err), cid, schema.TimeoutRead)
+	}
+
+	if w.CID == "" {
+		return notFoundDiag(d, "Worksheet", cid)
 	}
 
 	d.SetId(w.CID)
 
-	_ = d.Set(workspaceTitleAttr, w.Title)
-	_ = d.Set(workspaceDescriptionAttr, w.Description)
-	_ = d.Set(workspaceFavoriteAttr, w.Favorite)
-	_ = d.Set(workspaceNotesAttr, w.Notes)
+	_ = d.Set(worksheetTitleAttr, w.Title)
+	_ = d.Set(worksheetDescriptionAttr, w.Description)
+	_ = d.Set(worksheetFavoriteAttr, w.Favorite)
+	_ = d.Set(worksheetNotesAttr, w.Notes)
 
-	if err := d.Set(workspaceGraphsAttr, worksheetGraphsToState(apiToWorksheetGraphs(w.Graphs))); err != nil {
-		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceTagsAttr, err))
+	if err := d.Set(worksheetGraphsAttr, worksheetGraphsToState(apiToWorksheetGraphs(w.Graphs))); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", worksheetGraphsAttr, err))
 	}
 
-	if err := d.Set(workspaceTagsAttr, tagsToState(apiToTags(w.Tags))); err != nil {
-		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceTagsAttr, err))
+	if err := d.Set(worksheetTagsAttr, tagsToState(apiToTags(w.Tags))); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", worksheetTagsAttr, err))
 	}
 
 	smartQueries := make([]map[string]interface{}, 0, len(w.SmartQueries))
@@ -165,43 +176,24 @@ func worksheetRead(ctx context.Context, d *schema.ResourceData, meta interface{}
 		smartQueries = append(smartQueries, newQuery)
 	}
 
-	if err := d.Set(workspaceSmartQueriesAttr, smartQueries); err != nil {
-		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceSmartQueriesAttr, err))
+	if err := d.Set(worksheetSmartQueriesAttr, smartQueries); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to store worksheet %q attribute: %w", worksheetSmartQueriesAttr, err))
 	}
 
 	return diags
 }
 
-func worksheetExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	ctxt := meta.(*providerContext)
-
-	cid := d.Id()
-	w, err := ctxt.client.FetchWorksheet(api.CIDType(&cid))
-	if err != nil {
-		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
-			return false, nil
-		}
-
-		return false, err
-	}
-
-	if w.CID == "" {
-		return false, nil
-	}
-
-	return true, nil
-}
-
 func worksheetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
+	ctx = ctxt.logContext(ctx, resourceTypeWorksheet, d.Id())
 	w := newWorksheet()
 	if err := w.ParseConfig(d); err != nil {
-		return diag.FromErr(fmt.Errorf("parse worksheet config: %w", err))
+		return configDiag(fmt.Errorf("parse worksheet config: %w", err))
 	}
 
 	w.CID = d.Id()
-	if err := w.Update(ctxt); err != nil {
-		return diag.FromErr(fmt.Errorf("unable to update worksheet %q: %w", d.Id(), err))
+	if err := w.Update(ctx, ctxt); err != nil {
+		return timeoutDiag(fmt.Errorf("unable to update worksheet %q: %w", d.Id(), err), d.Id(), schema.TimeoutUpdate)
 	}
 
 	return worksheetRead(ctx, d, meta)
@@ -213,8 +205,13 @@ func worksheetDelete(ctx context.Context, d *schema.ResourceData, meta interface
 	ctxt := meta.(*providerContext)
 
 	cid := d.Id()
-	if _, err := ctxt.client.DeleteWorksheetByCID(api.CIDType(&cid)); err != nil {
-		return diag.FromErr(fmt.Errorf("unable to delete worksheet %q: %w", d.Id(), err))
+	ctx = ctxt.logContext(ctx, resourceTypeWorksheet, cid)
+	err := ctxt.apiRequest(ctx, http.MethodDelete, cid, func(client *api.API) error {
+		_, err := client.DeleteWorksheetByCID(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return timeoutDiag(fmt.Errorf("unable to delete worksheet %q: %w", d.Id(), err), cid, schema.TimeoutDelete)
 	}
 
 	d.SetId("")
@@ -222,8 +219,12 @@ func worksheetDelete(ctx context.Context, d *schema.ResourceData, meta interface
 	return diags
 }
 
-func (w *circonusWorksheet) Create(ctxt *providerContext) error {
-	nw, err := ctxt.client.CreateWorksheet(&w.Worksheet)
+func (w *circonusWorksheet) Create(ctx context.Context, ctxt *providerContext) error {
+	var nw *api.Worksheet
+	err := ctxt.apiRequest(ctx, http.MethodPost, config.WorksheetPrefix, func(client *api.API) (err error) {
+		nw, err = client.CreateWorksheet(&w.Worksheet)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -233,8 +234,11 @@ func (w *circonusWorksheet) Create(ctxt *providerContext) error {
 	return nil
 }
 
-func (w *circonusWorksheet) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateWorksheet(&w.Worksheet)
+func (w *circonusWorksheet) Update(ctx context.Context, ctxt *providerContext) error {
+	err := ctxt.apiRequest(ctx, http.MethodPut, w.CID, func(client *api.API) error {
+		_, err := client.UpdateWorksheet(&w.Worksheet)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Unable to update worksheet %s: %w", w.CID, err)
 	}
@@ -242,9 +246,13 @@ func (w *circonusWorksheet) Update(ctxt *providerContext) error {
 	return nil
 }
 
-func loadWorksheet(ctxt *providerContext, cid api.CIDType) (circonusWorksheet, error) {
+func loadWorksheet(ctx context.Context, ctxt *providerContext, cid api.CIDType) (circonusWorksheet, error) {
 	var w circonusWorksheet
-	nw, err := ctxt.client.FetchWorksheet(cid)
+	var nw *api.Worksheet
+	err := ctxt.apiRequest(ctx, http.MethodGet, *cid, func(client *api.API) (err error) {
+		nw, err = client.FetchWorksheet(cid)
+		return err
+	})
 	if err != nil {
 		return circonusWorksheet{}, err
 	}
@@ -254,35 +262,35 @@ func loadWorksheet(ctxt *providerContext, cid api.CIDType) (circonusWorksheet, e
 }
 
 func (w *circonusWorksheet) ParseConfig(d *schema.ResourceData) error {
-	w.Title = d.Get(workspaceTitleAttr).(string)
+	w.Title = d.Get(worksheetTitleAttr).(string)
 
-	if v, ok := d.GetOk(workspaceDescriptionAttr); ok {
+	if v, ok := d.GetOk(worksheetDescriptionAttr); ok {
 		desc := v.(string)
 		w.Description = &desc
 	}
 
-	if v, ok := d.GetOk(workspaceNotesAttr); ok {
+	if v, ok := d.GetOk(worksheetNotesAttr); ok {
 		notes := v.(string)
 		w.Notes = &notes
 	}
 
-	if v, found := d.GetOk(workspaceTagsAttr); found {
+	if v, found := d.GetOk(worksheetTagsAttr); found {
 		w.Tags = derefStringList(flattenSet(v.(*schema.Set)))
 	}
 
-	if v, found := d.GetOk(workspaceGraphsAttr); found {
+	if v, found := d.GetOk(worksheetGraphsAttr); found {
 		graphs := derefStringList(flattenSet(v.(*schema.Set)))
-		var workspaceGraphs []api.WorksheetGraph
+		var worksheetGraphs []api.WorksheetGraph
 		for _, graph := range graphs {
-			workspaceGraphs = append(workspaceGraphs, api.WorksheetGraph{
+			worksheetGraphs = append(worksheetGraphs, api.WorksheetGraph{
 				GraphCID: graph,
 			})
 		}
 
-		w.Graphs = workspaceGraphs
+		w.Graphs = worksheetGraphs
 	}
 
-	if v, found := d.GetOk(workspaceSmartQueriesAttr); found {
+	if v, found := d.GetOk(worksheetSmartQueriesAttr); found {
 		queriesList := v.(*schema.Set).List()
 		smaryQueries := make([]api.WorksheetSmartQuery, 0, len(queriesList))
 
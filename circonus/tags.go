@@ -1,7 +1,6 @@
 package circonus
 
 import (
-	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,7 +12,7 @@ type (
 )
 
 // tagMakeConfigSchema returns a schema pointer to the necessary tag structure.
-func tagMakeConfigSchema(tagAttrName schemaAttr) *schema.Schema { //nolint:unparam
+func tagMakeConfigSchema(tagAttrName schemaAttr) *schema.Schema {
 	return &schema.Schema{
 		Type:     schema.TypeSet,
 		Optional: true,
@@ -26,28 +25,20 @@ func tagMakeConfigSchema(tagAttrName schemaAttr) *schema.Schema { //nolint:unpar
 
 func (t circonusTag) Category() string {
 	tagInfo := strings.SplitN(string(t), ":", 2)
-	switch len(tagInfo) {
-	case 1:
+	if len(tagInfo) == 1 {
 		return strings.ToLower(string(t))
-	case 2:
-		return strings.ToLower(tagInfo[0])
-	default:
-		log.Printf("[ERROR]: Invalid category on tag %q", string(t))
-		return ""
 	}
+
+	return strings.ToLower(tagInfo[0])
 }
 
 func (t circonusTag) Value() string {
 	tagInfo := strings.SplitN(string(t), ":", 2)
-	switch len(tagInfo) {
-	case 1:
-		return ""
-	case 2:
-		return strings.ToLower(tagInfo[1])
-	default:
-		log.Printf("[ERROR]: Invalid value on tag %q", string(t))
+	if len(tagInfo) == 1 {
 		return ""
 	}
+
+	return strings.ToLower(tagInfo[1])
 }
 
 func tagsToState(tags circonusTags) *schema.Set {
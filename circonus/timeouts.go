@@ -0,0 +1,74 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultCirconusCreateTimeout = 10 * time.Minute
+	defaultCirconusReadTimeout   = 5 * time.Minute
+	defaultCirconusUpdateTimeout = 10 * time.Minute
+	defaultCirconusDeleteTimeout = 5 * time.Minute
+)
+
+// apiTimeoutError is returned by apiRequest when the operation's deadline
+// passes, or it is cancelled, before the Circonus API responds.
+type apiTimeoutError struct {
+	err     error
+	method  string
+	path    string
+	elapsed time.Duration
+}
+
+func (e *apiTimeoutError) Error() string {
+	if errors.Is(e.err, context.DeadlineExceeded) {
+		return fmt.Sprintf("Circonus API request %s %s did not complete before the operation timed out (waited %s)", e.method, e.path, e.elapsed.Round(time.Millisecond))
+	}
+
+	return fmt.Sprintf("Circonus API request %s %s was cancelled: %v", e.method, e.path, e.err)
+}
+
+func (e *apiTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// resourceTimeouts returns the default create, read, update and delete
+// timeouts shared by every resource.  Users can override them with a
+// `timeouts` block.
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultCirconusCreateTimeout),
+		Read:   schema.DefaultTimeout(defaultCirconusReadTimeout),
+		Update: schema.DefaultTimeout(defaultCirconusUpdateTimeout),
+		Delete: schema.DefaultTimeout(defaultCirconusDeleteTimeout),
+	}
+}
+
+// timeoutDiag converts err into diagnostics.  When err is an apiTimeoutError
+// the diagnostic names the object whose request was abandoned and the
+// timeout that should be raised.
+func timeoutDiag(err error, cid, timeoutKey string) diag.Diagnostics {
+	var timeoutErr *apiTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		return diag.FromErr(err)
+	}
+
+	obj := cid
+	if obj == "" {
+		obj = timeoutErr.path
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Timed out waiting on %s", obj),
+			Detail:   fmt.Sprintf("%s. The request may still complete on the Circonus side. Increase the %q timeout in the resource's timeouts block if the API is expected to be this slow.", timeoutErr.Error(), timeoutKey),
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_APIRequestTimeout(t *testing.T) {
+	ctxt := &providerContext{apiConfig: api.Config{TokenKey: "test"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	err := ctxt.apiRequest(ctx, http.MethodGet, "/check_bundle/1234", func(client *api.API) error {
+		<-unblock
+		return nil
+	})
+
+	var timeoutErr *apiTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected apiTimeoutError, got %v", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+
+	diags := timeoutDiag(err, "/check_bundle/1234", schema.TimeoutRead)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	if !strings.Contains(diags[0].Summary, "/check_bundle/1234") {
+		t.Errorf("expected summary to name the CID, got %q", diags[0].Summary)
+	}
+}
+
+func Test_APIRequestTimeoutPost(t *testing.T) {
+	ctxt := &providerContext{apiConfig: api.Config{TokenKey: "test"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var created bool
+	err := ctxt.apiRequest(ctx, http.MethodPost, "/check_bundle", func(client *api.API) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		created = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the POST to be waited on, got %v", err)
+	}
+
+	if !created {
+		t.Errorf("expected the POST to complete before apiRequest returned")
+	}
+}
+
+func Test_TimeoutDiagPassthrough(t *testing.T) {
+	diags := timeoutDiag(errors.New("API response code 500: boom"), "/graph/1", schema.TimeoutCreate)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	if diags[0].Summary != "API response code 500: boom" {
+		t.Errorf("unexpected summary %q", diags[0].Summary)
+	}
+}
@@ -1,9 +1,9 @@
 package circonus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -31,7 +31,7 @@ func convertToHelperSchema(descrs attrDescrs, in map[schemaAttr]*schema.Schema)
 
 			v.Description = string(descr)
 		} else {
-			log.Printf("[WARN] PROVIDER BUG: Unable to find description for attr %q", k)
+			panic(fmt.Sprintf("PROVIDER BUG: Unable to find description for attr %q", k))
 		}
 
 		out[string(k)] = v
@@ -80,10 +80,11 @@ func flattenSet(s *schema.Set) []*string {
 	return flattenList(s.List())
 }
 
-// importStatePassthrough is an implementation of StateFunc that can be used to
-// simply pass the ID directly through. This should be used only in the case
-// that an ID-only refresh is possible.  The ID is url.PathUnescape()'ed.
-func importStatePassthroughUnescape(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+// importStatePassthroughUnescape is an implementation of StateContextFunc
+// that can be used to simply pass the ID directly through. This should be used
+// only in the case that an ID-only refresh is possible.  The ID is
+// url.PathUnescape()'ed.
+func importStatePassthroughUnescape(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	// Ignore any path unescape issues
 	cid, _ := url.PathUnescape(d.Id())
 
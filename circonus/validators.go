@@ -12,7 +12,6 @@ import (
 )
 
 var (
-	knownCheckTypes        map[circonusCheckType]struct{}
 	knownContactMethods    map[contactMethods]struct{}
 	userContactMethods     map[contactMethods]struct{}
 	externalContactMethods map[contactMethods]struct{}
@@ -20,24 +19,6 @@ var (
 )
 
 func init() {
-	checkTypes := []circonusCheckType{
-		"caql", "cim", "circonuswindowsagent", "circonuswindowsagent,nad",
-		"collectd", "composite", "dcm", "dhcp", "dns", "elasticsearch",
-		"external", "ganglia", "googleanalytics", "haproxy", "http",
-		"http,apache", "httptrap", "imap", "jmx", "json", "json,couchdb",
-		"json,mongodb", "json,nad", "json,riak", "ldap", "memcached",
-		"munin", "mysql", "newrelic_rpm", "nginx", "nrpe", "ntp",
-		"oracle", "ping_icmp", "pop3", "postgres", "redis", "resmon",
-		"smtp", "snmp", "snmp,momentum", "sqlserver", "ssh2", "statsd",
-		"tcp", "varnish", "keynote", "keynote_pulse", "cloudwatch",
-		"ec_console", "mongodb",
-	}
-
-	knownCheckTypes = make(map[circonusCheckType]struct{}, len(checkTypes))
-	for _, k := range checkTypes {
-		knownCheckTypes[k] = struct{}{}
-	}
-
 	userMethods := []contactMethods{"email", "sms", "xmpp"}
 	externalMethods := []contactMethods{"slack"}
 
@@ -57,27 +38,27 @@ func init() {
 }
 
 func validateCheckType(v interface{}, key string) (warnings []string, errors []error) {
-	if _, ok := knownCheckTypes[circonusCheckType(v.(string))]; !ok {
-		warnings = append(warnings, fmt.Sprintf("Possibly unsupported check type: %s", v.(string)))
+	if _, ok := checkTypeForAPIType(v.(string)); !ok {
+		warnings = append(warnings, fmt.Sprintf("Check type %q has no dedicated block and must be configured with a %q block", v.(string), checkRawAttr))
 	}
 
 	return warnings, errors
 }
 
-func validateCheckCloudWatchDimmensions(v interface{}, key string) (warnings []string, errors []error) {
-	validDimmensionName := regexp.MustCompile(`^[\S]+$`)
-	validDimmensionValue := regexp.MustCompile(`^[\S]+$`)
+func validateCheckCloudWatchDimensions(v interface{}, key string) (warnings []string, errors []error) {
+	validDimensionName := regexp.MustCompile(`^[\S]+$`)
+	validDimensionValue := regexp.MustCompile(`^[\S]+$`)
 
-	dimmensions := v.(map[string]interface{})
-	for k, vRaw := range dimmensions {
-		if !validDimmensionName.MatchString(k) {
-			errors = append(errors, fmt.Errorf("Invalid CloudWatch Dimmension Name specified: %q", k))
+	dimensions := v.(map[string]interface{})
+	for k, vRaw := range dimensions {
+		if !validDimensionName.MatchString(k) {
+			errors = append(errors, fmt.Errorf("Invalid CloudWatch Dimension Name specified: %q", k))
 			continue
 		}
 
 		v := vRaw.(string)
-		if !validDimmensionValue.MatchString(v) {
-			errors = append(errors, fmt.Errorf("Invalid value for CloudWatch Dimmension %q specified: %q", k, v))
+		if !validDimensionValue.MatchString(v) {
+			errors = append(errors, fmt.Errorf("Invalid value for CloudWatch Dimension %q specified: %q", k, v))
 		}
 	}
 
@@ -87,7 +68,7 @@ func validateCheckCloudWatchDimmensions(v interface{}, key string) (warnings []s
 func validateContactGroup(cg *api.ContactGroup) error {
 	for i := range cg.Reminders {
 		if cg.Reminders[i] != 0 && cg.AggregationWindow > cg.Reminders[i] {
-			return fmt.Errorf("severity %d reminder (%ds) is shorter than the aggregation window (%ds)", i+1, cg.Reminders[i], cg.AggregationWindow)
+			return attrErrorf(attrPath(contactAlertOptionAttr), "severity %d reminder (%ds) is shorter than the aggregation window (%ds)", i+1, cg.Reminders[i], cg.AggregationWindow)
 		}
 	}
 
@@ -97,7 +78,7 @@ func validateContactGroup(cg *api.ContactGroup) error {
 			continue
 		case cg.Escalations[severityIndex].After > 0 && cg.Escalations[severityIndex].ContactGroupCID == "",
 			cg.Escalations[severityIndex].After == 0 && cg.Escalations[severityIndex].ContactGroupCID != "":
-			return fmt.Errorf("severity %d escalation requires both and %s and %s be set", severityIndex+1, contactEscalateToAttr, contactEscalateAfterAttr)
+			return attrErrorf(attrPath(contactAlertOptionAttr), "severity %d escalation requires both %s and %s be set", severityIndex+1, contactEscalateToAttr, contactEscalateAfterAttr)
 		}
 	}
 
@@ -260,6 +241,16 @@ func validateIntMax(attrName schemaAttr, max int) func(v interface{}, key string
 	}
 }
 
+func validateJSONPath(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		if err := parseJSONPath(v.(string)); err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified: %w", attrName, err))
+		}
+
+		return warnings, errors
+	}
+}
+
 func validateMetricType(v interface{}, key string) (warnings []string, errors []error) {
 	value := v.(string)
 	switch value {
@@ -271,6 +262,17 @@ func validateMetricType(v interface{}, key string) (warnings []string, errors []
 	return warnings, errors
 }
 
+func validateMetricStreamTag(v interface{}, key string) (warnings []string, errors []error) {
+	tag := v.(string)
+	if !strings.ContainsRune(tag, ':') {
+		errors = append(errors, fmt.Errorf("stream tag %q is missing a category", tag))
+	} else if category, _ := splitMetricStreamTag(tag); strings.TrimSpace(category) == "" {
+		errors = append(errors, fmt.Errorf("stream tag %q has an empty category", tag))
+	}
+
+	return warnings, errors
+}
+
 func validateRegexp(attrName schemaAttr, reString string) func(v interface{}, key string) (warnings []string, errors []error) {
 	re := regexp.MustCompile(reString)
 
@@ -283,6 +285,16 @@ func validateRegexp(attrName schemaAttr, reString string) func(v interface{}, ke
 	}
 }
 
+func validateRegexpSyntax(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		if _, err := regexp.Compile(v.(string)); err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): %w", attrName, v.(string), err))
+		}
+
+		return warnings, errors
+	}
+}
+
 func validateTag(v interface{}, key string) (warnings []string, errors []error) {
 	tag := v.(string)
 	if !strings.ContainsRune(tag, ':') {